@@ -0,0 +1,69 @@
+package uargs
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// AuditEntry is a historical invocation that fails to parse, as reported by Audit
+type AuditEntry struct {
+	// Line is the line number of the invocation in the log
+	Line int
+	// CommandLine is the invocation as it appears in the log
+	CommandLine string
+	// Err is the error the parser returns for it
+	Err error
+}
+
+// Audit parses a log of historical invocations, one command line per line
+// without the program name, and returns those that p rejects. Blank lines and
+// lines starting with # are skipped. When baseline is not nil, only
+// invocations that baseline accepts are reported, so p can hold proposed
+// stricter definitions and the report lists exactly what they would break.
+// Neither parser's last result is changed.
+//
+// Example:
+//
+//	entries, err := strict.Audit(logFile, current)
+//	for _, e := range entries {
+//		fmt.Printf("line %d: %s: %v\n", e.Line, e.CommandLine, e.Err)
+//	}
+func (p *Parser) Audit(r io.Reader, baseline *Parser) ([]AuditEntry, error) {
+	if p == nil {
+		return nil, ErrNilParser
+	}
+	defer p.keepResult()()
+	if baseline != nil {
+		defer baseline.keepResult()()
+	}
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		argv, err := Split(line)
+		if err == nil {
+			if baseline != nil {
+				if _, err := baseline.parse(argv, nil); err != nil {
+					continue
+				}
+			}
+			_, err = p.parse(argv, nil)
+		}
+		if err != nil {
+			entries = append(entries, AuditEntry{Line: n, CommandLine: line, Err: err})
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// keepResult saves the state of the last parse and returns a function that
+// restores it
+func (p *Parser) keepResult() func() {
+	result, parsed, done := p.result, p.parsed, p.done
+	return func() { p.result, p.parsed, p.done = result, parsed, done }
+}
@@ -0,0 +1,60 @@
+package uargs_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestAudit tests reporting historical invocations that stricter definitions reject
+func TestAudit(t *testing.T) {
+	current := uargs.NewParser([]uargs.ArgDef{
+		{Name: "name", Usage: "Name", Type: uargs.String},
+		{Name: "level", Usage: "Level", Type: uargs.Int},
+	})
+	strict := uargs.NewParser([]uargs.ArgDef{
+		{Name: "name", Usage: "Name", Type: uargs.String, MinLen: 1, Required: true},
+		{Name: "level", Usage: "Level", Type: uargs.Int, Min: uargs.Bound(0), Max: uargs.Bound(5)},
+	})
+	if _, err := strict.ParseArgs([]string{"--name", "kept"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	log := `# invocations from last month
+--name web --level 3
+--name web --level 9
+--level 1
+
+--name "" --level 2
+--name web --level high
+--name 'unterminated
+`
+	entries, err := strict.Audit(strings.NewReader(log), current)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := map[int]string{
+		3: "--level value 9 out of range [0, 5]",
+		4: "missing required argument --name",
+		6: "--name must not be empty",
+		8: "unterminated quote '",
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("Expected %d entries, got %+v", len(want), entries)
+	}
+	for _, e := range entries {
+		if e.Err.Error() != want[e.Line] {
+			t.Errorf("line %d (%s): expected %q, got %v", e.Line, e.CommandLine, want[e.Line], e.Err)
+		}
+	}
+
+	// Without a baseline, every rejected invocation is reported
+	entries, _ = strict.Audit(strings.NewReader(log), nil)
+	if len(entries) != 5 {
+		t.Errorf("Expected 5 entries without a baseline, got %+v", entries)
+	}
+	if v, _ := strict.Result().Get("name"); v != "kept" {
+		t.Errorf("Expected the last result to be kept, got %v", v)
+	}
+}
@@ -0,0 +1,56 @@
+package uargs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// RunBatch reads one command line per line from r, splits it with Split, parses
+// it, and passes the result to run. Flags given to the process itself (by the
+// last successful Parse) are shared by every line, which may override them.
+// Blank lines and lines starting with # are skipped. Processing stops at the
+// first error, which is returned with its line number. While run executes,
+// Result describes the current line; afterwards the parser's state is restored.
+// Lines are only parsed, like with Parse: the Run handlers and hooks of the
+// commands they select are not called, and run is called instead, with the
+// values of the root parser. Result().CommandPath() tells which command a line
+// selected. RunREPL runs lines like Execute.
+//
+// Example:
+//
+//	if _, err := parser.Parse(); err != nil {
+//		log.Fatal(err)
+//	}
+//	err := parser.RunBatch(os.Stdin, func(parsed map[string]interface{}) error {
+//		return apply(parsed)
+//	})
+func (p *Parser) RunBatch(r io.Reader, run func(parsed map[string]interface{}) error) error {
+	if p == nil {
+		return ErrNilParser
+	}
+	// Lines only see the process flags, never values given on earlier lines
+	defer p.keepResult()()
+	shared := p.Result().givenValues()
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		argv, err := Split(line)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+		parsed, err := p.parse(argv, shared)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+		if err := run(parsed); err != nil {
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,81 @@
+package uargs_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestRunBatch tests parsing one command line per input line with shared flags
+func TestRunBatch(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "region", Usage: "Region", Type: uargs.String, Default: "us"},
+		{Name: "user", Usage: "User name", Type: uargs.String, Required: true},
+		{Name: "count", Usage: "Count", Type: uargs.Int},
+	}
+	parser := uargs.NewParser(args)
+	if _, err := parser.ParseArgs([]string{"--region", "eu", "--user", "root"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	input := `
+# comment
+--user "Jane Doe" --count 2
+--region ap --user bob
+--count 5
+`
+	var got []map[string]interface{}
+	err := parser.RunBatch(strings.NewReader(input), func(parsed map[string]interface{}) error {
+		got = append(got, parsed)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("Expected 3 runs, got %d", len(got))
+	}
+	if got[0]["user"] != "Jane Doe" || got[0]["region"] != "eu" || got[0]["count"] != 2 {
+		t.Errorf("Expected line values over shared flags, got %v", got[0])
+	}
+	if got[1]["region"] != "ap" || got[1]["user"] != "bob" {
+		t.Errorf("Expected shared region to be overridden, got %v", got[1])
+	}
+	if got[2]["user"] != "root" || got[2]["count"] != 5 {
+		t.Errorf("Expected shared user to satisfy Required, got %v", got[2])
+	}
+
+	err = parser.RunBatch(strings.NewReader("--count 1\n--count 1 --count 2\n"), func(map[string]interface{}) error { return nil })
+	if err == nil || !strings.HasPrefix(err.Error(), "line 2: ") {
+		t.Errorf("Expected parse error on line 2, got %v", err)
+	}
+
+	failed := errors.New("failed")
+	err = parser.RunBatch(strings.NewReader("--count 1\n"), func(map[string]interface{}) error { return failed })
+	if err == nil || err.Error() != "line 1: failed" {
+		t.Errorf("Expected run error on line 1, got %v", err)
+	}
+
+	err = parser.RunBatch(strings.NewReader(`--user "x`), func(map[string]interface{}) error { return nil })
+	if err == nil || !strings.HasPrefix(err.Error(), "line 1: unterminated quote") {
+		t.Errorf("Expected split error on line 1, got %v", err)
+	}
+
+	// Lines selecting a command are passed to run, without calling its Run handler
+	ran := false
+	parser.AddCommand(uargs.Command{Name: "sync", Usage: "Sync", Run: func(context.Context, map[string]interface{}) error {
+		ran = true
+		return nil
+	}})
+	var paths []string
+	err = parser.RunBatch(strings.NewReader("sync\n--count 1\n"), func(map[string]interface{}) error {
+		paths = append(paths, strings.Join(parser.Result().CommandPath(), " "))
+		return nil
+	})
+	if err != nil || ran || len(paths) != 2 || paths[0] != "sync" || paths[1] != "" {
+		t.Errorf("Expected sync to be passed to run without its Run handler, got %q, ran=%v (%v)", paths, ran, err)
+	}
+}
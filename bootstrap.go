@@ -0,0 +1,96 @@
+package uargs
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// Bootstrap parses only the named arguments from argv, such as --log-level or
+// the address of a secrets service, so that they can set up logging or the
+// sources of the full parse that follows. Other flags and their values,
+// subcommand names, and positional values are skipped, and unknown flags are
+// tolerated. Persistent arguments are found after subcommand names too. The
+// named arguments fall back to their environment variables, config files,
+// other sources, and Default as in a full parse, but required arguments,
+// constraints, and validators are not checked. The --config and --profile
+// arguments of WithConfigFlag and WithProfiles are always included. The last
+// result of p is left unchanged.
+//
+// Example:
+//
+//	boot, err := parser.Bootstrap(os.Args[1:], "log-level", "vault-addr")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	setupLogging(boot["log-level"].(string))
+//	vault = connect(boot["vault-addr"].(string)) // Used by a source added with WithSource
+//	parsed, err := parser.Parse()
+func (p *Parser) Bootstrap(argv []string, names ...string) (map[string]interface{}, error) {
+	if p == nil {
+		return nil, ErrNilParser
+	}
+	if p.defErr != nil {
+		return nil, p.defErr
+	}
+	// A copy of p resolving only the named arguments keeps the last result
+	// intact
+	b := *p
+	b.parsed = make(map[string]interface{})
+	b.result, b.done = nil, false
+	b.only = map[string]bool{ConfigName: p.configFlag, ProfileName: p.profileFlag}
+	for _, name := range names {
+		if _, ok := p.defs[name]; !ok {
+			return nil, fmt.Errorf("unknown argument --%s", name)
+		}
+		b.only[name] = true
+	}
+	if err := b.scanBootstrap(argv); err != nil {
+		return nil, classify(usageErrorKind, err)
+	}
+	if err := b.fillMissing(); err != nil {
+		return nil, classify(usageErrorKind, err)
+	}
+	return b.parsed, nil
+}
+
+// scanBootstrap stores the values of the arguments resolved by Bootstrap that
+// are given in argv, skipping everything else. The flags of the subcommands
+// named in argv are recognized so that their values are skipped too.
+func (p *Parser) scanBootstrap(argv []string) error {
+	used, inherited := make(map[string]bool), make(map[string]bool)
+	q := p // Parser of the innermost subcommand named so far
+	for i := 0; i < len(argv) && argv[i] != "--"; i++ {
+		arg := argv[i]
+		name, isFlag := "", strings.HasPrefix(arg, "-") && !q.isValue(arg)
+		switch {
+		case strings.HasPrefix(arg, "--"):
+			name = arg[2:]
+		case isFlag:
+			name = q.shortToLong[arg[1:]]
+		default:
+			if c := q.findCommand(arg); c != nil {
+				// Persistent arguments may be given again after the command
+				q, inherited = c, maps.Clone(used)
+			}
+			continue
+		}
+		def, ok := q.defs[name]
+		if !ok {
+			continue
+		}
+		if p.only[name] && (q == p || q.inheritedFlags[name]) {
+			if def.EnvOnly {
+				return argError(def, fmt.Errorf("%s cannot be given on the command line%s", flagName(def), p.settingHint(def)))
+			}
+			if err := p.consume(argv, &i, def, used, inherited); err != nil {
+				return argError(def, err)
+			}
+			continue
+		}
+		for j := 0; (maxArgs(def) < 0 || j < maxArgs(def)) && i+1 < len(argv) && q.isValue(argv[i+1]); j++ {
+			i++
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package uargs_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestBootstrap tests parsing bootstrap flags before the full parse
+func TestBootstrap(t *testing.T) {
+	config := writeConfig(t, "config.json", `{"vault-addr": "vault.example.com", "port": 8080}`)
+	var vault string
+	source := uargs.ValueSourceFunc(func(path []string, def uargs.ArgDef) (uargs.SourceValue, bool, error) {
+		if vault == "" || def.Name != "token" {
+			return uargs.SourceValue{}, false, nil
+		}
+		return uargs.SourceValue{Args: []string{"token-from-" + vault}}, true, nil
+	})
+	parser := uargs.NewParser([]uargs.ArgDef{
+		{Name: "log-level", Short: "l", Usage: "Log level", Type: uargs.String, Choices: []string{"debug", "info", "warn"}, Default: "info", Persistent: true},
+		{Name: "vault-addr", Usage: "Vault address", Type: uargs.String},
+		{Name: "tags", Usage: "Tags", Type: uargs.String, NumArgs: 2},
+		{Name: "port", Usage: "Port", Type: uargs.Int, Required: true},
+		{Name: "token", Usage: "Token", Type: uargs.String, Required: true},
+	}, uargs.WithConfigFile(config), uargs.WithSource(source))
+	parser.AddCommand(uargs.Command{Name: "serve", Usage: "Serve", Args: []uargs.ArgDef{
+		{Name: "workers", Short: "w", Usage: "Workers", Type: uargs.Int},
+	}})
+	argv := []string{"--tags", "a", "--log-level", "--unknown", "serve", "-w", "4", "-l", "debug"}
+
+	// Test case 1: Only the named flags are parsed, from argv and other sources
+	boot, err := parser.Bootstrap(argv, "log-level", "vault-addr")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(boot) != 2 || boot["log-level"] != "debug" || boot["vault-addr"] != "vault.example.com" {
+		t.Errorf("Expected log-level=debug and vault-addr from the config file, got %v", boot)
+	}
+	if boot, err = parser.Bootstrap(nil, "log-level"); err != nil || boot["log-level"] != "info" {
+		t.Errorf("Expected the default log level, got %v (%v)", boot, err)
+	}
+
+	// Test case 2: The full parse uses the sources set up from the bootstrap values
+	boot, _ = parser.Bootstrap(argv, "vault-addr")
+	vault = boot["vault-addr"].(string)
+	parsed, err := parser.ParseArgs([]string{"serve", "-w", "4"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parsed["token"] != "token-from-vault.example.com" || parsed["port"] != 8080 {
+		t.Errorf("Expected the token from the vault source and port 8080, got %v", parsed)
+	}
+
+	// Test case 3: Bootstrap leaves the last result unchanged
+	if _, err := parser.Bootstrap([]string{"-l", "warn"}, "log-level"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v, _, _ := parser.Get("log-level"); v != "info" {
+		t.Errorf("Expected the parsed log-level to stay info, got %v", v)
+	}
+
+	// Test case 4: Errors in bootstrap flags and unknown names are reported
+	if _, err := parser.Bootstrap([]string{"--log-level", "loud"}, "log-level"); err == nil || !strings.Contains(err.Error(), "--log-level must be one of") {
+		t.Errorf("Expected an error about the invalid choice, got %v", err)
+	}
+	if _, err := parser.Bootstrap(nil, "verbose"); err == nil || err.Error() != "unknown argument --verbose" {
+		t.Errorf("Expected an error about --verbose, got %v", err)
+	}
+}
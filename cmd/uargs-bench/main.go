@@ -0,0 +1,200 @@
+// Command uargs-bench profiles argument parsing, to guide performance work on
+// the parser. It parses either generated definitions with a synthetic command
+// line, or a corpus of real command lines, many times in a loop and writes
+// pprof profiles that can be viewed as flame graphs:
+//
+//	go run ./cmd/uargs-bench --defs 500 --cpuprofile cpu.out
+//	go tool pprof -http=:8080 cpu.out
+//
+// A corpus file holds one command line per line, without the program name, or
+// lines in the tab-separated tool, command line, and expectation format of
+// testdata/corpus.txt. Definitions are inferred from the flags it contains.
+// Lines expected to fail, and lines that do not parse with the inferred
+// definitions, such as lines repeating a flag, are reported and skipped.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"time"
+
+	"github.com/utsav-56/uargs"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "uargs-bench:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	parser := uargs.NewParser([]uargs.ArgDef{
+		{Name: "defs", Short: "n", Usage: "Number of generated argument definitions", Type: uargs.Int, Default: 200, Min: uargs.Bound(1)},
+		{Name: "iterations", Short: "i", Usage: "Number of times each command line is parsed", Type: uargs.Int, Default: 10000, Min: uargs.Bound(1)},
+		{Name: "corpus", Usage: "File of command lines to parse instead of generated ones", Type: uargs.File, Readable: true},
+		{Name: "cpuprofile", Usage: "Write a CPU profile to this file", Type: uargs.File, Writable: true},
+		{Name: "memprofile", Usage: "Write an allocation profile to this file", Type: uargs.File, Writable: true},
+	})
+	parsed, err := parser.Parse()
+	if err != nil {
+		return fmt.Errorf("%v\n\n%s", err, parser.Usage())
+	}
+
+	var defs []uargs.ArgDef
+	var lines [][]string
+	if path, ok := parsed["corpus"].(string); ok {
+		if defs, lines, err = loadCorpus(path); err != nil {
+			return err
+		}
+	} else {
+		defs, lines = generate(parsed["defs"].(int))
+	}
+	target := uargs.NewParser(defs, uargs.WithWarnings(nil))
+	var valid [][]string
+	for n, argv := range lines {
+		if _, err := target.ParseArgs(argv); err != nil {
+			fmt.Fprintf(os.Stderr, "uargs-bench: skipping command line %d: %v\n", n+1, err)
+			continue
+		}
+		valid = append(valid, argv)
+	}
+	if len(valid) == 0 {
+		return errors.New("no command line parses")
+	}
+	lines = valid
+
+	if path, ok := parsed["cpuprofile"].(string); ok {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := pprof.StartCPUProfile(f); err != nil {
+			return err
+		}
+		defer pprof.StopCPUProfile()
+	}
+
+	iterations := parsed["iterations"].(int)
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	for range iterations {
+		for _, argv := range lines {
+			target.ParseArgs(argv)
+		}
+	}
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	ops := uint64(iterations * len(lines))
+	fmt.Printf("%d definitions, %d command lines, %d parses\n", len(defs), len(lines), ops)
+	fmt.Printf("%v/op  %d B/op  %d allocs/op\n",
+		elapsed/time.Duration(ops), (after.TotalAlloc-before.TotalAlloc)/ops, (after.Mallocs-before.Mallocs)/ops)
+
+	if path, ok := parsed["memprofile"].(string); ok {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return pprof.Lookup("allocs").WriteTo(f, 0)
+	}
+	return nil
+}
+
+// generate returns n definitions of mixed types and a command line that gives
+// every one of them
+func generate(n int) ([]uargs.ArgDef, [][]string) {
+	var defs []uargs.ArgDef
+	var argv []string
+	for i := range n {
+		def := uargs.ArgDef{Name: fmt.Sprintf("flag-%04d", i), Usage: fmt.Sprintf("Generated flag %d", i)}
+		switch i % 4 {
+		case 0:
+			def.Type = uargs.String
+			argv = append(argv, "--"+def.Name, "value")
+		case 1:
+			def.Type = uargs.Int
+			def.Min, def.Max = uargs.Bound(0), uargs.Bound(1000)
+			argv = append(argv, "--"+def.Name, "42")
+		case 2:
+			def.Type = uargs.Float
+			def.NumArgs = 2
+			argv = append(argv, "--"+def.Name, "1.5", "-2.5")
+		case 3:
+			def.Type = uargs.String
+			def.Choices = []string{"json", "text", "yaml"}
+			def.IgnoreCase = true
+			argv = append(argv, "--"+def.Name, "YAML")
+		}
+		if i < 26 {
+			def.Short = string(rune('a' + i))
+			argv[len(argv)-1-max(def.NumArgs, 1)] = "-" + def.Short
+		}
+		defs = append(defs, def)
+	}
+	return defs, [][]string{argv}
+}
+
+// loadCorpus reads the command lines of a corpus file and infers a definition
+// for every flag used in them. Inferred flags accept any number of values.
+// Lines with an "error: " expectation and lines that cannot be split are
+// skipped.
+func loadCorpus(path string) ([]uargs.ArgDef, [][]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	var defs []uargs.ArgDef
+	seen := make(map[string]bool)
+	var lines [][]string
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if fields := strings.Split(line, "\t"); len(fields) == 3 {
+			if strings.HasPrefix(fields[2], "error: ") {
+				continue
+			}
+			line = fields[1]
+		}
+		argv, err := uargs.Split(line)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "uargs-bench: skipping line %d of %s: %v\n", n, path, err)
+			continue
+		}
+		for _, tok := range argv {
+			if !strings.HasPrefix(tok, "-") || tok == "-" || seen[tok] {
+				continue
+			}
+			seen[tok] = true
+			def := uargs.ArgDef{Usage: "Corpus flag", Type: uargs.String, MaxArgs: -1}
+			if long, ok := strings.CutPrefix(tok, "--"); ok {
+				def.Name = long
+			} else {
+				def.Name = "short-" + tok[1:]
+				def.Short = tok[1:]
+			}
+			defs = append(defs, def)
+		}
+		lines = append(lines, argv)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	if len(lines) == 0 {
+		return nil, nil, errors.New("corpus has no command lines")
+	}
+	return defs, lines, nil
+}
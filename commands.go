@@ -0,0 +1,229 @@
+package uargs
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RunFunc is the signature of Run and its hooks. ctx is the context given to
+// ExecuteContext, carrying the command's Result (see FromContext), and parsed
+// holds the values of the command that runs.
+type RunFunc func(ctx context.Context, parsed map[string]interface{}) error
+
+// Middleware wraps the execution of a command, calling next to continue it.
+// It may act before and after next, change ctx, or return without calling it.
+type Middleware func(next RunFunc) RunFunc
+
+// Command defines a subcommand, such as "add" in "mytool add --name x", with
+// its own arguments, usage text, and parse result. Commands can be nested by
+// adding commands to a command's parser, as in "mytool remote add origin".
+type Command struct {
+	// Name is the word that selects the command on the command line
+	Name string
+	// Usage is a description of the command for help text
+	Usage string
+	// Aliases are alternative names that select the command, such as "rm" for
+	// "remove". Results and help text use Name.
+	Aliases []string
+	// Args defines the arguments accepted after the command name
+	Args []ArgDef
+	// NeedsTTY requires stdin and stdout to be a terminal, as for interactive
+	// commands
+	NeedsTTY bool
+	// MinWidth is the minimum terminal width in columns. It is only checked when
+	// the width is known.
+	MinWidth int
+	// NeedsRawMode requires stdin to be a terminal that can be put in raw mode,
+	// as for full-screen interfaces
+	NeedsRawMode bool
+	// PersistentPreRun is called by Execute before Run of this command and of
+	// every command nested in it, outermost command first
+	PersistentPreRun RunFunc
+	// PreRun is called by Execute right before Run
+	PreRun RunFunc
+	// Run is called by Execute with the command's parsed values, including
+	// persistent arguments, when the command is the last one selected
+	Run RunFunc
+	// PostRun is called by Execute after Run succeeds
+	PostRun RunFunc
+	// PersistentPostRun is called by Execute after PostRun of this command and of
+	// every command nested in it, innermost command first
+	PersistentPostRun RunFunc
+	// Middleware wraps the execution of this command and of every command
+	// nested in it, hooks included (see Parser.Use)
+	Middleware []Middleware
+	// Default selects the command when no other command is given, so that
+	// "mytool" behaves like "mytool serve". Flags the parent parser does not
+	// know are passed on to the default command. At most one command of a
+	// parser may be the default.
+	Default bool
+	// Hidden leaves the command out of help text, error messages that list
+	// commands, and suggestions. It still parses and runs normally, which suits
+	// internal and debugging commands.
+	Hidden bool
+	// Deprecated marks the command as deprecated. It still works, but running
+	// it writes a warning with this message (e.g. "use 'mytool get' instead"),
+	// and help text shows the message next to the command unless deprecated
+	// commands are hidden with WithDeprecatedCommands.
+	Deprecated string
+	// Group is the heading the command is listed under in help text, such as
+	// "Management Commands". Commands without a Group are listed first, under
+	// "Commands".
+	Group string
+	// DocsURL links to further documentation of the command. It is shown in the
+	// command's help and appended to errors in its arguments that have no link
+	// of their own.
+	DocsURL string
+}
+
+// AddCommand registers a subcommand and returns its parser. Arguments before
+// the command name are parsed by p, and everything after it by the returned
+// parser. The subcommand parser starts with p's stdin, logger, warnings
+// writer, epilogue, terminal detection, and secret advice setting, then
+// applies opts. Invalid subcommand definitions are reported by p's Parse.
+//
+// Example:
+//
+//	parser := uargs.NewParser(globalArgs)
+//	add := parser.AddCommand(uargs.Command{Name: "add", Usage: "Add an item", Args: addArgs})
+//	if _, err := parser.Parse(); err != nil {
+//		log.Fatal(err)
+//	}
+//	if res := add.Result(); res != nil {
+//		name, _ := res.Get("name")
+//	}
+func (p *Parser) AddCommand(cmd Command, opts ...Option) *Parser {
+	if p == nil {
+		return nil
+	}
+	inherit := func(c *Parser) {
+		c.stdin, c.logger, c.warnings = p.stdin, p.logger, p.warnings
+		c.epilogue, c.terminal, c.quietSecrets = p.epilogue, p.terminal, p.quietSecrets
+		c.suggestDistance, c.help, c.hideDeprecated = p.suggestDistance, p.help, p.hideDeprecated
+		c.envPrefix, c.dotEnv, c.dotEnvPath = p.envPrefix, p.dotEnv, p.dotEnvPath
+		c.configPath, c.configFlag, c.configApp = p.configPath, p.configFlag, p.configApp
+		c.configLayers, c.profileFlag, c.profiles = p.configLayers, p.profileFlag, p.profiles
+		c.secrets, c.sources, c.precedence = p.secrets, p.sources, p.precedence
+		c.sortUsage, c.printFlags, c.selfTest = p.sortUsage, p.printFlags, p.selfTest
+		// Persistent arguments are inherited before the rules and groups of the
+		// command are checked, so that they can refer to them
+		c.inheritedFlags = make(map[string]bool)
+		for _, name := range p.order {
+			def := p.defs[name]
+			if !def.Persistent {
+				continue
+			}
+			if _, ok := c.defs[name]; ok {
+				continue // The command's own argument shadows the persistent one (see ArgDef.Persistent)
+			}
+			if _, ok := c.shortToLong[def.Short]; ok {
+				def.Short = ""
+			}
+			c.defs[name] = def
+			c.order = append(c.order, name)
+			if def.Short != "" {
+				c.shortToLong[def.Short] = name
+			}
+			c.inheritedFlags[name] = true
+		}
+	}
+	child := NewParser(cmd.Args, append([]Option{inherit}, opts...)...)
+	child.command = &cmd
+	child.parent = p
+
+	var err error
+	switch {
+	case cmd.Name == "" || cmd.Name[0] == '-':
+		err = fmt.Errorf("command name %q must not be empty or start with '-'", cmd.Name)
+	case p.findCommand(cmd.Name) != nil:
+		err = fmt.Errorf("command %s is defined more than once", strings.Join(child.path(), " "))
+	case slices.ContainsFunc(cmd.Aliases, func(a string) bool { return a == "" || a[0] == '-' }):
+		err = fmt.Errorf("aliases of command %s must not be empty or start with '-'", strings.Join(child.path(), " "))
+	case cmd.Default && p.defaultCommand() != nil:
+		err = fmt.Errorf("commands %s and %s are both marked as default", p.defaultCommand().command.Name, cmd.Name)
+	case p.aliasTaken(cmd) != "":
+		err = fmt.Errorf("alias %s of command %s is already taken", p.aliasTaken(cmd), strings.Join(child.path(), " "))
+	case child.defErr != nil:
+		err = fmt.Errorf("command %s: %v", strings.Join(child.path(), " "), child.defErr)
+	}
+	// Enclosing commands report the error too, since parsing starts at the root
+	for q := p; err != nil && q != nil; q = q.parent {
+		if q.defErr == nil {
+			q.defErr = err
+		}
+	}
+	p.commands = append(p.commands, child)
+	return child
+}
+
+// path returns the names of the commands from the root down to p
+func (p *Parser) path() []string {
+	var names []string
+	for q := p; q != nil && q.command != nil; q = q.parent {
+		names = append([]string{q.command.Name}, names...)
+	}
+	return names
+}
+
+// Name returns the name of the command handled by p, or "" for a parser
+// created with NewParser
+func (p *Parser) Name() string {
+	if p == nil || p.command == nil {
+		return ""
+	}
+	return p.command.Name
+}
+
+// findCommand returns the parser of the subcommand called name or one of its
+// aliases, if any
+func (p *Parser) findCommand(name string) *Parser {
+	for _, c := range p.commands {
+		if c.command.Name == name || slices.Contains(c.command.Aliases, name) {
+			return c
+		}
+	}
+	return nil
+}
+
+// visibleCommands returns the parsers of the subcommands that are not Hidden,
+// leaving out deprecated ones if WithDeprecatedCommands hides them
+func (p *Parser) visibleCommands() []*Parser {
+	var visible []*Parser
+	for _, c := range p.commands {
+		if !c.command.Hidden && (c.command.Deprecated == "" || !p.hideDeprecated) {
+			visible = append(visible, c)
+		}
+	}
+	return visible
+}
+
+// defaultCommand returns the parser of the default subcommand, if any
+func (p *Parser) defaultCommand() *Parser {
+	for _, c := range p.commands {
+		if c.command.Default {
+			return c
+		}
+	}
+	return nil
+}
+
+// aliasTaken returns the first alias of cmd that already selects another
+// command, or is repeated, or "" if all are free
+func (p *Parser) aliasTaken(cmd Command) string {
+	for i, alias := range cmd.Aliases {
+		if alias == cmd.Name || p.findCommand(alias) != nil || slices.Contains(cmd.Aliases[:i], alias) {
+			return alias
+		}
+	}
+	return ""
+}
+
+// clearCommands forgets the results of subcommands from an earlier parse
+func (p *Parser) clearCommands() {
+	for _, c := range p.commands {
+		c.done = false
+		c.clearCommands()
+	}
+}
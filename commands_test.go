@@ -0,0 +1,433 @@
+package uargs_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestCommands tests subcommands with their own arguments and results
+func TestCommands(t *testing.T) {
+	parser := uargs.NewParser([]uargs.ArgDef{
+		{Name: "verbose", Short: "v", Usage: "Verbosity", Type: uargs.Int, Default: 0},
+	})
+	add := parser.AddCommand(uargs.Command{Name: "add", Usage: "Add an item", Args: []uargs.ArgDef{
+		{Name: "name", Short: "n", Usage: "Item name", Type: uargs.String, Required: true},
+	}})
+	remove := parser.AddCommand(uargs.Command{Name: "remove", Usage: "Remove an item", Args: []uargs.ArgDef{
+		{Name: "id", Usage: "Item ID", Type: uargs.Int, Required: true},
+	}})
+	if add.Name() != "add" || parser.Name() != "" {
+		t.Errorf("Expected command names add and \"\", got %q and %q", add.Name(), parser.Name())
+	}
+
+	// Test case 1: Global flags before the command, command flags after it
+	parsed, err := parser.ParseArgs([]string{"-v", "2", "add", "--name", "x"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parsed["verbose"] != 2 {
+		t.Errorf("Expected verbose=2, got %v", parsed["verbose"])
+	}
+	cmd := parser.Result().Command()
+	if cmd == nil || cmd.Name() != "add" {
+		t.Fatalf("Expected add to be selected, got %+v", cmd)
+	}
+	if v, _ := cmd.Get("name"); v != "x" {
+		t.Errorf("Expected name=x, got %v", v)
+	}
+	if remove.Result() != nil {
+		t.Errorf("Expected no result for the command that was not selected")
+	}
+
+	// Test case 2: A later parse selects another command
+	if _, err := parser.ParseArgs([]string{"remove", "--id", "3"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if add.Result() != nil || remove.Result() == nil {
+		t.Errorf("Expected only remove to have a result")
+	}
+
+	// Test case 3: No command
+	if _, err := parser.ParseArgs(nil); err != nil || parser.Result().Command() != nil {
+		t.Errorf("Expected no command to be selected, got %v", err)
+	}
+
+	// Test case 4: Errors
+	errorCases := map[string][]string{
+		"unknown command list":                  {"list"},
+		"missing required argument -n, --name":  {"add"},
+		"unknown argument --name":               {"--name", "x", "add"},
+		"unknown argument --verbose":            {"add", "--name", "x", "--verbose", "1"},
+		"too many values for -v, --verbose (ex": {"-v", "1", "2"},
+	}
+	for want, argv := range errorCases {
+		_, err := parser.ParseArgs(argv)
+		if err == nil || !strings.HasPrefix(err.Error(), want) {
+			t.Errorf("%v: expected error %q, got %v", argv, want, err)
+		}
+	}
+
+	// Test case 5: Usage lists the commands
+	if !strings.Contains(parser.Usage(), "Commands:\n  add     Add an item\n  remove  Remove an item\n") {
+		t.Errorf("Expected commands in usage, got:\n%s", parser.Usage())
+	}
+	if !strings.Contains(add.Usage(), "--name") {
+		t.Errorf("Expected add usage to list --name, got:\n%s", add.Usage())
+	}
+}
+
+// TestCommandMisconfiguration tests invalid subcommand definitions
+func TestCommandMisconfiguration(t *testing.T) {
+	tests := map[string]func(p *uargs.Parser){
+		`command name "" must not be empty or start with '-'`: func(p *uargs.Parser) {
+			p.AddCommand(uargs.Command{})
+		},
+		"command add is defined more than once": func(p *uargs.Parser) {
+			p.AddCommand(uargs.Command{Name: "add"})
+			p.AddCommand(uargs.Command{Name: "add"})
+		},
+		"command add: argument definition #1 has an empty Name": func(p *uargs.Parser) {
+			p.AddCommand(uargs.Command{Name: "add", Args: []uargs.ArgDef{{}}})
+		},
+	}
+	for want, setup := range tests {
+		parser := uargs.NewParser(nil)
+		setup(parser)
+		if _, err := parser.ParseArgs(nil); err == nil || err.Error() != want {
+			t.Errorf("Expected %q, got %v", want, err)
+		}
+	}
+}
+
+// TestNestedCommands tests commands containing child commands
+func TestNestedCommands(t *testing.T) {
+	parser := uargs.NewParser(nil)
+	remote := parser.AddCommand(uargs.Command{Name: "remote", Usage: "Manage remotes"})
+	add := remote.AddCommand(uargs.Command{Name: "add", Usage: "Add a remote", Args: []uargs.ArgDef{
+		{Name: "name", Usage: "Remote name", Type: uargs.String, Required: true},
+		{Name: "url", Usage: "Remote URL", Type: uargs.String, Required: true},
+	}})
+	remote.AddCommand(uargs.Command{Name: "remove", Usage: "Remove a remote"})
+
+	if _, err := parser.ParseArgs([]string{"remote", "add", "--name", "origin", "--url", "git@example.com:x.git"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	res := parser.Result()
+	if path := strings.Join(res.CommandPath(), " "); path != "remote add" {
+		t.Errorf("Expected command path \"remote add\", got %q", path)
+	}
+	if v, _ := add.Result().Get("url"); v != "git@example.com:x.git" {
+		t.Errorf("Expected url from the nested command, got %v", v)
+	}
+
+	if _, err := parser.ParseArgs([]string{"remote"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if path := parser.Result().CommandPath(); len(path) != 1 || add.Result() != nil {
+		t.Errorf("Expected only remote to be selected, got %v", path)
+	}
+	if _, err := parser.ParseArgs([]string{"remote", "rename"}); err == nil || err.Error() != "unknown command rename" {
+		t.Errorf("Expected unknown nested command, got %v", err)
+	}
+
+	// Each level has its own usage
+	if usage := remote.Usage(); !strings.HasPrefix(usage, "Usage: remote\n") || !strings.Contains(usage, "  add     Add a remote\n") {
+		t.Errorf("Expected remote usage with its commands, got:\n%s", usage)
+	}
+	if usage := add.Usage(); !strings.HasPrefix(usage, "Usage: remote add\n") || !strings.Contains(usage, "--url") {
+		t.Errorf("Expected add usage with its arguments, got:\n%s", usage)
+	}
+
+	// Definition errors of nested commands are reported from the root
+	remote.AddCommand(uargs.Command{Name: "show", Args: []uargs.ArgDef{{Name: "x", NumArgs: -1}}})
+	_, err := parser.ParseArgs(nil)
+	if err == nil || err.Error() != "command remote show: --x has negative NumArgs -1" {
+		t.Errorf("Expected nested definition error, got %v", err)
+	}
+}
+
+// TestCommandAliases tests alternative command names
+func TestCommandAliases(t *testing.T) {
+	parser := uargs.NewParser(nil)
+	parser.AddCommand(uargs.Command{Name: "remove", Usage: "Remove an item", Aliases: []string{"rm", "del"}})
+	parser.AddCommand(uargs.Command{Name: "list", Usage: "List items", Aliases: []string{"ls"}})
+
+	for _, name := range []string{"remove", "rm", "del"} {
+		if _, err := parser.ParseArgs([]string{name}); err != nil {
+			t.Fatalf("%s: unexpected error %v", name, err)
+		}
+		if path := parser.Result().CommandPath(); len(path) != 1 || path[0] != "remove" {
+			t.Errorf("%s: expected canonical name remove, got %v", name, path)
+		}
+	}
+	if !strings.Contains(parser.Usage(), "  remove  Remove an item (aliases: rm, del)\n") {
+		t.Errorf("Expected aliases in usage, got:\n%s", parser.Usage())
+	}
+
+	for want, cmd := range map[string]uargs.Command{
+		"alias ls of command show is already taken":          {Name: "show", Aliases: []string{"ls"}},
+		"alias remove of command drop is already taken":      {Name: "drop", Aliases: []string{"remove"}},
+		"alias s of command show is already taken":           {Name: "show", Aliases: []string{"s", "s"}},
+		"aliases of command show must not be empty or start": {Name: "show", Aliases: []string{""}},
+	} {
+		parser := uargs.NewParser(nil)
+		parser.AddCommand(uargs.Command{Name: "remove"})
+		parser.AddCommand(uargs.Command{Name: "list", Aliases: []string{"ls"}})
+		parser.AddCommand(cmd)
+		if _, err := parser.ParseArgs(nil); err == nil || !strings.HasPrefix(err.Error(), want) {
+			t.Errorf("Expected %q, got %v", want, err)
+		}
+	}
+}
+
+// TestDefaultCommand tests the command selected when none is given
+func TestDefaultCommand(t *testing.T) {
+	parser := uargs.NewParser([]uargs.ArgDef{{Name: "verbose", Short: "v", Usage: "Verbosity", Type: uargs.Int}})
+	serve := parser.AddCommand(uargs.Command{Name: "serve", Usage: "Start the server", Default: true, Args: []uargs.ArgDef{
+		{Name: "port", Short: "p", Usage: "Port", Type: uargs.Int, Default: 8080},
+	}})
+	parser.AddCommand(uargs.Command{Name: "init", Usage: "Create a config"})
+
+	tests := []struct {
+		argv []string
+		path string
+		port interface{}
+	}{
+		{nil, "serve", 8080},
+		{[]string{"-v", "1"}, "serve", 8080},
+		{[]string{"--port", "90"}, "serve", 90},
+		{[]string{"-v", "1", "-p", "91"}, "serve", 91},
+		{[]string{"serve", "--port", "92"}, "serve", 92},
+		{[]string{"init"}, "init", nil},
+	}
+	for _, tt := range tests {
+		if _, err := parser.ParseArgs(tt.argv); err != nil {
+			t.Errorf("%v: unexpected error %v", tt.argv, err)
+			continue
+		}
+		if path := strings.Join(parser.Result().CommandPath(), " "); path != tt.path {
+			t.Errorf("%v: expected command %s, got %q", tt.argv, tt.path, path)
+		}
+		if tt.port != nil {
+			if v, _ := serve.Result().Get("port"); v != tt.port {
+				t.Errorf("%v: expected port=%v, got %v", tt.argv, tt.port, v)
+			}
+		}
+	}
+
+	if _, err := parser.ParseArgs([]string{"--bogus"}); err == nil || err.Error() != "unknown argument --bogus" {
+		t.Errorf("Expected the default command to reject unknown flags, got %v", err)
+	}
+	if _, err := parser.ParseArgs([]string{"bogus"}); err == nil || err.Error() != "unknown command bogus" {
+		t.Errorf("Expected unknown command, got %v", err)
+	}
+	if !strings.Contains(parser.Usage(), "  serve  Start the server (default)\n") {
+		t.Errorf("Expected default marker in usage, got:\n%s", parser.Usage())
+	}
+
+	parser.AddCommand(uargs.Command{Name: "run", Default: true})
+	if _, err := parser.ParseArgs(nil); err == nil || err.Error() != "commands serve and run are both marked as default" {
+		t.Errorf("Expected error for two default commands, got %v", err)
+	}
+}
+
+// TestPersistentFlags tests root flags shared with every subcommand
+func TestPersistentFlags(t *testing.T) {
+	parser := uargs.NewParser([]uargs.ArgDef{
+		{Name: "verbose", Short: "v", Usage: "Verbosity", Type: uargs.Int, Default: 0, Persistent: true},
+		{Name: "config", Short: "c", Usage: "Config file", Type: uargs.String, Required: true, Persistent: true},
+		{Name: "dry-run", Usage: "Root only", Type: uargs.String},
+	})
+	remote := parser.AddCommand(uargs.Command{Name: "remote", Args: []uargs.ArgDef{
+		{Name: "name", Short: "v", Usage: "Remote name (shadows -v)", Type: uargs.String},
+	}})
+	add := remote.AddCommand(uargs.Command{Name: "add", Args: []uargs.ArgDef{
+		{Name: "config", Usage: "Remote config (shadows --config)", Type: uargs.Int},
+	}})
+
+	// Test case 1: Given before the command
+	if _, err := parser.ParseArgs([]string{"--verbose", "2", "-c", "a.json", "remote"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v, _ := remote.Result().Get("verbose"); v != 2 {
+		t.Errorf("Expected verbose=2 in the command result, got %v", v)
+	}
+	if v, _ := remote.Result().Get("config"); v != "a.json" {
+		t.Errorf("Expected config in the command result, got %v", v)
+	}
+
+	// Test case 2: Given after the command, and the root's Required is satisfied
+	parsed, err := parser.ParseArgs([]string{"remote", "--config", "b.json", "--verbose", "3"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parsed["config"] != "b.json" || parsed["verbose"] != 3 {
+		t.Errorf("Expected root result to include flags given after the command, got %v", parsed)
+	}
+
+	// Test case 3: The command's own short name shadows the inherited one
+	if _, err := parser.ParseArgs([]string{"-c", "x", "remote", "-v", "origin"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v, _ := remote.Result().Get("name"); v != "origin" {
+		t.Errorf("Expected -v to mean --name in remote, got %v", v)
+	}
+	if v, _ := remote.Result().Get("verbose"); v != 0 {
+		t.Errorf("Expected default verbose=0, got %v", v)
+	}
+
+	// Test case 4: Nested commands inherit too, and can shadow by name
+	if _, err := parser.ParseArgs([]string{"-c", "x", "remote", "add", "--verbose", "1", "--config", "5"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v, _ := add.Result().Get("config"); v != 5 {
+		t.Errorf("Expected add's own int --config, got %v", v)
+	}
+	if v, _ := add.Result().Get("verbose"); v != 1 {
+		t.Errorf("Expected verbose=1 in the nested command, got %v", v)
+	}
+
+	// Test case 5: Non-persistent root flags stay at the root
+	if _, err := parser.ParseArgs([]string{"-c", "x", "remote", "--dry-run", "y"}); err == nil || err.Error() != "unknown argument --dry-run" {
+		t.Errorf("Expected --dry-run to be unknown in remote, got %v", err)
+	}
+	if _, err := parser.ParseArgs([]string{"remote"}); err == nil || err.Error() != "missing required argument -c, --config" {
+		t.Errorf("Expected missing persistent required flag, got %v", err)
+	}
+
+	// Test case 6: A shadowed argument is not inherited by the commands nested in
+	// the shadowing command
+	add.AddCommand(uargs.Command{Name: "mirror"})
+	if _, err := parser.ParseArgs([]string{"-c", "x", "remote", "add", "mirror", "--config", "y"}); err == nil || err.Error() != "unknown argument --config" {
+		t.Errorf("Expected --config to be unknown in mirror, got %v", err)
+	}
+}
+
+// TestPersistentConstraints tests command constraints that refer to
+// persistent arguments of the parent
+func TestPersistentConstraints(t *testing.T) {
+	parser := uargs.NewParser([]uargs.ArgDef{
+		{Name: "token", Usage: "API token", Type: uargs.String, Persistent: true},
+		{Name: "anonymous", Usage: "No authentication", NumArgs: 0, Type: uargs.String, Persistent: true},
+	})
+	push := parser.AddCommand(uargs.Command{Name: "push", Usage: "Push changes", Args: []uargs.ArgDef{
+		{Name: "remote", Usage: "Remote name", Type: uargs.String, RequiredIfGiven: []string{"token"}},
+		{Name: "dry-run", Usage: "Do not push", NumArgs: 0, Type: uargs.String, ConflictsWith: []string{"anonymous"}},
+	}}, uargs.WithExactlyOneOf("token", "anonymous"))
+
+	// Test case 1: The constraints hold for persistent flags before or after the command name
+	if _, err := parser.ParseArgs([]string{"--token", "x", "push", "--remote", "origin"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if v, _ := push.Result().Get("remote"); v != "origin" {
+		t.Errorf("Expected remote=origin, got %v", v)
+	}
+	if _, err := parser.ParseArgs([]string{"push", "--token", "x"}); err == nil || err.Error() != "argument --remote is required when --token is given" {
+		t.Errorf("Expected a RequiredIfGiven error, got %v", err)
+	}
+
+	// Test case 2: Conflicts and groups name the persistent flags
+	if _, err := parser.ParseArgs([]string{"push", "--anonymous", "--dry-run"}); err == nil || err.Error() != "arguments --dry-run and --anonymous cannot be used together" {
+		t.Errorf("Expected a conflict error, got %v", err)
+	}
+	if _, err := parser.ParseArgs([]string{"push"}); err == nil || err.Error() != "one of --token | --anonymous is required" {
+		t.Errorf("Expected a group error, got %v", err)
+	}
+}
+
+// TestHiddenCommands tests commands left out of help text and suggestions
+func TestHiddenCommands(t *testing.T) {
+	ran := false
+	parser := uargs.NewParser(nil)
+	parser.AddCommand(uargs.Command{Name: "status", Usage: "Show the status"})
+	parser.AddCommand(uargs.Command{Name: "debug-dump", Usage: "Dump internal state", Hidden: true,
+		Run: func(context.Context, map[string]interface{}) error {
+			ran = true
+			return nil
+		},
+	})
+
+	if err := parser.ExecuteArgs([]string{"debug-dump"}); err != nil || !ran {
+		t.Errorf("Expected the hidden command to run, got %v", err)
+	}
+	for _, usage := range []string{parser.Usage(), parser.AccessibleUsage()} {
+		if strings.Contains(usage, "debug-dump") || !strings.Contains(usage, "status") {
+			t.Errorf("Expected only status in usage, got:\n%s", usage)
+		}
+	}
+	if _, err := parser.ParseArgs([]string{"debug-dmup"}); err == nil || err.Error() != "unknown command debug-dmup" {
+		t.Errorf("Expected no suggestion of the hidden command, got %v", err)
+	}
+	if err := parser.ExecuteArgs(nil); err == nil || err.Error() != "missing command, expected one of: status" {
+		t.Errorf("Expected the hidden command to be left out, got %v", err)
+	}
+}
+
+// TestCommandGroups tests sections of commands in usage
+func TestCommandGroups(t *testing.T) {
+	parser := uargs.NewParser(nil)
+	parser.AddCommand(uargs.Command{Name: "container", Usage: "Manage containers", Group: "Management Commands"})
+	parser.AddCommand(uargs.Command{Name: "run", Usage: "Run a container"})
+	parser.AddCommand(uargs.Command{Name: "inspect", Usage: "Show low-level details", Group: "Troubleshooting"})
+	parser.AddCommand(uargs.Command{Name: "image", Usage: "Manage images", Group: "Management Commands"})
+	parser.AddCommand(uargs.Command{Name: "debug", Usage: "Debug", Group: "Troubleshooting", Hidden: true})
+
+	want := "\nCommands:\n  run        Run a container\n" +
+		"\nManagement Commands:\n  container  Manage containers\n  image      Manage images\n" +
+		"\nTroubleshooting:\n  inspect    Show low-level details\n"
+	if usage := parser.Usage(); !strings.HasSuffix(usage, want) {
+		t.Errorf("Expected grouped commands, got:\n%s", usage)
+	}
+	if usage := parser.AccessibleUsage(); !strings.Contains(usage, "Command image. Manage images. Listed under Management Commands.") {
+		t.Errorf("Expected the group in accessible usage, got:\n%s", usage)
+	}
+
+	// Without ungrouped commands, the default section is left out
+	grouped := uargs.NewParser(nil)
+	grouped.AddCommand(uargs.Command{Name: "login", Usage: "Log in", Group: "Auth"})
+	if usage := grouped.Usage(); strings.Contains(usage, "Commands:") || !strings.Contains(usage, "\nAuth:\n  login  Log in\n") {
+		t.Errorf("Expected only the Auth section, got:\n%s", usage)
+	}
+}
+
+// TestDeprecatedCommands tests warnings and help text for deprecated commands
+func TestDeprecatedCommands(t *testing.T) {
+	var warnings strings.Builder
+	newParser := func(opts ...uargs.Option) *uargs.Parser {
+		parser := uargs.NewParser(nil, append([]uargs.Option{uargs.WithWarnings(&warnings)}, opts...)...)
+		parser.AddCommand(uargs.Command{Name: "get", Usage: "Get an item"})
+		parser.AddCommand(uargs.Command{Name: "fetch", Usage: "Fetch an item", Deprecated: "use get instead"})
+		return parser
+	}
+	parser := newParser()
+
+	if _, err := parser.ParseArgs([]string{"fetch"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if warnings.String() != "warning: command fetch is deprecated: use get instead\n" {
+		t.Errorf("Expected a deprecation warning, got %q", warnings.String())
+	}
+	if !strings.Contains(parser.Usage(), "  fetch  Fetch an item (deprecated: use get instead)\n") {
+		t.Errorf("Expected the deprecation note in usage, got:\n%s", parser.Usage())
+	}
+	if !strings.Contains(parser.AccessibleUsage(), "Command fetch. Fetch an item. Deprecated: use get instead.") {
+		t.Errorf("Expected the deprecation note in accessible usage, got:\n%s", parser.AccessibleUsage())
+	}
+
+	// Current commands rank above deprecated ones
+	if got := parser.Suggest("fet"); len(got) != 2 || got[0].Text != "get" || got[1].Text != "fetch" {
+		t.Errorf("Expected get before fetch, got %+v", got)
+	}
+
+	// Deprecated commands can be left out of help
+	hidden := newParser(uargs.WithDeprecatedCommands(false))
+	if strings.Contains(hidden.Usage(), "fetch") {
+		t.Errorf("Expected fetch to be hidden, got:\n%s", hidden.Usage())
+	}
+	if _, err := hidden.ParseArgs([]string{"fetch"}); err != nil {
+		t.Errorf("Expected the hidden deprecated command to work, got %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+package uargs
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// HintKind describes what a Hint proposes as the next token
+type HintKind string
+
+const (
+	// HintFlag proposes a flag such as --output
+	HintFlag HintKind = "flag"
+	// HintValue proposes a value for the flag that precedes the cursor
+	HintValue HintKind = "value"
+	// HintCommand proposes the name or an alias of a subcommand
+	HintCommand HintKind = "command"
+)
+
+// Hint is a single suggestion for the token at the cursor of a partial command line
+type Hint struct {
+	// Kind tells whether the hint is a flag name, a value placeholder, or a
+	// command name
+	Kind HintKind
+	// Text is the flag as typed (e.g. --output), one of the argument's Choices,
+	// a value placeholder (e.g. <int>), or a command name or alias
+	Text string
+	// Name is the long name of the argument or the name of the command the hint
+	// belongs to
+	Name string
+	// Type is the value type expected by the argument, or "" for commands
+	Type ArgType
+	// Usage is the description of the argument or command
+	Usage string
+}
+
+// Hints returns the tokens that may appear at the cursor position of a partial
+// command line, so editors and terminal hint bars can offer inline assistance.
+// The line holds the arguments without the program name and cursor is a byte
+// offset into it. Flags that were already given are not suggested again.
+// Command names in the line select the subcommand whose flags and values are
+// suggested, and the names and aliases of subcommands that are not Hidden are
+// suggested where a command may appear.
+//
+// Example:
+//
+//	for _, h := range parser.Hints("--input data.csv --co", 21) {
+//		fmt.Println(h.Text, h.Usage) // --count Number of iterations
+//	}
+func (p *Parser) Hints(line string, cursor int) []Hint {
+	if p == nil {
+		return nil
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
+	if cursor > len(line) {
+		cursor = len(line)
+	}
+	before := line[:cursor]
+	tokens := strings.Fields(before)
+	partial := ""
+	if len(tokens) > 0 && !unicode.IsSpace(rune(before[len(before)-1])) {
+		partial = tokens[len(tokens)-1]
+		tokens = tokens[:len(tokens)-1]
+	}
+	return p.hints(tokens, partial, make(map[string]bool))
+}
+
+// hints returns the Hints for partial after the complete tokens, which follow
+// the name of the command of p. used holds the flags already given.
+func (p *Parser) hints(tokens []string, partial string, used map[string]bool) []Hint {
+	var pending *ArgDef
+	given := 0
+	for i, tok := range tokens {
+		if pending == nil && p.isValue(tok) {
+			if c := p.findCommand(tok); c != nil {
+				inherited := make(map[string]bool)
+				for name := range used {
+					if c.inheritedFlags[name] {
+						inherited[name] = true
+					}
+				}
+				return c.hints(tokens[i+1:], partial, inherited)
+			}
+		}
+		if !p.isValue(tok) {
+			pending = nil
+			if def, ok := p.lookup(tok); ok {
+				used[def.Name] = true
+				pending = &def
+				given = 0
+			}
+			continue
+		}
+		if pending != nil {
+			given++
+			if limit := maxArgs(*pending); limit >= 0 && given >= limit {
+				pending = nil
+			}
+		}
+	}
+
+	var hints []Hint
+	if pending != nil && !strings.HasPrefix(partial, "-") {
+		values := []string{"<" + string(valueType(*pending)) + ">"}
+		if len(pending.Choices) > 0 {
+			values = nil
+			for _, choice := range pending.Choices {
+				if strings.HasPrefix(choice, partial) || (pending.IgnoreCase && strings.HasPrefix(strings.ToLower(choice), strings.ToLower(partial))) {
+					values = append(values, choice)
+				}
+			}
+		}
+		for _, v := range values {
+			hints = append(hints, Hint{
+				Kind:  HintValue,
+				Text:  v,
+				Name:  pending.Name,
+				Type:  valueType(*pending),
+				Usage: pending.Usage,
+			})
+		}
+		if partial != "" {
+			return hints
+		}
+	}
+	if pending == nil && !strings.HasPrefix(partial, "-") {
+		var commands []Hint
+		for _, c := range p.visibleCommands() {
+			for _, name := range append([]string{c.command.Name}, c.command.Aliases...) {
+				if strings.HasPrefix(name, partial) {
+					commands = append(commands, Hint{Kind: HintCommand, Text: name, Name: c.command.Name, Usage: c.command.Usage})
+				}
+			}
+		}
+		sort.Slice(commands, func(i, j int) bool { return commands[i].Text < commands[j].Text })
+		hints = append(hints, commands...)
+	}
+	if partial != "" && !strings.HasPrefix(partial, "-") {
+		return hints
+	}
+
+	var flags []Hint
+	for name, def := range p.defs {
+		if used[name] || def.EnvOnly {
+			continue
+		}
+		text := ""
+		if long := "--" + name; strings.HasPrefix(long, partial) {
+			text = long
+		} else if def.Short != "" && strings.HasPrefix("-"+def.Short, partial) {
+			text = "-" + def.Short
+		}
+		if text == "" {
+			continue
+		}
+		flags = append(flags, Hint{Kind: HintFlag, Text: text, Name: name, Type: valueType(def), Usage: def.Usage})
+	}
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Text < flags[j].Text })
+	return append(hints, flags...)
+}
+
+// lookup resolves a token such as --name or -n to its argument definition
+func (p *Parser) lookup(token string) (ArgDef, bool) {
+	if strings.HasPrefix(token, "--") {
+		def, ok := p.defs[token[2:]]
+		return def, ok
+	}
+	if name, ok := p.shortToLong[strings.TrimPrefix(token, "-")]; ok {
+		return p.defs[name], true
+	}
+	return ArgDef{}, false
+}
+
+// valueType returns the effective type of an argument, defaulting to String
+func valueType(def ArgDef) ArgType {
+	if def.Type == "" {
+		return String
+	}
+	return def.Type
+}
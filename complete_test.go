@@ -0,0 +1,89 @@
+package uargs_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestHints tests next-token suggestions for partial command lines
+func TestHints(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "input", Short: "i", Usage: "Input file", Type: uargs.File},
+		{Name: "count", Short: "c", Usage: "Count value", Type: uargs.Int},
+		{Name: "coords", Usage: "Coordinates", NumArgs: 2, Type: uargs.Float},
+		{Name: "format", Usage: "Output format", Choices: []string{"json", "JSONL", "yaml"}, IgnoreCase: true},
+	}
+	parser := uargs.NewParser(args)
+
+	// Test case 1: Flag name prefix
+	hints := parser.Hints("--input a.txt --co", 18)
+	if len(hints) != 2 || hints[0].Text != "--coords" || hints[1].Text != "--count" {
+		t.Fatalf("Expected --coords and --count, got %+v", hints)
+	}
+
+	// Test case 2: Value expected after a flag
+	line := "--count "
+	hints = parser.Hints(line, len(line))
+	if len(hints) == 0 || hints[0].Kind != uargs.HintValue || hints[0].Type != uargs.Int {
+		t.Fatalf("Expected an int value hint first, got %+v", hints)
+	}
+	for _, h := range hints[1:] {
+		if h.Name == "count" {
+			t.Errorf("Did not expect already given flag --count to be suggested")
+		}
+	}
+
+	// Test case 3: Cursor in the middle of the line ignores the rest
+	hints = parser.Hints("-i x --count 3", 4)
+	if len(hints) != 1 || hints[0].Kind != uargs.HintValue || hints[0].Name != "input" {
+		t.Fatalf("Expected only a value hint for --input, got %+v", hints)
+	}
+
+	// Test case 4: Choices are offered as values, filtered by the partial value
+	hints = parser.Hints("--format js", 11)
+	if len(hints) != 2 || hints[0].Text != "json" || hints[1].Text != "JSONL" {
+		t.Fatalf("Expected json and JSONL, got %+v", hints)
+	}
+}
+
+// TestCommandHints tests suggestions for command lines with subcommands
+func TestCommandHints(t *testing.T) {
+	parser := uargs.NewParser([]uargs.ArgDef{
+		{Name: "verbose", Short: "v", Usage: "Verbosity", Type: uargs.Int, Persistent: true},
+		{Name: "root-only", Usage: "Root only", Type: uargs.String},
+	})
+	serve := parser.AddCommand(uargs.Command{Name: "serve", Aliases: []string{"srv"}, Usage: "Serve", Args: []uargs.ArgDef{
+		{Name: "workers", Usage: "Workers", Type: uargs.Int},
+		{Name: "mode", Usage: "Mode", Choices: []string{"fast", "safe"}},
+	}})
+	serve.AddCommand(uargs.Command{Name: "status", Usage: "Show the status"})
+	parser.AddCommand(uargs.Command{Name: "secret", Usage: "Hidden", Hidden: true})
+	parser.AddCommand(uargs.Command{Name: "stop", Usage: "Stop"})
+
+	tests := []struct {
+		line     string
+		expected []string // Kind:Text of each hint, in order
+	}{
+		{"se", []string{"command:serve"}},
+		{"s", []string{"command:serve", "command:srv", "command:stop"}},
+		{"serve --wo", []string{"flag:--workers"}},
+		{"srv --workers ", []string{"value:<int>", "flag:--mode", "flag:--verbose"}},
+		{"serve --mode f", []string{"value:fast"}},
+		{"-v 2 serve --", []string{"flag:--mode", "flag:--workers"}},
+		{"serve --workers 4 st", []string{"command:status"}},
+		{"serve status -", []string{"flag:--verbose"}},
+		{"secret -", []string{"flag:--verbose"}},
+		{"--verbose 2 st", []string{"command:stop"}},
+	}
+	for _, test := range tests {
+		var got []string
+		for _, h := range parser.Hints(test.line, len(test.line)) {
+			got = append(got, string(h.Kind)+":"+h.Text)
+		}
+		if strings.Join(got, " ") != strings.Join(test.expected, " ") {
+			t.Errorf("Expected %v for %q, got %v", test.expected, test.line, got)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package uargs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"unicode/utf8"
+)
+
+// ConfigName is the name of the flag registered by WithConfigFlag
+const ConfigName = "config"
+
+// configFile holds the values of a config file by key: the argument name, such
+// as "port", prefixed with the command path for subcommands, as in
+// "remote.add.name"
+type configFile struct {
+	path   string
+	values map[string]configValue
+}
+
+// configValue is the value of a key in a config file or profile
+type configValue struct {
+	path    string   // File the value was read from, "" for profiles set with WithProfiles
+	profile string   // Profile the value was set in by WithProfiles
+	key     string   // Key as written, for error messages
+	line    int      // Line of the key, 0 if unknown
+	column  int      // Column of the key, 0 if unknown
+	args    []string // Values in command-line form
+}
+
+// configSet holds the merged values of the config files read by a parse
+type configSet struct {
+	file   string                 // File given with --config or WithConfigFile, or found by discovery
+	files  []string               // Files read, lowest precedence first
+	values map[string]configValue // Values by key, from the file of highest precedence
+}
+
+// ConfigValue is a value of the merged config files, as returned by
+// Result.Config
+type ConfigValue struct {
+	// Values are the values of the key, as they would be given on the command
+	// line
+	Values []string
+	// File is the config file the value was read from
+	File string
+	// Line is the line of the key in File
+	Line int
+	// Column is the column of the key in its line, counted in characters
+	Column int
+}
+
+// loadConfig reads and merges the config files of a parse: the layers set with
+// WithConfigLayers that exist, then the file given with --config or
+// WithConfigFile, or found by WithConfigDiscovery. Later files replace the
+// keys of earlier ones. Each file is checked against the arguments of the
+// parser tree. dotEnv holds the variables of the dotenv file, if any.
+func (p *Parser) loadConfig(dotEnv map[string]string) (*configSet, error) {
+	set := &configSet{file: p.configFilePath(dotEnv), values: make(map[string]configValue)}
+	paths := p.configLayers
+	if set.file != "" {
+		paths = append(paths[:len(paths):len(paths)], set.file)
+	}
+	for _, path := range paths {
+		cfg, err := readConfigFile(path)
+		if errors.Is(err, fs.ErrNotExist) && path != set.file {
+			continue
+		}
+		if err == nil {
+			err = p.checkConfig(cfg)
+		}
+		if err != nil {
+			return nil, err
+		}
+		set.files = append(set.files, path)
+		maps.Copy(set.values, cfg.values)
+	}
+	return set, nil
+}
+
+// readConfigFile reads the config file at path in the format given by its
+// extension
+func readConfigFile(path string) (*configFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &configFile{path: path, values: make(map[string]configValue)}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		err = cfg.parseJSON(data)
+	case ".toml":
+		err = cfg.parseTOML(data)
+	case ".ini", ".cfg", ".conf":
+		err = cfg.parseINI(data)
+	default:
+		return nil, fmt.Errorf("%s: unsupported config format %q", path, ext)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for key, v := range cfg.values {
+		v.path = path
+		cfg.values[key] = v
+	}
+	return cfg, nil
+}
+
+// parseJSON reads a JSON object whose keys are argument names. Values are
+// strings, numbers, booleans, or lists of them for multi-value arguments, and
+// nested objects hold the arguments of subcommands. Null values and empty
+// lists are ignored.
+func (c *configFile) parseJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	tok, err := dec.Token()
+	if err == nil && tok != json.Delim('{') {
+		return fmt.Errorf("%s: expected an object of argument names", c.path)
+	}
+	if err == nil {
+		err = c.addJSON(dec, data, "")
+	}
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &syntaxErr):
+		line, col := positionOf(data, syntaxErr.Offset)
+		return fmt.Errorf("%s:%d:%d: %v", c.path, line, col, err)
+	case errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF):
+		return fmt.Errorf("%s: unexpected end of JSON input", c.path)
+	}
+	return err
+}
+
+// addJSON stores the values of the object being read by dec under prefix,
+// with the position of each key in data
+func (c *configFile) addJSON(dec *json.Decoder, data []byte, prefix string) error {
+	for dec.More() {
+		// The key starts at the first quote after the previous token
+		offset := dec.InputOffset()
+		offset += int64(bytes.IndexByte(data[offset:], '"'))
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key := prefix + tok.(string)
+		line, col := positionOf(data, offset)
+		if tok, err = dec.Token(); err != nil {
+			return err
+		}
+		var args []string
+		switch tok {
+		case nil:
+			continue
+		case json.Delim('{'):
+			if err := c.addJSON(dec, data, key+"."); err != nil {
+				return err
+			}
+			continue
+		case json.Delim('['):
+			for dec.More() {
+				if tok, err = dec.Token(); err != nil {
+					return err
+				}
+				s, ok := jsonScalar(tok)
+				if !ok {
+					return fmt.Errorf("%s:%d:%d: %s: expected a list of strings, numbers, or booleans", c.path, line, col, key)
+				}
+				args = append(args, s)
+			}
+			if _, err := dec.Token(); err != nil {
+				return err
+			}
+		default:
+			s, _ := jsonScalar(tok)
+			args = []string{s}
+		}
+		if len(args) > 0 {
+			c.values[key] = configValue{key: key, line: line, column: col, args: args}
+		}
+	}
+	// The closing brace
+	_, err := dec.Token()
+	return err
+}
+
+// jsonScalar formats a JSON string, number, or boolean as a command-line value
+func jsonScalar(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return fmt.Sprint(v), true
+	}
+	return "", false
+}
+
+// positionOf returns the line and column, counted in characters, of the byte
+// at offset in data
+func positionOf(data []byte, offset int64) (line, col int) {
+	before := data[:min(int(offset), len(data))]
+	start := bytes.LastIndexByte(before, '\n') + 1
+	return bytes.Count(before, []byte("\n")) + 1, utf8.RuneCount(before[start:]) + 1
+}
+
+// indentOf returns the number of spaces and tabs that line starts with
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+// configKeys returns the keys the named argument may have in a config file:
+// under the command path of p, and for inherited Persistent arguments under the
+// path of each enclosing command, nearest first
+func (p *Parser) configKeys(name string) []string {
+	var keys []string
+	for q := p; q != nil; q = q.parent {
+		keys = append(keys, strings.Join(append(q.path(), name), "."))
+		if !q.inheritedFlags[name] {
+			break
+		}
+	}
+	return keys
+}
+
+// configExtensions are the extensions of the config files found by
+// WithConfigDiscovery, in the order they are tried
+var configExtensions = []string{".json", ".toml", ".ini", ".cfg", ".conf"}
+
+// configFilePath returns the config file to read: the value of --config when
+// WithConfigFlag is set and it has one, from the command line or from its
+// environment variable in the process environment or dotEnv, the path set with
+// WithConfigFile, or the first file found by WithConfigDiscovery. It returns ""
+// if there is none.
+func (p *Parser) configFilePath(dotEnv map[string]string) string {
+	if p.configFlag {
+		if path, ok := p.parsed[ConfigName].(string); ok && path != "" {
+			return path
+		}
+		env := &envSource{p: p, dotEnv: dotEnv}
+		if v, ok, err := env.Lookup(p.path(), p.defs[ConfigName]); err == nil && ok && len(v.Args) == 1 {
+			return v.Args[0]
+		}
+	}
+	if p.configPath != "" || p.configApp == "" {
+		return p.configPath
+	}
+	for _, path := range configCandidates(p.configApp) {
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			return path
+		}
+	}
+	return ""
+}
+
+// configCandidates returns the files searched by WithConfigDiscovery for app,
+// in order: config.* in the app's directory under the user config directory
+// ($XDG_CONFIG_HOME or ~/.config on Unix, ~/Library/Application Support on
+// macOS, %APPDATA% on Windows), then under $XDG_CONFIG_HOME or ~/.config on
+// macOS, where many command-line tools keep their settings
+func configCandidates(app string) []string {
+	var dirs []string
+	if dir, err := os.UserConfigDir(); err == nil {
+		dirs = append(dirs, dir)
+	}
+	if runtime.GOOS == "darwin" {
+		if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+			dirs = append(dirs, dir)
+		} else if home, err := os.UserHomeDir(); err == nil {
+			dirs = append(dirs, filepath.Join(home, ".config"))
+		}
+	}
+	var paths []string
+	for _, dir := range dirs {
+		for _, ext := range configExtensions {
+			paths = append(paths, filepath.Join(dir, app, "config"+ext))
+		}
+	}
+	return paths
+}
+
+// configSource looks up arguments in the merged config files
+type configSource struct {
+	p   *Parser
+	cfg *configSet
+}
+
+// Lookup returns the value of def from the config files, under the first of
+// its keys that is set. The --config argument of WithConfigFlag is never taken
+// from them.
+func (s *configSource) Lookup(path []string, def ArgDef) (SourceValue, bool, error) {
+	if s.p.configFlag && def.Name == ConfigName {
+		return SourceValue{}, false, nil
+	}
+	return lookupConfig(s.p, s.cfg.values, def, OriginConfig)
+}
+
+// lookupConfig returns the value of def from values, keyed as in config files,
+// under the first of its keys that is set, with origin as where it came from
+func lookupConfig(p *Parser, values map[string]configValue, def ArgDef, origin Origin) (SourceValue, bool, error) {
+	for _, key := range p.configKeys(def.Name) {
+		if v, ok := values[key]; ok {
+			src := Source{Origin: origin, File: v.path, Line: v.line, Key: v.key, Profile: v.profile}
+			return SourceValue{Args: v.args, Source: src}, true, nil
+		}
+	}
+	return SourceValue{}, false, nil
+}
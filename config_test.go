@@ -0,0 +1,288 @@
+package uargs_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// writeConfig writes content to a file named name in a temporary directory and
+// returns its path
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestConfigJSON tests taking values from a JSON config file
+func TestConfigJSON(t *testing.T) {
+	path := writeConfig(t, "config.json", `{
+	"port": 8080,
+	"host": "example.com",
+	"ratio": 0.5,
+	"tags": ["a", "b"],
+	"token": null,
+	"remote": {"add": {"name": "origin", "verbose": "yes"}}
+}`)
+	parser := uargs.NewParser([]uargs.ArgDef{
+		{Name: "port", Usage: "Port", Type: uargs.Int, Env: "MYAPP_PORT", Default: 80},
+		{Name: "host", Usage: "Host", Type: uargs.String, Required: true},
+		{Name: "ratio", Usage: "Ratio", Type: uargs.Float},
+		{Name: "tags", Usage: "Tags", Type: uargs.String, NumArgs: 2},
+		{Name: "token", Usage: "Token", Type: uargs.String},
+		{Name: "verbose", Usage: "Verbosity", Type: uargs.String, Persistent: true, Default: "no"},
+	}, uargs.WithConfigFile(path))
+	remote := parser.AddCommand(uargs.Command{Name: "remote", Usage: "Manage remotes"})
+	add := remote.AddCommand(uargs.Command{Name: "add", Usage: "Add a remote", Args: []uargs.ArgDef{
+		{Name: "name", Usage: "Remote name", Type: uargs.String},
+	}})
+
+	// Test case 1: Config values fill absent arguments and satisfy required ones
+	parsed, err := parser.ParseArgs(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parsed["port"] != 8080 || parsed["host"] != "example.com" || parsed["ratio"] != 0.5 || parsed["verbose"] != "no" {
+		t.Errorf("Expected values from the config file, got %v", parsed)
+	}
+	if tags, ok := parsed["tags"].([]string); !ok || strings.Join(tags, "|") != "a|b" {
+		t.Errorf("Expected tags a and b, got %v", parsed["tags"])
+	}
+	if _, ok := parsed["token"]; ok {
+		t.Errorf("Expected null to leave token unset, got %v", parsed["token"])
+	}
+	if origin := parser.Result().Origin("port"); origin != uargs.OriginConfig {
+		t.Errorf("Expected origin config, got %s", origin)
+	}
+
+	// Test case 2: The command line and the environment take precedence
+	t.Setenv("MYAPP_PORT", "9000")
+	parsed, err = parser.ParseArgs([]string{"--host", "localhost"})
+	if err != nil || parsed["port"] != 9000 || parsed["host"] != "localhost" {
+		t.Errorf("Expected port=9000 and host=localhost, got %v (%v)", parsed, err)
+	}
+
+	// Test case 3: Nested objects hold the values of subcommands
+	if _, err := parser.ParseArgs([]string{"remote", "add"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	values := add.Result().Map()
+	if values["name"] != "origin" || values["verbose"] != "yes" {
+		t.Errorf("Expected name=origin and verbose=yes, got %v", values)
+	}
+}
+
+// TestConfigJSONErrors tests errors in JSON config files
+func TestConfigJSONErrors(t *testing.T) {
+	defs := []uargs.ArgDef{
+		{Name: "port", Usage: "Port", Type: uargs.Int, Max: uargs.Bound(65535)},
+	}
+	tests := []struct {
+		name, file, content, expected string
+	}{
+		{"invalid value", "config.json", `{"port": 99999}`, "(from config file %s:1, key port)"},
+		{"syntax error", "config.json", "{\n\"port\": 80,\n}", "%s:2:12: invalid character ','"},
+		{"nested list", "config.json", `{"port": [[1]]}`, "%s:1:2: port: expected a list of strings, numbers, or booleans"},
+		{"not an object", "config.json", `[1]`, "%s: expected an object of argument names"},
+		{"unknown format", "config.xml", `<port>80</port>`, `%s: unsupported config format ".xml"`},
+	}
+	for _, test := range tests {
+		path := writeConfig(t, test.file, test.content)
+		parser := uargs.NewParser(defs, uargs.WithConfigFile(path))
+		_, err := parser.ParseArgs(nil)
+		expected := strings.ReplaceAll(test.expected, "%s", path)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("%s: Expected an error containing %q, got %v", test.name, expected, err)
+		}
+	}
+
+	// Test case: Invalid values are validation errors about the argument
+	path := writeConfig(t, "config.json", `{"port": "http"}`)
+	parser := uargs.NewParser(defs, uargs.WithConfigFile(path))
+	_, err := parser.ParseArgs(nil)
+	var argErr *uargs.ArgError
+	if !errors.As(err, &argErr) || argErr.Name != "port" || parser.ExitCode(err) != 3 {
+		t.Errorf("Expected a validation error for --port, got %v", err)
+	}
+}
+
+// TestConfigFlag tests choosing the config file with --config
+func TestConfigFlag(t *testing.T) {
+	base := writeConfig(t, "base.json", `{"port": 1000, "host": "base.example.com"}`)
+	dev := writeConfig(t, "dev.json", `{"port": 2000, "serve": {"workers": 4}}`)
+	parser := uargs.NewParser([]uargs.ArgDef{
+		{Name: "port", Usage: "Port", Type: uargs.Int, Env: "MYAPP_PORT", Default: 80},
+		{Name: "host", Usage: "Host", Type: uargs.String, Default: "localhost"},
+	}, uargs.WithConfigFile(base), uargs.WithConfigFlag())
+	serve := parser.AddCommand(uargs.Command{Name: "serve", Usage: "Serve", Args: []uargs.ArgDef{
+		{Name: "workers", Usage: "Workers", Type: uargs.Int, Default: 1},
+	}})
+
+	// Test case 1: Without --config, the file set with WithConfigFile is read
+	parsed, err := parser.ParseArgs(nil)
+	if err != nil || parsed["port"] != 1000 || parsed["host"] != "base.example.com" {
+		t.Errorf("Expected values from the base file, got %v (%v)", parsed, err)
+	}
+
+	// Test case 2: --config replaces the file, before or after the command name
+	for _, argv := range [][]string{{"--config", dev, "serve"}, {"serve", "--config", dev}} {
+		parsed, err := parser.ParseArgs(argv)
+		if err != nil {
+			t.Fatalf("Unexpected error for %v: %v", argv, err)
+		}
+		if parsed["port"] != 2000 || parsed["host"] != "localhost" || parsed["config"] != dev {
+			t.Errorf("Expected values from the dev file for %v, got %v", argv, parsed)
+		}
+		if workers := serve.Result().Map()["workers"]; workers != 4 {
+			t.Errorf("Expected workers=4 for %v, got %v", argv, workers)
+		}
+	}
+
+	// Test case 3: The command line and the environment take precedence
+	t.Setenv("MYAPP_PORT", "3000")
+	parsed, err = parser.ParseArgs([]string{"--config", dev})
+	if err != nil || parsed["port"] != 3000 {
+		t.Errorf("Expected port=3000 from the environment, got %v (%v)", parsed["port"], err)
+	}
+	parsed, err = parser.ParseArgs([]string{"--config", dev, "--port", "4000"})
+	if err != nil || parsed["port"] != 4000 {
+		t.Errorf("Expected port=4000 from the command line, got %v (%v)", parsed["port"], err)
+	}
+
+	// Test case 4: A missing file is an error
+	if _, err := parser.ParseArgs([]string{"--config", dev + ".missing"}); err == nil {
+		t.Errorf("Expected an error for a missing config file")
+	}
+
+	// Test case 5: The file can be chosen through the environment variable of --config
+	base = writeConfig(t, "base.json", `{"port": 1000}`)
+	dev = writeConfig(t, "dev.json", `{"port": 2000}`)
+	t.Setenv("APP_CONFIG", dev)
+	parser = uargs.NewParser([]uargs.ArgDef{
+		{Name: "port", Usage: "Port", Type: uargs.Int, Env: "-", Default: 80},
+	}, uargs.WithConfigFile(base), uargs.WithConfigFlag(), uargs.WithEnvPrefix("APP"))
+	parsed, err = parser.ParseArgs(nil)
+	if err != nil || parsed["port"] != 2000 || parsed["config"] != dev {
+		t.Errorf("Expected values from the dev file, got %v (%v)", parsed, err)
+	}
+	if origin := parser.Result().Origin("config"); origin != uargs.OriginEnv {
+		t.Errorf("Expected origin env for config, got %s", origin)
+	}
+	if parsed, err = parser.ParseArgs([]string{"--config", base}); err != nil || parsed["port"] != 1000 {
+		t.Errorf("Expected --config to take precedence, got %v (%v)", parsed["port"], err)
+	}
+}
+
+// TestConfigDiscovery tests finding the config file in the user config directory
+func TestConfigDiscovery(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("APPDATA", dir)
+	defs := []uargs.ArgDef{
+		{Name: "port", Usage: "Port", Type: uargs.Int, Default: 80},
+	}
+	parser := uargs.NewParser(defs, uargs.WithConfigDiscovery("mytool"))
+
+	// Test case 1: Finding no file is not an error
+	parsed, err := parser.ParseArgs(nil)
+	if err != nil || parsed["port"] != 80 || parser.Result().ConfigFile() != "" {
+		t.Errorf("Expected the default port and no config file, got %v and %q (%v)", parsed["port"], parser.Result().ConfigFile(), err)
+	}
+
+	// Test case 2: JSON files are tried before TOML files
+	if err := os.MkdirAll(filepath.Join(dir, "mytool"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	tomlPath := filepath.Join(dir, "mytool", "config.toml")
+	if err := os.WriteFile(tomlPath, []byte("port = 8080\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	parsed, err = parser.ParseArgs(nil)
+	if err != nil || parsed["port"] != 8080 || parser.Result().ConfigFile() != tomlPath {
+		t.Errorf("Expected port=8080 from %s, got %v from %q (%v)", tomlPath, parsed["port"], parser.Result().ConfigFile(), err)
+	}
+	jsonPath := filepath.Join(dir, "mytool", "config.json")
+	if err := os.WriteFile(jsonPath, []byte(`{"port": 9090}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	parsed, err = parser.ParseArgs(nil)
+	if err != nil || parsed["port"] != 9090 || parser.Result().ConfigFile() != jsonPath {
+		t.Errorf("Expected port=9090 from %s, got %v from %q (%v)", jsonPath, parsed["port"], parser.Result().ConfigFile(), err)
+	}
+
+	// Test case 3: An explicit path takes precedence
+	explicit := writeConfig(t, "explicit.json", `{"port": 7070}`)
+	parser = uargs.NewParser(defs, uargs.WithConfigDiscovery("mytool"), uargs.WithConfigFile(explicit))
+	parsed, err = parser.ParseArgs(nil)
+	if err != nil || parsed["port"] != 7070 || parser.Result().ConfigFile() != explicit {
+		t.Errorf("Expected port=7070 from %s, got %v from %q (%v)", explicit, parsed["port"], parser.Result().ConfigFile(), err)
+	}
+}
+
+// TestConfigLayers tests merging several config files
+func TestConfigLayers(t *testing.T) {
+	system := writeConfig(t, "system.toml", "port = 1000\nhost = \"system.example.com\"\nretries = 1\n")
+	user := writeConfig(t, "user.json", `{"port": 2000, "timeout": 30}`)
+	project := writeConfig(t, "project.ini", "\nhost = project.example.com\n")
+	missing := filepath.Join(t.TempDir(), "missing.toml")
+	defs := []uargs.ArgDef{
+		{Name: "port", Usage: "Port", Type: uargs.Int},
+		{Name: "host", Usage: "Host", Type: uargs.String},
+		{Name: "retries", Usage: "Retries", Type: uargs.Int},
+		{Name: "timeout", Usage: "Timeout", Type: uargs.Int},
+	}
+	parser := uargs.NewParser(defs, uargs.WithConfigLayers(system, missing, user, project), uargs.WithConfigFlag())
+
+	// Test case 1: Later files replace the keys of earlier ones, and missing files are skipped
+	parsed, err := parser.ParseArgs(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parsed["port"] != 2000 || parsed["host"] != "project.example.com" || parsed["retries"] != 1 || parsed["timeout"] != 30 {
+		t.Errorf("Expected merged values, got %v", parsed)
+	}
+	res := parser.Result()
+	if files := res.ConfigFiles(); strings.Join(files, "|") != strings.Join([]string{system, user, project}, "|") {
+		t.Errorf("Expected the files that exist, got %v", files)
+	}
+	if file := res.ConfigFile(); file != "" {
+		t.Errorf("Expected no explicit config file, got %q", file)
+	}
+
+	// Test case 2: Config reports the file and line of each key
+	config := res.Config()
+	if v := config["host"]; v.File != project || v.Line != 2 || strings.Join(v.Values, "|") != "project.example.com" {
+		t.Errorf("Expected host from %s:2, got %+v", project, v)
+	}
+	if v := config["retries"]; v.File != system || v.Line != 3 {
+		t.Errorf("Expected retries from %s:3, got %+v", system, v)
+	}
+	if v := config["port"]; v.File != user || v.Line != 1 || v.Column != 2 {
+		t.Errorf("Expected port from %s:1:2, got %+v", user, v)
+	}
+
+	// Test case 3: The file given with --config takes precedence over all layers
+	override := writeConfig(t, "override.json", `{"timeout": 60}`)
+	parsed, err = parser.ParseArgs([]string{"--config", override})
+	if err != nil || parsed["timeout"] != 60 || parsed["port"] != 2000 {
+		t.Errorf("Expected timeout=60 and port=2000, got %v (%v)", parsed, err)
+	}
+	if files := parser.Result().ConfigFiles(); len(files) != 4 || files[3] != override {
+		t.Errorf("Expected %s to be read last, got %v", override, files)
+	}
+
+	// Test case 4: Layers that exist must be valid
+	broken := writeConfig(t, "broken.toml", "port =\n")
+	parser = uargs.NewParser(defs, uargs.WithConfigLayers(system, broken))
+	if _, err := parser.ParseArgs(nil); err == nil || !strings.Contains(err.Error(), broken+":1: port") {
+		t.Errorf("Expected an error about %s, got %v", broken, err)
+	}
+}
@@ -0,0 +1,252 @@
+package uargs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// checkNumber verifies that a numeric value is a multiple of its argument's Step
+// and lies within its Min and Max bounds. Arguments with RoundToStep or
+// ClampToRange have the value adjusted instead, with a warning, and the adjusted
+// value is returned.
+func (p *Parser) checkNumber(def ArgDef, v float64) (float64, error) {
+	if def.Step > 0 {
+		p.debug("validator run", "flag", def.Name, "validator", "step", "value", redactValue(def, v))
+		// A small tolerance keeps fractional steps such as 0.1 from failing on
+		// floating-point noise
+		ratio := v / def.Step
+		if rounded := math.Round(ratio) * def.Step; math.Abs(ratio-math.Round(ratio)) > 1e-9 {
+			if !def.RoundToStep {
+				return 0, fmt.Errorf("%s must be a multiple of %s, got %v", flagName(def), formatNumber(def.Step), redactValue(def, formatNumber(v)))
+			}
+			p.warnf("%s value %v rounded to %v", flagName(def), redactValue(def, formatNumber(v)), redactValue(def, formatNumber(rounded)))
+			v = rounded
+		}
+	}
+	if def.Min == nil && def.Max == nil {
+		return v, nil
+	}
+	p.debug("validator run", "flag", def.Name, "validator", "range", "value", redactValue(def, v))
+	clamped := v
+	if def.Min != nil && v < *def.Min {
+		clamped = *def.Min
+	}
+	if def.Max != nil && v > *def.Max {
+		clamped = *def.Max
+	}
+	if clamped == v {
+		return v, nil
+	}
+	if !def.ClampToRange {
+		return 0, fmt.Errorf("%s value %v out of range %s", flagName(def), redactValue(def, formatNumber(v)), rangeText(def))
+	}
+	p.warnf("%s value %v clamped to %v", flagName(def), redactValue(def, formatNumber(v)), redactValue(def, formatNumber(clamped)))
+	return clamped, nil
+}
+
+// checkInt is checkNumber for Int values. Bounds, clamping, and rounding are
+// done in integer space so that values beyond 2^53 are kept exactly.
+func (p *Parser) checkInt(def ArgDef, n int) (int, error) {
+	if def.Step > 0 {
+		p.debug("validator run", "flag", def.Name, "validator", "step", "value", redactValue(def, n))
+		step := int(def.Step)
+		if r := n % step; r != 0 {
+			// Round half away from zero, as math.Round does for floats
+			rounded := n - r
+			if 2*max(r, -r) >= step {
+				if r > 0 {
+					rounded += step
+				} else {
+					rounded -= step
+				}
+			}
+			if !def.RoundToStep {
+				return 0, fmt.Errorf("%s must be a multiple of %s, got %v", flagName(def), formatNumber(def.Step), redactValue(def, n))
+			}
+			p.warnf("%s value %v rounded to %v", flagName(def), redactValue(def, n), redactValue(def, rounded))
+			n = rounded
+		}
+	}
+	if def.Min == nil && def.Max == nil {
+		return n, nil
+	}
+	p.debug("validator run", "flag", def.Name, "validator", "range", "value", redactValue(def, n))
+	clamped := n
+	if def.Min != nil && n < toInt(math.Ceil(*def.Min)) {
+		clamped = toInt(math.Ceil(*def.Min))
+	}
+	if def.Max != nil && n > toInt(math.Floor(*def.Max)) {
+		clamped = toInt(math.Floor(*def.Max))
+	}
+	if clamped == n {
+		return n, nil
+	}
+	if !def.ClampToRange {
+		return 0, fmt.Errorf("%s value %v out of range %s", flagName(def), redactValue(def, n), rangeText(def))
+	}
+	p.warnf("%s value %v clamped to %v", flagName(def), redactValue(def, n), redactValue(def, clamped))
+	return clamped, nil
+}
+
+// toInt converts the whole number f to an int, saturating at the limits of int
+func toInt(f float64) int {
+	switch {
+	case f >= -math.MinInt:
+		return math.MaxInt
+	case f <= math.MinInt:
+		return math.MinInt
+	}
+	return int(f)
+}
+
+// checkString verifies that a string value respects the MinLen and MaxLen of its
+// argument. Lengths are counted in characters, not bytes.
+func (p *Parser) checkString(def ArgDef, s string) error {
+	if def.MinLen == 0 && def.MaxLen == 0 {
+		return nil
+	}
+	p.debug("validator run", "flag", def.Name, "validator", "length")
+	n := utf8.RuneCountInString(s)
+	switch {
+	case n == 0 && def.MinLen > 0:
+		return fmt.Errorf("%s must not be empty", flagName(def))
+	case n < def.MinLen:
+		return fmt.Errorf("%s must be at least %d characters, got %d", flagName(def), def.MinLen, n)
+	case def.MaxLen > 0 && n > def.MaxLen:
+		return fmt.Errorf("%s must be at most %d characters, got %d", flagName(def), def.MaxLen, n)
+	}
+	return nil
+}
+
+// checkFile verifies the permissions a File argument asks for: Readable,
+// Writable (or creatable in an existing directory), and Executable
+func checkFile(def ArgDef, path string) error {
+	if def.Readable {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("%s: file is not readable: %v", flagName(def), err)
+		}
+		f.Close()
+	}
+	if def.Writable {
+		f, err := os.OpenFile(path, os.O_WRONLY, 0)
+		switch {
+		case err == nil:
+			f.Close()
+		case errors.Is(err, fs.ErrNotExist):
+			dir := filepath.Dir(path)
+			if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+				return fmt.Errorf("%s: directory %s does not exist", flagName(def), dir)
+			}
+		default:
+			return fmt.Errorf("%s: file is not writable: %v", flagName(def), err)
+		}
+	}
+	if def.Executable {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("%s: file is not executable: %v", flagName(def), err)
+		}
+		if info.IsDir() || info.Mode().Perm()&0o111 == 0 {
+			return fmt.Errorf("%s: file %s is not executable", flagName(def), path)
+		}
+	}
+	return nil
+}
+
+// checkChoice resolves s to one of the argument's Choices, following
+// ChoiceAliases and IgnoreCase, and returns the canonical choice
+func checkChoice(def ArgDef, s string) (string, error) {
+	equal := func(a, b string) bool {
+		return a == b || (def.IgnoreCase && strings.EqualFold(a, b))
+	}
+	for _, choice := range def.Choices {
+		if equal(s, choice) {
+			return choice, nil
+		}
+	}
+	for alias, choice := range def.ChoiceAliases {
+		if equal(s, alias) {
+			return choice, nil
+		}
+	}
+	return "", fmt.Errorf("%s must be one of %s, got '%s'%s", flagName(def), strings.Join(def.Choices, ", "), s, exampleHint(def))
+}
+
+// checkConflicts reports the first pair of given arguments that were declared
+// mutually exclusive through ConflictsWith
+func (p *Parser) checkConflicts(used map[string]bool) error {
+	names := make([]string, 0, len(used))
+	for name := range used {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, other := range p.defs[name].ConflictsWith {
+			if used[other] {
+				return fmt.Errorf("arguments %s and %s cannot be used together", p.flagNameOf(name), p.flagNameOf(other))
+			}
+		}
+	}
+	return nil
+}
+
+// AddValidator registers a check over the complete set of parsed values, run
+// after every argument has been parsed and defaults applied. Use it for
+// invariants between arguments, such as an end date after a start date. The
+// first error returned by a validator fails the parse.
+//
+// Example:
+//
+//	parser.AddValidator(func(parsed map[string]interface{}) error {
+//		if parsed["end"].(int) < parsed["start"].(int) {
+//			return errors.New("--end must not be before --start")
+//		}
+//		return nil
+//	})
+func (p *Parser) AddValidator(fn func(parsed map[string]interface{}) error) {
+	if p == nil || fn == nil {
+		return
+	}
+	p.validators = append(p.validators, fn)
+}
+
+// runValidators runs the validators added with AddValidator in order
+func (p *Parser) runValidators() error {
+	for i, fn := range p.validators {
+		p.debug("validator run", "validator", "parser", "index", i)
+		if err := fn(p.parsed); err != nil {
+			return classify(validationErrorKind, err)
+		}
+	}
+	return nil
+}
+
+// rangeText describes the bounds of an argument in interval notation, such as
+// [1, 65535] or [0, +inf). It returns "" when the argument has no bounds.
+func rangeText(def ArgDef) string {
+	if def.Min == nil && def.Max == nil {
+		return ""
+	}
+	lo, hi := "(-inf", "+inf)"
+	if def.Min != nil {
+		lo = "[" + formatNumber(*def.Min)
+	}
+	if def.Max != nil {
+		hi = formatNumber(*def.Max) + "]"
+	}
+	return lo + ", " + hi
+}
+
+// formatNumber formats v without trailing zeros, so integral bounds print as integers
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,345 @@
+package uargs_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestMinMax tests numeric range constraints
+func TestMinMax(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "port", Usage: "Port", Min: uargs.Bound(1), Max: uargs.Bound(65535), Type: uargs.Int},
+		{Name: "rate", Usage: "Rate", Min: uargs.Bound(0), Type: uargs.Float},
+	}
+	parser := uargs.NewParser(args)
+
+	parsed, err := parser.ParseArgs([]string{"--port", "8080", "--rate", "0"})
+	if err != nil {
+		t.Fatalf("Failed to parse valid arguments: %v", err)
+	}
+	if parsed["port"] != 8080 {
+		t.Errorf("Expected port=8080, got %v", parsed["port"])
+	}
+
+	_, err = parser.ParseArgs([]string{"--port", "70000"})
+	if err == nil || !strings.Contains(err.Error(), "out of range [1, 65535]") {
+		t.Errorf("Expected out of range error for port, got %v", err)
+	}
+
+	_, err = parser.ParseArgs([]string{"--rate", "-1"})
+	if err == nil || !strings.Contains(err.Error(), "out of range [0, +inf)") {
+		t.Errorf("Expected out of range error for rate, got %v", err)
+	}
+
+	for _, word := range []string{"-inf", "-Inf", "-infinity"} {
+		_, err = parser.ParseArgs([]string{"--rate", word})
+		if want := "invalid short argument usage: " + word; err == nil || err.Error() != want {
+			t.Errorf("Expected %q not to be taken as a number, got %v", word, err)
+		}
+	}
+
+	if !strings.Contains(parser.Usage(), "Port (range: [1, 65535])") {
+		t.Errorf("Expected range in usage, got %q", parser.Usage())
+	}
+}
+
+// TestMinMaxLen tests string length constraints
+func TestMinMaxLen(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "name", Usage: "Name", MinLen: 1, MaxLen: 5, Type: uargs.String},
+		{Name: "code", Usage: "Code", MinLen: 3, Type: uargs.String},
+	}
+	parser := uargs.NewParser(args)
+
+	if _, err := parser.ParseArgs([]string{"--name", "héllo", "--code", "abc"}); err != nil {
+		t.Fatalf("Failed to parse valid arguments: %v", err)
+	}
+
+	tests := []struct {
+		argv []string
+		want string
+	}{
+		{[]string{"--name", ""}, "--name must not be empty"},
+		{[]string{"--name", "too long"}, "--name must be at most 5 characters, got 8"},
+		{[]string{"--code", "ab"}, "--code must be at least 3 characters, got 2"},
+	}
+	for _, tt := range tests {
+		_, err := parser.ParseArgs(tt.argv)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("Expected error %q for %v, got %v", tt.want, tt.argv, err)
+		}
+	}
+
+	bad := []uargs.ArgDef{{Name: "name", MinLen: 5, MaxLen: 2, Type: uargs.String}}
+	if _, err := uargs.NewParser(bad).ParseArgs(nil); err == nil {
+		t.Error("Expected error for MinLen greater than MaxLen, got nil")
+	}
+}
+
+// TestConflictsWith tests mutually exclusive arguments
+func TestConflictsWith(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "json", Usage: "JSON output", NumArgs: 0, ConflictsWith: []string{"yaml"}, Type: uargs.String},
+		{Name: "yaml", Usage: "YAML output", Type: uargs.String},
+	}
+	parser := uargs.NewParser(args)
+
+	if _, err := parser.ParseArgs([]string{"--yaml"}); err != nil {
+		t.Fatalf("Failed to parse valid arguments: %v", err)
+	}
+
+	_, err := parser.ParseArgs([]string{"--yaml", "--json"})
+	if err == nil || err.Error() != "arguments --json and --yaml cannot be used together" {
+		t.Errorf("Expected conflict error, got %v", err)
+	}
+
+	if !strings.Contains(parser.Usage(), "JSON output (conflicts with --yaml)") {
+		t.Errorf("Expected conflict note in usage, got %q", parser.Usage())
+	}
+}
+
+// TestClampAndRound tests numeric adjustment policies
+func TestClampAndRound(t *testing.T) {
+	var warnings bytes.Buffer
+	args := []uargs.ArgDef{
+		{Name: "port", Usage: "Port", Min: uargs.Bound(1), Max: uargs.Bound(65535), ClampToRange: true, Type: uargs.Int},
+		{Name: "chunk", Usage: "Chunk size", Step: 4096, RoundToStep: true, Type: uargs.Int},
+		{Name: "ratio", Usage: "Ratio", Step: 0.25, RoundToStep: true, Max: uargs.Bound(1), ClampToRange: true, Type: uargs.Float},
+	}
+	parser := uargs.NewParser(args, uargs.WithWarnings(&warnings))
+
+	parsed, err := parser.ParseArgs([]string{"--port", "70000", "--chunk", "5000", "--ratio", "1.2"})
+	if err != nil {
+		t.Fatalf("Failed to parse valid arguments: %v", err)
+	}
+	if parsed["port"] != 65535 {
+		t.Errorf("Expected port clamped to 65535, got %v", parsed["port"])
+	}
+	if parsed["chunk"] != 4096 {
+		t.Errorf("Expected chunk rounded to 4096, got %v", parsed["chunk"])
+	}
+	if parsed["ratio"] != 1.0 {
+		t.Errorf("Expected ratio rounded then clamped to 1, got %v", parsed["ratio"])
+	}
+	for _, want := range []string{
+		"warning: --port value 70000 clamped to 65535",
+		"warning: --chunk value 5000 rounded to 4096",
+		"warning: --ratio value 1.25 clamped to 1",
+	} {
+		if !strings.Contains(warnings.String(), want) {
+			t.Errorf("Expected warning %q, got %q", want, warnings.String())
+		}
+	}
+
+	bad := []uargs.ArgDef{{Name: "n", RoundToStep: true, Type: uargs.Int}}
+	if _, err := uargs.NewParser(bad).ParseArgs(nil); err == nil {
+		t.Error("Expected error for RoundToStep without Step, got nil")
+	}
+}
+
+// TestStep tests the multiple-of constraint
+func TestStep(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "chunk-size", Usage: "Chunk size", Step: 4096, Type: uargs.Int},
+		{Name: "ratio", Usage: "Ratio", Step: 0.1, Type: uargs.Float},
+	}
+	parser := uargs.NewParser(args)
+
+	if _, err := parser.ParseArgs([]string{"--chunk-size", "8192", "--ratio", "0.3"}); err != nil {
+		t.Fatalf("Failed to parse valid arguments: %v", err)
+	}
+
+	_, err := parser.ParseArgs([]string{"--chunk-size", "5000"})
+	if err == nil || err.Error() != "--chunk-size must be a multiple of 4096, got 5000" {
+		t.Errorf("Expected step error, got %v", err)
+	}
+
+	if !strings.Contains(parser.Usage(), "Chunk size (multiple of 4096)") {
+		t.Errorf("Expected step in usage, got %q", parser.Usage())
+	}
+}
+
+// TestLargeInts tests that Int values beyond 2^53 are kept exactly through
+// range and step checks
+func TestLargeInts(t *testing.T) {
+	var warnings bytes.Buffer
+	args := []uargs.ArgDef{
+		{Name: "id", Usage: "ID", Type: uargs.Int},
+		{Name: "offset", Usage: "Offset", Min: uargs.Bound(0), Type: uargs.Int},
+		{Name: "even", Usage: "Even number", Step: 2, RoundToStep: true, Type: uargs.Int},
+		{Name: "small", Usage: "Small number", Max: uargs.Bound(100.5), ClampToRange: true, Type: uargs.Int},
+	}
+	parser := uargs.NewParser(args, uargs.WithWarnings(&warnings))
+
+	// Test case 1: Values are not rounded through float64
+	parsed, err := parser.ParseArgs([]string{"--id", "9007199254740993", "--offset", "9007199254740993", "--even", "9007199254740993", "--small", "9007199254740993"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parsed["id"] != 9007199254740993 || parsed["offset"] != 9007199254740993 {
+		t.Errorf("Expected 9007199254740993 kept exactly, got %v and %v", parsed["id"], parsed["offset"])
+	}
+	if parsed["even"] != 9007199254740994 || !strings.Contains(warnings.String(), "--even value 9007199254740993 rounded to 9007199254740994") {
+		t.Errorf("Expected rounding to 9007199254740994, got %v (%q)", parsed["even"], warnings.String())
+	}
+	if parsed["small"] != 100 {
+		t.Errorf("Expected small clamped to 100, got %v", parsed["small"])
+	}
+
+	// Test case 2: Range and step errors show the exact value
+	parser = uargs.NewParser([]uargs.ArgDef{{Name: "n", Usage: "N", Max: uargs.Bound(10), Step: 2, Type: uargs.Int}})
+	if _, err = parser.ParseArgs([]string{"--n", "9007199254740993"}); err == nil || err.Error() != "--n must be a multiple of 2, got 9007199254740993" {
+		t.Errorf("Expected a step error with the exact value, got %v", err)
+	}
+	if _, err = parser.ParseArgs([]string{"--n", "9007199254740994"}); err == nil || err.Error() != "--n value 9007199254740994 out of range (-inf, 10]" {
+		t.Errorf("Expected a range error with the exact value, got %v", err)
+	}
+
+	// Test case 3: Int arguments need a whole Step
+	if _, err = uargs.NewParserE([]uargs.ArgDef{{Name: "n", Usage: "N", Step: 0.5, Type: uargs.Int}}); err == nil || err.Error() != "--n takes integers and needs a whole Step, got 0.5" {
+		t.Errorf("Expected an error about the fractional Step, got %v", err)
+	}
+}
+
+// TestAddValidator tests cross-argument checks run after parsing
+func TestAddValidator(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "start", Usage: "Start", Type: uargs.Int, Default: 0},
+		{Name: "end", Usage: "End", Type: uargs.Int, Default: 10},
+	}
+	parser := uargs.NewParser(args)
+	var order []string
+	parser.AddValidator(func(parsed map[string]interface{}) error {
+		order = append(order, "first")
+		if parsed["end"].(int) < parsed["start"].(int) {
+			return errors.New("--end must not be before --start")
+		}
+		return nil
+	})
+	parser.AddValidator(func(parsed map[string]interface{}) error {
+		order = append(order, "second")
+		return nil
+	})
+
+	if _, err := parser.ParseArgs([]string{"--start", "3"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(order, ",") != "first,second" {
+		t.Errorf("Expected validators to run in order, got %v", order)
+	}
+
+	order = nil
+	_, err := parser.ParseArgs([]string{"--start", "20"})
+	if err == nil || err.Error() != "--end must not be before --start" {
+		t.Errorf("Expected validator error against the default end, got %v", err)
+	}
+	if len(order) != 1 {
+		t.Errorf("Expected validation to stop at the first error, got %v", order)
+	}
+	if parser.Result() != nil {
+		t.Errorf("Expected no result after a failed validator")
+	}
+}
+
+// TestChoices tests restricted values with case folding and aliases
+func TestChoices(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "confirm", Usage: "Confirm", Choices: []string{"yes", "no"}, IgnoreCase: true,
+			ChoiceAliases: map[string]string{"y": "yes", "n": "no"}},
+		{Name: "level", Usage: "Level", Type: uargs.Int, Choices: []string{"1", "2", "3"}},
+		{Name: "mode", Usage: "Mode", Choices: []string{"fast", "Safe"}},
+	}
+	parser := uargs.NewParser(args)
+
+	tests := []struct {
+		argv []string
+		name string
+		want interface{}
+	}{
+		{[]string{"--confirm", "yes"}, "confirm", "yes"},
+		{[]string{"--confirm", "YES"}, "confirm", "yes"},
+		{[]string{"--confirm", "Y"}, "confirm", "yes"},
+		{[]string{"--confirm", "n"}, "confirm", "no"},
+		{[]string{"--level", "2"}, "level", 2},
+		{[]string{"--mode", "Safe"}, "mode", "Safe"},
+	}
+	for _, tt := range tests {
+		parsed, err := parser.ParseArgs(tt.argv)
+		if err != nil {
+			t.Errorf("%v: unexpected error %v", tt.argv, err)
+			continue
+		}
+		if parsed[tt.name] != tt.want {
+			t.Errorf("%v: expected %v, got %v", tt.argv, tt.want, parsed[tt.name])
+		}
+	}
+
+	_, err := parser.ParseArgs([]string{"--mode", "safe"})
+	if err == nil || err.Error() != "--mode must be one of fast, Safe, got 'safe'" {
+		t.Errorf("Expected case-sensitive choice error, got %v", err)
+	}
+	if _, err := parser.ParseArgs([]string{"--level", "4"}); err == nil {
+		t.Errorf("Expected error for a level outside its choices")
+	}
+	if !strings.Contains(parser.Usage(), "(one of: yes, no)") {
+		t.Errorf("Expected choices in usage, got:\n%s", parser.Usage())
+	}
+
+	bad := uargs.NewParser([]uargs.ArgDef{{Name: "x", Choices: []string{"a"}, ChoiceAliases: map[string]string{"b": "c"}}})
+	if _, err := bad.ParseArgs(nil); err == nil || !strings.Contains(err.Error(), "not one of its Choices") {
+		t.Errorf("Expected error for an alias of an unknown choice, got %v", err)
+	}
+}
+
+// TestFilePermissions tests Readable, Writable, and Executable File arguments
+func TestFilePermissions(t *testing.T) {
+	dir := t.TempDir()
+	data := filepath.Join(dir, "data.txt")
+	script := filepath.Join(dir, "run.sh")
+	if err := os.WriteFile(data, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(script, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	args := []uargs.ArgDef{
+		{Name: "input", Usage: "Input", Type: uargs.File, Readable: true},
+		{Name: "output", Usage: "Output", Type: uargs.File, Writable: true},
+		{Name: "hook", Usage: "Hook", Type: uargs.File, Executable: true},
+	}
+	parser := uargs.NewParser(args)
+
+	valid := [][]string{
+		{"--input", data},
+		{"--input", "-"},
+		{"--output", data},
+		{"--output", filepath.Join(dir, "new.txt")},
+		{"--hook", script},
+	}
+	for _, argv := range valid {
+		if _, err := parser.ParseArgs(argv); err != nil {
+			t.Errorf("%v: unexpected error %v", argv, err)
+		}
+	}
+
+	invalid := map[string][]string{
+		"--input: file is not readable":    {"--input", filepath.Join(dir, "missing.txt")},
+		"--output: directory":              {"--output", filepath.Join(dir, "missing", "out.txt")},
+		"--output: file is not writable":   {"--output", dir},
+		"--hook: file " + data + " is not": {"--hook", data},
+		"--hook: file " + dir + " is not":  {"--hook", dir},
+	}
+	for want, argv := range invalid {
+		_, err := parser.ParseArgs(argv)
+		if err == nil || !strings.HasPrefix(err.Error(), want) {
+			t.Errorf("%v: expected error starting with %q, got %v", argv, want, err)
+		}
+	}
+}
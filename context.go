@@ -0,0 +1,31 @@
+package uargs
+
+import "context"
+
+// resultKey is the context key under which NewContext stores a Result
+type resultKey struct{}
+
+// NewContext returns a copy of ctx carrying result, so code deep in a call
+// stack can read parsed values with FromContext instead of receiving them as
+// parameters.
+//
+// Example:
+//
+//	ctx := uargs.NewContext(context.Background(), parser.Result())
+//	serve(ctx)
+func NewContext(ctx context.Context, result *Result) context.Context {
+	return context.WithValue(ctx, resultKey{}, result)
+}
+
+// FromContext returns the Result stored in ctx by NewContext. It reports false
+// if ctx carries no Result.
+//
+// Example:
+//
+//	if res, ok := uargs.FromContext(ctx); ok {
+//		port, _ := res.Get("port")
+//	}
+func FromContext(ctx context.Context) (*Result, bool) {
+	result, ok := ctx.Value(resultKey{}).(*Result)
+	return result, ok && result != nil
+}
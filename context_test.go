@@ -0,0 +1,32 @@
+package uargs_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestContext tests carrying a Result in a context
+func TestContext(t *testing.T) {
+	parser := uargs.NewParser([]uargs.ArgDef{{Name: "port", Usage: "Port", Type: uargs.Int}})
+	if _, err := parser.ParseArgs([]string{"--port", "8080"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx := uargs.NewContext(context.Background(), parser.Result())
+	res, ok := uargs.FromContext(ctx)
+	if !ok {
+		t.Fatalf("Expected a Result in the context")
+	}
+	if v, _ := res.Get("port"); v != 8080 {
+		t.Errorf("Expected port=8080, got %v", v)
+	}
+
+	if _, ok := uargs.FromContext(context.Background()); ok {
+		t.Errorf("Expected no Result in an empty context")
+	}
+	if _, ok := uargs.FromContext(uargs.NewContext(context.Background(), nil)); ok {
+		t.Errorf("Expected a nil Result to be reported as missing")
+	}
+}
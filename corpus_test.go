@@ -0,0 +1,92 @@
+package uargs_test
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// appendValues is a Merge function collecting repeated occurrences into a slice
+func appendValues(old, new interface{}) (interface{}, error) {
+	if values, ok := old.([]string); ok {
+		return append(values, new.(string)), nil
+	}
+	return []string{old.(string), new.(string)}, nil
+}
+
+// corpusTools defines the flags of the tools whose command lines are in testdata/corpus.txt
+var corpusTools = map[string][]uargs.ArgDef{
+	"git": {
+		{Name: "message", Short: "m", Usage: "Commit message", Type: uargs.String},
+		{Name: "author", Usage: "Commit author", Type: uargs.String},
+		{Name: "depth", Usage: "History depth", Type: uargs.Int},
+		{Name: "paths", Usage: "Limit to paths", Type: uargs.String, Greedy: true},
+	},
+	"docker": {
+		{Name: "publish", Short: "p", Usage: "Published ports", Type: uargs.String, Merge: appendValues},
+		{Name: "env", Short: "e", Usage: "Environment variables", Type: uargs.String, Merge: appendValues},
+		{Name: "name", Usage: "Container name", Type: uargs.String, MinArgs: 1},
+		{Name: "cpus", Usage: "CPU limit", Type: uargs.Float, Min: uargs.Bound(0.01)},
+	},
+	"kubectl": {
+		{Name: "namespace", Short: "n", Usage: "Namespace", Type: uargs.String},
+		{Name: "output", Short: "o", Usage: "Output format", Choices: []string{"json", "yaml", "wide"}, IgnoreCase: true},
+		{Name: "selector", Short: "l", Usage: "Label selector", Type: uargs.String},
+		{Name: "tail", Usage: "Lines to show", Type: uargs.Int},
+		{Name: "replicas", Usage: "Replica count", Type: uargs.Int, Min: uargs.Bound(0)},
+	},
+}
+
+// TestCorpus tests that real-world command line shapes parse as documented in testdata/corpus.txt
+func TestCorpus(t *testing.T) {
+	f, err := os.Open("testdata/corpus.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			t.Fatalf("line %d: expected 3 tab-separated fields, got %d", n, len(fields))
+		}
+		defs, ok := corpusTools[fields[0]]
+		if !ok {
+			t.Fatalf("line %d: unknown tool %s", n, fields[0])
+		}
+		argv, err := uargs.Split(fields[1])
+		if err != nil {
+			t.Fatalf("line %d: %v", n, err)
+		}
+
+		parsed, err := uargs.NewParser(defs).ParseArgs(argv)
+		if want, ok := strings.CutPrefix(fields[2], "error: "); ok {
+			if err == nil || !strings.HasPrefix(err.Error(), want) {
+				t.Errorf("line %d: expected error %q, got %v", n, want, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("line %d: unexpected error %v", n, err)
+			continue
+		}
+		for _, pair := range strings.Split(fields[2], "; ") {
+			name, want, _ := strings.Cut(pair, "=")
+			if got := fmt.Sprint(parsed[name]); got != want {
+				t.Errorf("line %d: expected %s=%s, got %s", n, name, want, got)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,76 @@
+package uargs
+
+import "fmt"
+
+// checkDefault verifies that the Default of an argument matches its Type and
+// returns it normalized (int defaults of Float arguments become float64)
+func checkDefault(def ArgDef) (interface{}, error) {
+	if def.Default == nil {
+		return nil, nil
+	}
+	ok := false
+	switch v := def.Default.(type) {
+	case string, []string:
+		ok = valueType(def) == String || valueType(def) == File
+	case int:
+		if valueType(def) == Float {
+			return float64(v), nil
+		}
+		ok = valueType(def) == Int
+	case []int:
+		if valueType(def) == Float {
+			floats := make([]float64, len(v))
+			for i, n := range v {
+				floats[i] = float64(n)
+			}
+			return floats, nil
+		}
+		ok = valueType(def) == Int
+	case float64, []float64:
+		ok = valueType(def) == Float
+	}
+	if !ok {
+		return nil, fmt.Errorf("default value %v (%T) for --%s does not match type %s", redactValue(def, def.Default), def.Default, def.Name, valueType(def))
+	}
+	return def.Default, nil
+}
+
+// applyDefaults stores the default of every argument that was not parsed,
+// calling DefaultFunc where one is set
+func (p *Parser) applyDefaults() error {
+	for name, def := range p.defs {
+		if _, ok := p.parsed[name]; ok || (p.only != nil && !p.only[name]) {
+			continue
+		}
+		if def.DefaultFunc != nil {
+			v, err := def.DefaultFunc()
+			if err != nil {
+				return fmt.Errorf("default for %s: %v", flagName(def), redactError(def, err, v))
+			}
+			def.Default = v
+			if v, err = checkDefault(def); err != nil {
+				return err
+			}
+			def.Default = v
+		}
+		if def.Default != nil {
+			p.debug("source resolved", "flag", name, "source", "default")
+			p.parsed[name] = def.Default
+		}
+	}
+	return nil
+}
+
+// fillMissing resolves the arguments that were not given on the command line
+// from the value sources and their defaults, without checking required
+// arguments or constraints
+func (p *Parser) fillMissing() error {
+	sources, _, err := p.valueSources()
+	if err != nil {
+		return err
+	}
+	if _, err := p.applySources(sources); err != nil {
+		return err
+	}
+	return p.applyDefaults()
+}
@@ -0,0 +1,101 @@
+package uargs_test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestDefaults tests that defaults fill in missing arguments
+func TestDefaults(t *testing.T) {
+	originalArgs := os.Args
+	defer func() { os.Args = originalArgs }()
+
+	os.Args = []string{"app", "--port", "9000"}
+
+	args := []uargs.ArgDef{
+		{Name: "port", Short: "p", Usage: "Port", Default: 8080, Type: uargs.Int},
+		{Name: "host", Short: "H", Usage: "Host", Default: "localhost", Type: uargs.String},
+		{Name: "rate", Short: "r", Usage: "Rate", Default: 2, Type: uargs.Float},
+	}
+
+	parser := uargs.NewParser(args)
+	parsed, err := parser.Parse()
+	if err != nil {
+		t.Fatalf("Failed to parse valid arguments: %v", err)
+	}
+	if parsed["port"] != 9000 {
+		t.Errorf("Expected port=9000, got %v", parsed["port"])
+	}
+	if parsed["host"] != "localhost" {
+		t.Errorf("Expected host='localhost', got '%v'", parsed["host"])
+	}
+	if parsed["rate"] != 2.0 {
+		t.Errorf("Expected rate=2.0 as float64, got %v (%T)", parsed["rate"], parsed["rate"])
+	}
+	if !strings.Contains(parser.Usage(), "Host (default: localhost)") {
+		t.Errorf("Expected default in usage, got %q", parser.Usage())
+	}
+}
+
+// TestDefaultTypeMismatch tests that a default of the wrong type is rejected
+func TestDefaultTypeMismatch(t *testing.T) {
+	originalArgs := os.Args
+	defer func() { os.Args = originalArgs }()
+
+	os.Args = []string{"app"}
+
+	args := []uargs.ArgDef{
+		{Name: "port", Usage: "Port", Default: "8080", Type: uargs.Int},
+	}
+
+	if _, err := uargs.NewParser(args).Parse(); err == nil {
+		t.Error("Expected error for mismatched default type, got nil")
+	}
+}
+
+// TestDefaultFunc tests lazily computed defaults
+func TestDefaultFunc(t *testing.T) {
+	originalArgs := os.Args
+	defer func() { os.Args = originalArgs }()
+
+	calls := 0
+	args := []uargs.ArgDef{
+		{Name: "workers", Usage: "Worker count", Type: uargs.Int, DefaultFunc: func() (interface{}, error) {
+			calls++
+			return 4, nil
+		}},
+	}
+
+	// Test case 1: The func is not called when the flag is given
+	os.Args = []string{"app", "--workers", "2"}
+	parsed, err := uargs.NewParser(args).Parse()
+	if err != nil {
+		t.Fatalf("Failed to parse valid arguments: %v", err)
+	}
+	if parsed["workers"] != 2 || calls != 0 {
+		t.Errorf("Expected workers=2 without calling DefaultFunc, got %v after %d calls", parsed["workers"], calls)
+	}
+
+	// Test case 2: The func supplies the value when the flag is absent
+	os.Args = []string{"app"}
+	parsed, err = uargs.NewParser(args).Parse()
+	if err != nil {
+		t.Fatalf("Failed to parse valid arguments: %v", err)
+	}
+	if parsed["workers"] != 4 || calls != 1 {
+		t.Errorf("Expected workers=4 after one call, got %v after %d calls", parsed["workers"], calls)
+	}
+
+	// Test case 3: Errors from the func surface as parse errors
+	args[0].DefaultFunc = func() (interface{}, error) {
+		return nil, errors.New("no CPU information")
+	}
+	_, err = uargs.NewParser(args).Parse()
+	if err == nil || !strings.Contains(err.Error(), "no CPU information") {
+		t.Errorf("Expected DefaultFunc error, got %v", err)
+	}
+}
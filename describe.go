@@ -0,0 +1,119 @@
+package uargs
+
+import (
+	"maps"
+	"slices"
+	"sort"
+)
+
+// CommandInfo describes a parser and its subcommands, as returned by Describe.
+// It is a copy: changing it does not affect the parser.
+type CommandInfo struct {
+	// Name is the command's name, or "" for the root parser
+	Name string
+	// Path holds the names of the commands from the root to this one
+	Path []string
+	// Usage is the command's description
+	Usage string
+	// Aliases are alternative names of the command
+	Aliases []string
+	// Group is the heading the command is listed under in help text
+	Group string
+	// Hidden reports whether the command is left out of help text
+	Hidden bool
+	// Default reports whether the command runs when no command is given
+	Default bool
+	// Args describes the command's arguments, sorted by name
+	Args []ArgInfo
+	// Constraints describes the argument groups, in the order they were added
+	Constraints []ConstraintInfo
+	// Commands describes the subcommands, in the order they were added
+	Commands []CommandInfo
+}
+
+// ArgInfo describes an argument, as returned by Describe
+type ArgInfo struct {
+	// ArgDef is a copy of the definition, with Type, NumArgs, and Env set to
+	// the effective values where they were left out
+	ArgDef
+	// Inherited reports whether the argument is a Persistent argument of an
+	// enclosing command
+	Inherited bool
+}
+
+// ConstraintInfo describes a rule over a set of arguments, as added with
+// options such as WithRequiredTogether
+type ConstraintInfo struct {
+	// Kind is "together", "exactly-one", or "at-least-one"
+	Kind string
+	// Names are the long names of the arguments the rule applies to
+	Names []string
+	// Description explains the rule as help text does
+	Description string
+}
+
+// constraintKinds names the group kinds in ConstraintInfo
+var constraintKinds = map[groupKind]string{
+	groupTogether:   "together",
+	groupExactlyOne: "exactly-one",
+	groupAtLeastOne: "at-least-one",
+}
+
+// Describe returns a description of p's arguments, constraints, and commands,
+// including hidden ones, so documentation or other interfaces can be generated
+// from the same definitions as the command line.
+//
+// Example:
+//
+//	info := parser.Describe()
+//	for _, cmd := range info.Commands {
+//		for _, arg := range cmd.Args {
+//			fmt.Printf("%s --%s (%s): %s\n", cmd.Name, arg.Name, arg.Type, arg.Usage)
+//		}
+//	}
+func (p *Parser) Describe() CommandInfo {
+	if p == nil {
+		return CommandInfo{}
+	}
+	info := CommandInfo{Path: p.path()}
+	if c := p.command; c != nil {
+		info.Name, info.Usage, info.Aliases = c.Name, c.Usage, slices.Clone(c.Aliases)
+		info.Group, info.Hidden, info.Default = c.Group, c.Hidden, c.Default
+	}
+	for name, def := range p.defs {
+		arg := ArgInfo{ArgDef: copyDef(def), Inherited: p.inheritedFlags[name]}
+		arg.Env = p.envName(def)
+		info.Args = append(info.Args, arg)
+	}
+	sort.Slice(info.Args, func(i, j int) bool { return info.Args[i].Name < info.Args[j].Name })
+	for _, g := range p.groups {
+		info.Constraints = append(info.Constraints, ConstraintInfo{
+			Kind:        constraintKinds[g.kind],
+			Names:       slices.Clone(g.names),
+			Description: p.describeGroup(g),
+		})
+	}
+	for _, c := range p.commands {
+		info.Commands = append(info.Commands, c.Describe())
+	}
+	return info
+}
+
+// copyDef returns a copy of def that shares no slices, maps, or pointers with
+// it, with the effective Type and the Default of Secret arguments redacted
+func copyDef(def ArgDef) ArgDef {
+	def.Type = valueType(def)
+	def.OptionalIfGiven = slices.Clone(def.OptionalIfGiven)
+	def.RequiredIfGiven = slices.Clone(def.RequiredIfGiven)
+	def.ConflictsWith = slices.Clone(def.ConflictsWith)
+	def.Choices = slices.Clone(def.Choices)
+	def.ChoiceAliases = maps.Clone(def.ChoiceAliases)
+	def.Default = redactValue(def, copyValue(def.Default))
+	if def.Min != nil {
+		def.Min = Bound(*def.Min)
+	}
+	if def.Max != nil {
+		def.Max = Bound(*def.Max)
+	}
+	return def
+}
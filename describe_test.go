@@ -0,0 +1,63 @@
+package uargs_test
+
+import (
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestDescribe tests walking the definitions of a parser and its commands
+func TestDescribe(t *testing.T) {
+	parser := uargs.NewParser([]uargs.ArgDef{
+		{Name: "verbose", Short: "v", Usage: "Verbosity", Type: uargs.Int, Default: 0, Persistent: true},
+		{Name: "json", Usage: "JSON output"},
+		{Name: "yaml", Usage: "YAML output"},
+		{Name: "level", Usage: "Level", Type: uargs.Int, Min: uargs.Bound(1), Choices: []string{"1", "2"}},
+	}, uargs.WithExactlyOneOf("json", "yaml"))
+	remote := parser.AddCommand(uargs.Command{Name: "remote", Usage: "Manage remotes", Aliases: []string{"r"}, Group: "Management"})
+	remote.AddCommand(uargs.Command{Name: "add", Usage: "Add a remote", Args: []uargs.ArgDef{
+		{Name: "url", Usage: "Remote URL", Type: uargs.String, Required: true},
+	}})
+	parser.AddCommand(uargs.Command{Name: "debug", Hidden: true})
+
+	info := parser.Describe()
+	if info.Name != "" || len(info.Args) != 4 || info.Args[0].Name != "json" || info.Args[3].Name != "yaml" {
+		t.Fatalf("Expected the root arguments sorted by name, got %+v", info.Args)
+	}
+	if info.Args[0].Type != uargs.String || info.Args[0].NumArgs != 1 {
+		t.Errorf("Expected effective type string and 1 value, got %s and %d", info.Args[0].Type, info.Args[0].NumArgs)
+	}
+	if len(info.Constraints) != 1 || info.Constraints[0].Kind != "exactly-one" || info.Constraints[0].Description != "exactly one of --json | --yaml is required" {
+		t.Errorf("Expected the exactly-one constraint, got %+v", info.Constraints)
+	}
+	if len(info.Commands) != 2 || info.Commands[0].Name != "remote" || !info.Commands[1].Hidden {
+		t.Fatalf("Expected remote and the hidden debug command, got %+v", info.Commands)
+	}
+	remoteInfo := info.Commands[0]
+	if remoteInfo.Group != "Management" || len(remoteInfo.Aliases) != 1 || remoteInfo.Usage != "Manage remotes" {
+		t.Errorf("Expected the remote command's details, got %+v", remoteInfo)
+	}
+	add := remoteInfo.Commands[0]
+	if len(add.Path) != 2 || add.Path[1] != "add" || len(add.Args) != 2 {
+		t.Fatalf("Expected remote add with url and verbose, got %+v", add)
+	}
+	if add.Args[0].Name != "url" || add.Args[0].Inherited || add.Args[1].Name != "verbose" || !add.Args[1].Inherited {
+		t.Errorf("Expected verbose to be inherited, got %+v", add.Args)
+	}
+
+	// The description is a copy
+	info.Args[1].Choices[0] = "9"
+	*info.Args[1].Min = 9
+	again := parser.Describe()
+	if again.Args[1].Choices[0] != "1" || *again.Args[1].Min != 1 {
+		t.Errorf("Expected changes to the description not to affect the parser")
+	}
+
+	// The defaults of Secret arguments are redacted
+	secret := uargs.NewParser([]uargs.ArgDef{
+		{Name: "token", Usage: "API token", Type: uargs.String, Secret: true, Default: "hunter2"},
+	}).Describe()
+	if len(secret.Args) != 1 || secret.Args[0].Default != "[redacted]" {
+		t.Errorf("Expected the token default redacted, got %+v", secret.Args)
+	}
+}
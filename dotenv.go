@@ -0,0 +1,124 @@
+package uargs
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// DotEnvFile is the file read by WithDotEnv when no path is given
+const DotEnvFile = ".env"
+
+// lookupEnv returns the value of the environment variable name, falling back
+// to the dotenv file set with WithDotEnv, and whether it came from the file.
+// Variables that are empty count as unset, and variables of the process take
+// precedence over the file.
+func (p *Parser) lookupEnv(name string, dotEnv map[string]string) (string, bool) {
+	if v := os.Getenv(name); v != "" {
+		return v, false
+	}
+	return dotEnv[name], dotEnv[name] != ""
+}
+
+// dotEnvFile returns the path of the dotenv file set with WithDotEnv
+func (p *Parser) dotEnvFile() string {
+	if p.dotEnvPath == "" {
+		return DotEnvFile
+	}
+	return p.dotEnvPath
+}
+
+// loadDotEnv reads the dotenv file set with WithDotEnv. A missing file is only
+// an error if its path was given explicitly.
+func (p *Parser) loadDotEnv() (map[string]string, error) {
+	if !p.dotEnv {
+		return nil, nil
+	}
+	path := p.dotEnvFile()
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) && p.dotEnvPath == "" {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseDotEnv(f, path)
+}
+
+// parseDotEnv reads KEY=VALUE lines. Blank lines and lines starting with # are
+// skipped, and a leading "export " is ignored. Values may be quoted: single
+// quotes keep their content as is, and double quotes support the escapes \n,
+// \t, \", and \\. In unquoted values, a # after whitespace starts a comment.
+func parseDotEnv(r io.Reader, path string) (map[string]string, error) {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, n)
+		}
+		value, err := dotEnvValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %s: %v", path, n, key, err)
+		}
+		values[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return values, nil
+}
+
+// dotEnvValue unquotes the value of a dotenv line
+func dotEnvValue(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+	switch quote := s[0]; quote {
+	case '\'':
+		end := strings.IndexByte(s[1:], '\'')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated quote %c", quote)
+		}
+		return s[1 : end+1], nil
+	case '"':
+		var b strings.Builder
+		for i := 1; i < len(s); i++ {
+			switch c := s[i]; {
+			case c == '"':
+				return b.String(), nil
+			case c == '\\' && i+1 < len(s):
+				i++
+				switch s[i] {
+				case 'n':
+					b.WriteByte('\n')
+				case 't':
+					b.WriteByte('\t')
+				default:
+					b.WriteByte(s[i])
+				}
+			default:
+				b.WriteByte(c)
+			}
+		}
+		return "", fmt.Errorf("unterminated quote %c", quote)
+	}
+	for i := 1; i < len(s); i++ {
+		if s[i] == '#' && (s[i-1] == ' ' || s[i-1] == '\t') {
+			s = s[:i]
+			break
+		}
+	}
+	return strings.TrimSpace(s), nil
+}
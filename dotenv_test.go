@@ -0,0 +1,84 @@
+package uargs_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestDotEnv tests reading environment variables from a dotenv file
+func TestDotEnv(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.env")
+	content := `# settings
+export MYAPP_PORT=8080
+MYAPP_NAME = "my app\tv2" 
+MYAPP_TAGS='a "b c"' # tags
+MYAPP_HOST=example.com # host
+MYAPP_TOKEN=file-token
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	defs := []uargs.ArgDef{
+		{Name: "port", Usage: "Port", Type: uargs.Int},
+		{Name: "name", Usage: "Name", Type: uargs.String},
+		{Name: "tags", Usage: "Tags", Type: uargs.String, NumArgs: 2},
+		{Name: "host", Usage: "Host", Type: uargs.String},
+		{Name: "token", Usage: "Token", Type: uargs.String},
+	}
+	parser := uargs.NewParser(defs, uargs.WithEnvPrefix("MYAPP"), uargs.WithDotEnv(path))
+
+	// Test case 1: File entries fill absent arguments, and the process environment wins
+	t.Setenv("MYAPP_TOKEN", "env-token")
+	parsed, err := parser.ParseArgs([]string{"--host", "localhost"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parsed["port"] != 8080 || parsed["name"] != "my app\tv2" || parsed["host"] != "localhost" || parsed["token"] != "env-token" {
+		t.Errorf("Expected values from the file, the command line, and the environment, got %v", parsed)
+	}
+	if tags, ok := parsed["tags"].([]string); !ok || strings.Join(tags, "|") != "a|b c" {
+		t.Errorf("Expected tags a and b c, got %v", parsed["tags"])
+	}
+	if origin := parser.Result().Origin("port"); origin != uargs.OriginEnv {
+		t.Errorf("Expected origin env, got %s", origin)
+	}
+	if _, ok := os.LookupEnv("MYAPP_PORT"); ok {
+		t.Errorf("Expected the process environment to be unchanged")
+	}
+
+	// Test case 2: Invalid lines report the file and line
+	if err := os.WriteFile(path, []byte("MYAPP_PORT=1\nMYAPP_NAME\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parser.ParseArgs(nil); err == nil || err.Error() != path+":2: expected KEY=VALUE" {
+		t.Errorf("Expected a syntax error, got %v", err)
+	}
+	if err := os.WriteFile(path, []byte(`MYAPP_NAME="open`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parser.ParseArgs(nil); err == nil || err.Error() != path+":1: MYAPP_NAME: unterminated quote \"" {
+		t.Errorf("Expected an unterminated quote error, got %v", err)
+	}
+
+	// Test case 3: An explicit file must exist, but the default one may be missing
+	missing := uargs.NewParser(defs, uargs.WithEnvPrefix("MYAPP"), uargs.WithDotEnv(filepath.Join(dir, "missing.env")))
+	if _, err := missing.ParseArgs(nil); err == nil {
+		t.Errorf("Expected an error for a missing file")
+	}
+	t.Chdir(dir)
+	parser = uargs.NewParser(defs, uargs.WithEnvPrefix("MYAPP"), uargs.WithDotEnv(""))
+	if _, err := parser.ParseArgs(nil); err != nil {
+		t.Fatalf("Unexpected error without a .env file: %v", err)
+	}
+	if err := os.WriteFile(uargs.DotEnvFile, []byte("MYAPP_PORT=9000\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if parsed, err := parser.ParseArgs(nil); err != nil || parsed["port"] != 9000 {
+		t.Errorf("Expected port=9000 from .env, got %v (%v)", parsed["port"], err)
+	}
+}
@@ -0,0 +1,70 @@
+package uargs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// envName returns the environment variable of def: its Env, or a name derived
+// from the prefix set with WithEnvPrefix, as in MYAPP_LOG_LEVEL for
+// --log-level. It returns "" if the argument has no variable.
+func (p *Parser) envName(def ArgDef) string {
+	switch {
+	case def.Env == "-":
+		return ""
+	case def.Env != "":
+		return def.Env
+	case p.envPrefix != "":
+		return p.envPrefix + "_" + strings.ToUpper(strings.ReplaceAll(def.Name, "-", "_"))
+	}
+	return ""
+}
+
+// settingHint tells where the EnvOnly argument def can be set, as in " (set
+// the environment variable MYAPP_TOKEN or the config key token)", or returns
+// "" if there is no such place
+func (p *Parser) settingHint(def ArgDef) string {
+	var places []string
+	if env := p.envName(def); env != "" {
+		places = append(places, "the environment variable "+env)
+	}
+	if p.configPath != "" || p.configFlag || p.configApp != "" || len(p.configLayers) > 0 {
+		places = append(places, "the config key "+p.configKeys(def.Name)[0])
+	}
+	if len(places) == 0 {
+		return ""
+	}
+	return " (set " + strings.Join(places, " or ") + ")"
+}
+
+// envSource looks up the environment variables of arguments, in the process
+// environment or the dotenv file
+type envSource struct {
+	p      *Parser
+	dotEnv map[string]string // Variables of the dotenv file set with WithDotEnv
+}
+
+// Lookup returns the value of the environment variable of def, if it is set to
+// a non-empty value. Multi-value arguments split the variable with Split.
+func (s *envSource) Lookup(path []string, def ArgDef) (SourceValue, bool, error) {
+	env := s.p.envName(def)
+	if env == "" {
+		return SourceValue{}, false, nil
+	}
+	raw, fromFile := s.p.lookupEnv(env, s.dotEnv)
+	if raw == "" {
+		return SourceValue{}, false, nil
+	}
+	v := SourceValue{Args: []string{raw}, Source: Source{Origin: OriginEnv, Env: env}}
+	if fromFile {
+		v.Source.File = s.p.dotEnvFile()
+	}
+	if maxArgs(def) != 1 {
+		var err error
+		if v.Args, err = Split(raw); err != nil {
+			err = fmt.Errorf("invalid value for %s: %v (from environment variable %s)", flagName(def), err, env)
+			return SourceValue{}, false, argError(def, classify(validationErrorKind, err))
+		}
+	}
+	return v, true, nil
+}
@@ -0,0 +1,226 @@
+package uargs_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestEnv tests falling back to environment variables for absent flags
+func TestEnv(t *testing.T) {
+	parser := uargs.NewParser([]uargs.ArgDef{
+		{Name: "port", Usage: "Port", Type: uargs.Int, Env: "MYAPP_PORT", Default: 80, Max: uargs.Bound(65535)},
+		{Name: "token", Usage: "API token", Type: uargs.String, Env: "MYAPP_TOKEN", Required: true},
+		{Name: "tags", Usage: "Tags", Type: uargs.String, NumArgs: 3, Env: "MYAPP_TAGS"},
+	})
+
+	// Test case 1: Environment values satisfy required arguments and replace defaults
+	t.Setenv("MYAPP_PORT", "8080")
+	t.Setenv("MYAPP_TOKEN", "secret")
+	t.Setenv("MYAPP_TAGS", "a 'b c'")
+	parsed, err := parser.ParseArgs(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parsed["port"] != 8080 || parsed["token"] != "secret" {
+		t.Errorf("Expected port=8080 and token=secret, got %v and %v", parsed["port"], parsed["token"])
+	}
+	if tags, ok := parsed["tags"].([]string); !ok || strings.Join(tags, "|") != "a|b c" {
+		t.Errorf("Expected tags a and b c, got %v", parsed["tags"])
+	}
+	if origin := parser.Result().Origin("port"); origin != uargs.OriginEnv {
+		t.Errorf("Expected origin env, got %s", origin)
+	}
+
+	// Test case 2: The command line takes precedence
+	parsed, err = parser.ParseArgs([]string{"--port", "9000"})
+	if err != nil || parsed["port"] != 9000 || parser.Result().Origin("port") != uargs.OriginCommandLine {
+		t.Errorf("Expected port=9000 from the command line, got %v (%v)", parsed["port"], err)
+	}
+
+	// Test case 3: Invalid values name the variable
+	t.Setenv("MYAPP_PORT", "99999")
+	_, err = parser.ParseArgs(nil)
+	var argErr *uargs.ArgError
+	if err == nil || !strings.HasSuffix(err.Error(), "(from environment variable MYAPP_PORT)") || !errors.As(err, &argErr) || argErr.Name != "port" {
+		t.Errorf("Expected an error naming MYAPP_PORT, got %v", err)
+	}
+	if code := parser.ExitCode(err); code != 3 {
+		t.Errorf("Expected a validation exit code, got %d", code)
+	}
+
+	// Test case 4: Empty variables are ignored
+	t.Setenv("MYAPP_PORT", "")
+	t.Setenv("MYAPP_TOKEN", "")
+	if _, err := parser.ParseArgs(nil); err == nil || err.Error() != "missing required argument --token" {
+		t.Errorf("Expected a missing argument error, got %v", err)
+	}
+	if usage := parser.Usage(); !strings.Contains(usage, "Port (env: MYAPP_PORT) (default: 80)") {
+		t.Errorf("Expected the variable in usage, got:\n%s", usage)
+	}
+
+	// Test case 5: Invalid variable names are definition errors
+	bad := uargs.NewParser([]uargs.ArgDef{{Name: "x", Env: "A=B"}})
+	if _, err := bad.ParseArgs(nil); err == nil || err.Error() != `environment variable "A=B" of --x must not contain spaces or '='` {
+		t.Errorf("Expected a definition error, got %v", err)
+	}
+}
+
+// TestEnvPrefix tests environment variables derived from argument names
+func TestEnvPrefix(t *testing.T) {
+	parser := uargs.NewParser([]uargs.ArgDef{
+		{Name: "log-level", Usage: "Log level", Type: uargs.String},
+		{Name: "port", Usage: "Port", Type: uargs.Int, Env: "PORT"},
+		{Name: "debug", Usage: "Debug", Type: uargs.Int, Env: "-"},
+	}, uargs.WithEnvPrefix("MYAPP"))
+	add := parser.AddCommand(uargs.Command{Name: "add", Args: []uargs.ArgDef{
+		{Name: "name", Usage: "Name", Type: uargs.String},
+	}})
+	t.Setenv("MYAPP_LOG_LEVEL", "debug")
+	t.Setenv("PORT", "8080")
+	t.Setenv("MYAPP_PORT", "1")
+	t.Setenv("MYAPP_DEBUG", "1")
+	t.Setenv("MYAPP_NAME", "x")
+
+	parsed, err := parser.ParseArgs([]string{"add"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parsed["log-level"] != "debug" || parsed["port"] != 8080 {
+		t.Errorf("Expected log-level=debug and port=8080, got %v and %v", parsed["log-level"], parsed["port"])
+	}
+	if _, ok := parsed["debug"]; ok {
+		t.Errorf("Expected Env \"-\" to disable the variable, got %v", parsed["debug"])
+	}
+	if v, _ := add.Result().Get("name"); v != "x" {
+		t.Errorf("Expected subcommands to use the prefix, got name=%v", v)
+	}
+	if usage := parser.Usage(); !strings.Contains(usage, "Log level (env: MYAPP_LOG_LEVEL)") || strings.Contains(usage, "MYAPP_DEBUG") {
+		t.Errorf("Expected derived names in usage, got:\n%s", usage)
+	}
+}
+
+// TestEnvOnly tests settings that cannot be given on the command line
+func TestEnvOnly(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "api-token", Usage: "API token", Type: uargs.String, Env: "API_TOKEN", EnvOnly: true, Required: true},
+		{Name: "pin", Usage: "PIN", Type: uargs.Int, EnvOnly: true, Min: uargs.Bound(1000)},
+		{Name: "count", Short: "c", Usage: "Count", Type: uargs.Int},
+	}
+	config := writeConfig(t, "config.json", `{"pin": 1234}`)
+	parser := uargs.NewParser(args, uargs.WithConfigFile(config))
+	t.Setenv("API_TOKEN", "s3cret")
+
+	// Test case 1: Settings come from the environment and config files, typed and checked
+	parsed, err := parser.ParseArgs([]string{"-c", "2"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parsed["api-token"] != "s3cret" || parsed["pin"] != 1234 || parsed["count"] != 2 {
+		t.Errorf("Expected api-token=s3cret, pin=1234, and count=2, got %v", parsed)
+	}
+
+	// Test case 2: Giving a setting on the command line is an error naming where to set it
+	_, err = parser.ParseArgs([]string{"--api-token", "leaked"})
+	var argErr *uargs.ArgError
+	want := "--api-token cannot be given on the command line (set the environment variable API_TOKEN or the config key api-token)"
+	if !errors.As(err, &argErr) || argErr.Name != "api-token" || err.Error() != want {
+		t.Errorf("Expected %q, got %v", want, err)
+	}
+	if code := parser.ExitCode(err); code != 2 {
+		t.Errorf("Expected the usage exit code 2, got %d", code)
+	}
+
+	// Test case 3: Missing required settings and invalid values are reported
+	t.Setenv("API_TOKEN", "")
+	_, err = parser.ParseArgs(nil)
+	if err == nil || err.Error() != "missing required setting api-token (set the environment variable API_TOKEN or the config key api-token)" {
+		t.Errorf("Expected a missing setting error, got %v", err)
+	}
+	t.Setenv("API_TOKEN", "s3cret")
+	parser = uargs.NewParser(args, uargs.WithConfigFile(writeConfig(t, "low.json", `{"pin": 12}`)))
+	if _, err = parser.ParseArgs(nil); err == nil || !strings.Contains(err.Error(), "out of range") {
+		t.Errorf("Expected a range error for pin, got %v", err)
+	}
+
+	// Test case 4: Usage lists settings apart from flags, and completion leaves them out
+	usage := parser.Usage()
+	if strings.Contains(usage, "--api-token") || !strings.Contains(usage, "Settings (environment or config file only):\n  api-token  API token (required) (env: API_TOKEN)\n") {
+		t.Errorf("Expected api-token listed as a setting, got:\n%s", usage)
+	}
+	if hints := parser.Hints("--", 2); len(hints) != 1 || hints[0].Text != "--count" {
+		t.Errorf("Expected only --count to be completed, got %+v", hints)
+	}
+
+	// Test case 5: Settings cannot have short names
+	_, err = uargs.NewParserE([]uargs.ArgDef{
+		{Name: "api-token", Short: "t", Usage: "API token", Type: uargs.String, EnvOnly: true},
+	})
+	if err == nil || !strings.Contains(err.Error(), "cannot have a short name") {
+		t.Errorf("Expected a definition error, got %v", err)
+	}
+
+	// Test case 6: Settings count as given for the constraints between arguments
+	t.Setenv("DB_PASSWORD", "hunter2")
+	args = []uargs.ArgDef{
+		{Name: "user", Usage: "User", Type: uargs.String},
+		{Name: "password", Usage: "Password", Type: uargs.String, Env: "DB_PASSWORD", EnvOnly: true, RequiredIfGiven: []string{"user"}},
+	}
+	parser = uargs.NewParser(args)
+	if parsed, err = parser.ParseArgs([]string{"--user", "x"}); err != nil || parsed["password"] != "hunter2" {
+		t.Errorf("Expected RequiredIfGiven to accept the setting, got %v (%v)", parsed["password"], err)
+	}
+	args[1].RequiredIfGiven = nil
+	parser = uargs.NewParser(args, uargs.WithRequiredTogether("user", "password"))
+	if _, err = parser.ParseArgs([]string{"--user", "x"}); err != nil {
+		t.Errorf("Expected WithRequiredTogether to accept the setting, got %v", err)
+	}
+}
+
+// TestEnvConstraints tests that values from the environment and config files
+// count as given for the constraints between arguments
+func TestEnvConstraints(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "file", Usage: "Read a file", Type: uargs.String},
+		{Name: "url", Usage: "Fetch a URL", Type: uargs.String, Env: "APP_URL"},
+		{Name: "json", Usage: "JSON output", NumArgs: 0, Type: uargs.String, ConflictsWith: []string{"yaml"}},
+		{Name: "yaml", Usage: "YAML output", NumArgs: 0, Type: uargs.String, Env: "APP_YAML"},
+		{Name: "level", Usage: "Log level", Type: uargs.String, Default: "info"},
+	}
+	config := writeConfig(t, "config.json", `{"file": "a.txt"}`)
+
+	// Test case 1: An environment value satisfies a required group
+	t.Setenv("APP_URL", "https://example.com")
+	parser := uargs.NewParser(args, uargs.WithExactlyOneOf("file", "url"))
+	parsed, err := parser.ParseArgs(nil)
+	if err != nil || parsed["url"] != "https://example.com" {
+		t.Errorf("Expected url from APP_URL, got %v (%v)", parsed["url"], err)
+	}
+
+	// Test case 2: An environment value conflicts with a command-line flag
+	_, err = parser.ParseArgs([]string{"--file", "a.txt"})
+	if want := "only one of --file | --url can be used (got --file | --url)"; err == nil || err.Error() != want {
+		t.Errorf("Expected error %q, got %v", want, err)
+	}
+	t.Setenv("APP_YAML", "true")
+	_, err = parser.ParseArgs([]string{"--json"})
+	if want := "arguments --json and --yaml cannot be used together"; err == nil || err.Error() != want {
+		t.Errorf("Expected error %q, got %v", want, err)
+	}
+	t.Setenv("APP_YAML", "")
+
+	// Test case 3: A config value satisfies a required group
+	t.Setenv("APP_URL", "")
+	parser = uargs.NewParser(args, uargs.WithAtLeastOneOf("file", "url"), uargs.WithConfigFile(config))
+	if parsed, err = parser.ParseArgs(nil); err != nil || parsed["file"] != "a.txt" {
+		t.Errorf("Expected file from the config file, got %v (%v)", parsed["file"], err)
+	}
+
+	// Test case 4: Defaults do not count as given
+	parser = uargs.NewParser(args, uargs.WithExactlyOneOf("url", "level"))
+	if _, err = parser.ParseArgs(nil); err == nil || err.Error() != "one of --url | --level is required" {
+		t.Errorf("Expected a missing group error, got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package uargs
+
+import (
+	"os"
+	"runtime"
+	"strings"
+)
+
+// HelpEnv describes the environment help text is rendered in. It is passed to
+// the callback set with WithEpilogue.
+type HelpEnv struct {
+	// OS is the operating system, as in runtime.GOOS
+	OS string
+	// CI reports whether the program runs in a continuous integration system
+	CI bool
+	// Command is the command path of the help being rendered, empty for the root
+	Command []string
+}
+
+// Exists reports whether a file exists at path. A leading ~ is expanded to the
+// home directory, so an epilogue can check for "~/.mytool.json".
+func (e HelpEnv) Exists(path string) bool {
+	if p, err := expandPath(path); err == nil {
+		path = p
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// ciEnv lists environment variables set by common CI systems
+var ciEnv = []string{"CI", "GITHUB_ACTIONS", "GITLAB_CI", "BUILDKITE", "JENKINS_URL", "TF_BUILD", "CIRCLECI"}
+
+// detectCI reports whether one of the variables set by CI systems is present
+func detectCI() bool {
+	for _, name := range ciEnv {
+		if v := os.Getenv(name); v != "" && v != "false" && v != "0" {
+			return true
+		}
+	}
+	return false
+}
+
+// writeEpilogue appends the footer returned by the epilogue callback, if any
+func (p *Parser) writeEpilogue(b *strings.Builder) {
+	if p.epilogue == nil {
+		return
+	}
+	text := p.epilogue(HelpEnv{OS: runtime.GOOS, CI: detectCI(), Command: p.path()})
+	if text != "" {
+		b.WriteString("\n" + text)
+		if text[len(text)-1] != '\n' {
+			b.WriteString("\n")
+		}
+	}
+}
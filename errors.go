@@ -0,0 +1,62 @@
+package uargs
+
+import "errors"
+
+// ArgError is returned by Parse for errors caused by a single argument, such
+// as an invalid or missing value. Use errors.As to find which argument failed.
+type ArgError struct {
+	// Name is the long name of the argument
+	Name string
+	// DocsURL is the argument's DocsURL, if any
+	DocsURL string
+	// Err is the underlying error
+	Err error
+}
+
+// Error returns the underlying message, followed by the argument's DocsURL
+func (e *ArgError) Error() string {
+	if e.DocsURL == "" {
+		return e.Err.Error()
+	}
+	return e.Err.Error() + " (see " + e.DocsURL + ")"
+}
+
+// Unwrap returns the underlying error
+func (e *ArgError) Unwrap() error {
+	return e.Err
+}
+
+// argError attributes err to the argument def
+func argError(def ArgDef, err error) error {
+	return &ArgError{Name: def.Name, DocsURL: def.DocsURL, Err: err}
+}
+
+// docsError appends the DocsURL of a command to an error in its arguments
+type docsError struct {
+	url string
+	err error
+}
+
+// Error returns the underlying message, followed by the command's DocsURL
+func (e *docsError) Error() string {
+	return e.err.Error() + " (see " + e.url + ")"
+}
+
+// Unwrap returns the underlying error
+func (e *docsError) Unwrap() error {
+	return e.err
+}
+
+// withCommandDocs appends the DocsURL of the command of p to err, unless err
+// reports success or already has a link
+func (p *Parser) withCommandDocs(err error) error {
+	if err == nil || p.command == nil || p.command.DocsURL == "" || informational(err) {
+		return err
+	}
+	var argErr *ArgError
+	var docsErr *docsError
+	if (errors.As(err, &argErr) && argErr.DocsURL != "") || errors.As(err, &docsErr) {
+		return err
+	}
+	return &docsError{url: p.command.DocsURL, err: err}
+}
@@ -0,0 +1,66 @@
+package uargs_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestArgError tests errors attributed to arguments and their DocsURL
+func TestArgError(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "schedule", Usage: "Cron schedule", Type: uargs.Int, DocsURL: "https://example.com/docs/schedule"},
+		{Name: "name", Usage: "Name", Type: uargs.String, Required: true},
+	}
+	parser := uargs.NewParser(args)
+
+	_, err := parser.ParseArgs([]string{"--name", "x", "--schedule", "daily"})
+	if err == nil || err.Error() != "--schedule expects int, got 'daily' (see https://example.com/docs/schedule)" {
+		t.Errorf("Expected error with docs link, got %v", err)
+	}
+	var argErr *uargs.ArgError
+	if !errors.As(err, &argErr) || argErr.Name != "schedule" {
+		t.Errorf("Expected ArgError for schedule, got %#v", err)
+	}
+
+	_, err = parser.ParseArgs(nil)
+	if !errors.As(err, &argErr) || argErr.Name != "name" || err.Error() != "missing required argument --name" {
+		t.Errorf("Expected ArgError for name without docs link, got %v", err)
+	}
+
+	if !strings.Contains(parser.Usage(), "Cron schedule (see https://example.com/docs/schedule)") {
+		t.Errorf("Expected docs link in usage, got:\n%s", parser.Usage())
+	}
+	if !strings.Contains(parser.AccessibleUsage(), "See https://example.com/docs/schedule for more information.") {
+		t.Errorf("Expected docs link in accessible usage, got:\n%s", parser.AccessibleUsage())
+	}
+
+	err = parser.RunBatch(strings.NewReader("--schedule weekly\n"), func(map[string]interface{}) error { return nil })
+	if !errors.As(err, &argErr) || argErr.Name != "schedule" {
+		t.Errorf("Expected ArgError through RunBatch, got %v", err)
+	}
+
+	// Commands show their link in help and errors without a link of their own
+	cron := parser.AddCommand(uargs.Command{Name: "cron", Usage: "Manage jobs", DocsURL: "https://example.com/docs/cron", Args: []uargs.ArgDef{
+		{Name: "every", Usage: "Interval", Type: uargs.Int},
+	}})
+	_, err = parser.ParseArgs([]string{"--name", "x", "cron", "--every", "often"})
+	if err == nil || err.Error() != "--every expects int, got 'often' (see https://example.com/docs/cron)" {
+		t.Errorf("Expected error with the command docs link, got %v", err)
+	}
+	if !errors.As(err, &argErr) || argErr.Name != "every" || parser.ExitCode(err) != 3 {
+		t.Errorf("Expected a validation ArgError for every, got %#v", err)
+	}
+	_, err = parser.ParseArgs([]string{"--name", "x", "cron", "--schedule", "daily"})
+	if err == nil || err.Error() != "unknown argument --schedule (see https://example.com/docs/cron)" {
+		t.Errorf("Expected unknown argument error with the command docs link, got %v", err)
+	}
+	if !strings.Contains(cron.Usage(), "\nManage jobs (see https://example.com/docs/cron)\n") {
+		t.Errorf("Expected docs link in command usage, got:\n%s", cron.Usage())
+	}
+	if !strings.Contains(cron.AccessibleUsage(), "See https://example.com/docs/cron for more information.") {
+		t.Errorf("Expected docs link in accessible command usage, got:\n%s", cron.AccessibleUsage())
+	}
+}
@@ -95,7 +95,7 @@ func main() {
 
    # Not using file argument means template is required
    go run main.go
-   Error: missing required argument --template
+   Error: missing required argument -tpl, --template
 
    # You can use the short format for all arguments
    go run main.go -f data.txt -t red blue green -c 10.5 20.3
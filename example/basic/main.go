@@ -76,7 +76,7 @@ func main() {
 
    # Missing required argument will show an error
    go run main.go --output result.txt
-   Error: missing required argument --input
+   Error: missing required argument -i, --input
    Usage:
      --input      -i    Input file path
      --output     -o    Output file path (optional)
@@ -0,0 +1,168 @@
+package uargs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Execute parses os.Args and calls the Run function of the selected command,
+// passing the command's parsed values. It saves every program from switching
+// over command names itself. The hooks of the selected commands run around
+// Run in this order: PersistentPreRun from the outermost command inwards,
+// PreRun, Run, PostRun, then PersistentPostRun from the innermost command
+// outwards. The first error stops the sequence and is returned.
+//
+// Example:
+//
+//	parser.AddCommand(uargs.Command{Name: "serve", Args: serveArgs, Run: serve})
+//	if err := parser.Execute(); err != nil {
+//		log.Fatal(err)
+//	}
+func (p *Parser) Execute() error {
+	return p.ExecuteContext(context.Background())
+}
+
+// ExecuteContext is like Execute but passes ctx to Run and the hooks, so
+// long-running commands can honor cancellation and deadlines. Once ctx is
+// done, the remaining hooks are skipped and ctx's error is returned.
+//
+// Example:
+//
+//	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+//	defer stop()
+//	if err := parser.ExecuteContext(ctx); err != nil {
+//		log.Fatal(err)
+//	}
+func (p *Parser) ExecuteContext(ctx context.Context) error {
+	var argv []string
+	if len(os.Args) > 1 {
+		argv = os.Args[1:]
+	}
+	return p.ExecuteArgsContext(ctx, argv)
+}
+
+// ExecuteArgs is like Execute but parses the given arguments instead of os.Args
+func (p *Parser) ExecuteArgs(argv []string) error {
+	return p.ExecuteArgsContext(context.Background(), argv)
+}
+
+// ExecuteArgsContext is like ExecuteContext but parses the given arguments
+// instead of os.Args
+func (p *Parser) ExecuteArgsContext(ctx context.Context, argv []string) error {
+	if p == nil {
+		return ErrNilParser
+	}
+	return p.execute(ctx, argv, nil)
+}
+
+// execute implements ExecuteArgsContext, treating the values in shared as
+// given like parse does
+func (p *Parser) execute(ctx context.Context, argv []string, shared map[string]interface{}) error {
+	if _, err := p.parse(argv, shared); err != nil {
+		return err
+	}
+	if plugin, args := p.result.Plugin(); plugin != nil {
+		return p.runPlugin(ctx, plugin, args)
+	}
+	// chain holds the selected commands from the outermost to the one to run
+	var chain []*Command
+	parsers := []*Parser{p}
+	cmd := p
+	for next := p.result.command; next != nil; next = next.result.command {
+		cmd = next
+		chain = append(chain, next.command)
+		parsers = append(parsers, next)
+	}
+	if cmd.command == nil || cmd.command.Run == nil {
+		return cmd.missingRun()
+	}
+
+	// Every hook sees the values of the command that runs
+	parsed := cmd.parsed
+	ctx = NewContext(ctx, cmd.result)
+	hooks := []RunFunc{}
+	for _, c := range chain {
+		hooks = append(hooks, c.PersistentPreRun)
+	}
+	hooks = append(hooks, cmd.command.PreRun, cmd.command.Run, cmd.command.PostRun)
+	for i := len(chain) - 1; i >= 0; i-- {
+		hooks = append(hooks, chain[i].PersistentPostRun)
+	}
+	run := RunFunc(func(ctx context.Context, parsed map[string]interface{}) error {
+		for _, hook := range hooks {
+			if hook == nil {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := hook(ctx, parsed); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	// The first middleware of the outermost parser wraps all others
+	var middleware []Middleware
+	for _, q := range parsers {
+		if q.command != nil {
+			middleware = append(middleware, q.command.Middleware...)
+		}
+		middleware = append(middleware, q.middleware...)
+	}
+	for i := len(middleware) - 1; i >= 0; i-- {
+		if middleware[i] != nil {
+			run = middleware[i](run)
+		}
+	}
+	return run(ctx, parsed)
+}
+
+// Use adds middleware that wraps the execution of every command run through
+// p by Execute, including commands nested in it. Middleware suits concerns
+// such as timing, panic recovery, telemetry, and authorization. It wraps the
+// hooks as well as Run, and is applied from the outermost parser inwards: the
+// first middleware added to the root runs first and returns last, followed by
+// the Middleware of each selected command and the middleware added to its
+// parser.
+//
+// Example:
+//
+//	parser.Use(func(next uargs.RunFunc) uargs.RunFunc {
+//		return func(ctx context.Context, parsed map[string]interface{}) error {
+//			start := time.Now()
+//			defer func() { log.Printf("took %v", time.Since(start)) }()
+//			return next(ctx, parsed)
+//		}
+//	})
+func (p *Parser) Use(middleware ...Middleware) {
+	if p == nil {
+		return
+	}
+	p.middleware = append(p.middleware, middleware...)
+}
+
+// missingRun builds the error for a selected parser that has nothing to run
+func (p *Parser) missingRun() error {
+	if len(p.commands) > 0 {
+		var names []string
+		for _, c := range p.visibleCommands() {
+			names = append(names, c.command.Name)
+		}
+		expected := ""
+		if len(names) > 0 {
+			expected = ", expected one of: " + strings.Join(names, ", ")
+		}
+		if p.command == nil {
+			return classify(usageErrorKind, fmt.Errorf("missing command%s", expected))
+		}
+		return classify(usageErrorKind, fmt.Errorf("command %s needs a subcommand%s", strings.Join(p.path(), " "), expected))
+	}
+	if p.command == nil {
+		return fmt.Errorf("no commands to run")
+	}
+	return fmt.Errorf("command %s has no Run function", strings.Join(p.path(), " "))
+}
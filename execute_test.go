@@ -0,0 +1,205 @@
+package uargs_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestExecute tests dispatching to the Run function of the selected command
+func TestExecute(t *testing.T) {
+	var ran string
+	var got map[string]interface{}
+	run := func(name string) uargs.RunFunc {
+		return func(_ context.Context, parsed map[string]interface{}) error {
+			ran, got = name, parsed
+			return nil
+		}
+	}
+
+	parser := uargs.NewParser([]uargs.ArgDef{{Name: "verbose", Usage: "Verbosity", Type: uargs.Int, Persistent: true}})
+	parser.AddCommand(uargs.Command{Name: "add", Run: run("add"), Args: []uargs.ArgDef{
+		{Name: "name", Usage: "Name", Type: uargs.String, Required: true},
+	}})
+	remote := parser.AddCommand(uargs.Command{Name: "remote"})
+	remote.AddCommand(uargs.Command{Name: "show", Run: run("remote show")})
+	remote.AddCommand(uargs.Command{Name: "prune"})
+	failing := errors.New("failed")
+	parser.AddCommand(uargs.Command{Name: "fail", Run: func(context.Context, map[string]interface{}) error { return failing }})
+
+	if err := parser.ExecuteArgs([]string{"--verbose", "1", "add", "--name", "x"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ran != "add" || got["name"] != "x" || got["verbose"] != 1 {
+		t.Errorf("Expected add to run with its values, got %s with %v", ran, got)
+	}
+	if err := parser.ExecuteArgs([]string{"remote", "show"}); err != nil || ran != "remote show" {
+		t.Errorf("Expected the nested command to run, got %s, %v", ran, err)
+	}
+
+	ran = ""
+	errorCases := map[string][]string{
+		"missing required argument --name":                                {"add"},
+		"missing command, expected one of: add, remote, fail":             nil,
+		"command remote needs a subcommand, expected one of: show, prune": {"remote"},
+		"command remote prune has no Run function":                        {"remote", "prune"},
+		"failed": {"fail"},
+	}
+	for want, argv := range errorCases {
+		err := parser.ExecuteArgs(argv)
+		if err == nil || err.Error() != want {
+			t.Errorf("%v: expected %q, got %v", argv, want, err)
+		}
+	}
+	if ran != "" {
+		t.Errorf("Expected no Run function to be called, got %s", ran)
+	}
+}
+
+// TestHooks tests the order of pre-run and post-run hooks
+func TestHooks(t *testing.T) {
+	var calls []string
+	hook := func(name string, err error) uargs.RunFunc {
+		return func(_ context.Context, parsed map[string]interface{}) error {
+			calls = append(calls, fmt.Sprintf("%s(%v)", name, parsed["verbose"]))
+			return err
+		}
+	}
+
+	parser := uargs.NewParser([]uargs.ArgDef{{Name: "verbose", Usage: "Verbosity", Type: uargs.Int, Default: 0, Persistent: true}})
+	remote := parser.AddCommand(uargs.Command{
+		Name:              "remote",
+		PersistentPreRun:  hook("remote.persistentPre", nil),
+		PreRun:            hook("remote.pre", nil),
+		PostRun:           hook("remote.post", nil),
+		PersistentPostRun: hook("remote.persistentPost", nil),
+	})
+	remote.AddCommand(uargs.Command{
+		Name:              "add",
+		PersistentPreRun:  hook("add.persistentPre", nil),
+		PreRun:            hook("add.pre", nil),
+		Run:               hook("add.run", nil),
+		PostRun:           hook("add.post", nil),
+		PersistentPostRun: hook("add.persistentPost", nil),
+	})
+	failing := errors.New("not authorized")
+	remote.AddCommand(uargs.Command{Name: "drop", PreRun: hook("drop.pre", failing), Run: hook("drop.run", nil)})
+
+	if err := parser.ExecuteArgs([]string{"remote", "add", "--verbose", "2"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := "remote.persistentPre(2) add.persistentPre(2) add.pre(2) add.run(2) add.post(2) add.persistentPost(2) remote.persistentPost(2)"
+	if got := strings.Join(calls, " "); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+
+	calls = nil
+	if err := parser.ExecuteArgs([]string{"remote", "drop"}); !errors.Is(err, failing) {
+		t.Errorf("Expected the hook error, got %v", err)
+	}
+	if got := strings.Join(calls, " "); got != "remote.persistentPre(0) drop.pre(0)" {
+		t.Errorf("Expected Run to be skipped after a failing hook, got %s", got)
+	}
+}
+
+// TestExecuteContext tests passing a context to Run and the hooks
+func TestExecuteContext(t *testing.T) {
+	type key struct{}
+	var calls []string
+	parser := uargs.NewParser([]uargs.ArgDef{{Name: "port", Usage: "Port", Type: uargs.Int, Default: 80}})
+	parser.AddCommand(uargs.Command{
+		Name: "serve",
+		Args: []uargs.ArgDef{{Name: "host", Usage: "Host", Type: uargs.String, Default: "localhost"}},
+		PreRun: func(ctx context.Context, parsed map[string]interface{}) error {
+			calls = append(calls, "pre")
+			return nil
+		},
+		Run: func(ctx context.Context, parsed map[string]interface{}) error {
+			calls = append(calls, "run")
+			if ctx.Value(key{}) != "value" {
+				t.Errorf("Expected the caller's context, got %v", ctx.Value(key{}))
+			}
+			res, ok := uargs.FromContext(ctx)
+			if !ok || res.Name() != "serve" {
+				t.Fatalf("Expected the command's result in the context, got %v", res)
+			}
+			if host, _ := res.Get("host"); host != "localhost" {
+				t.Errorf("Expected host=localhost, got %v", host)
+			}
+			return nil
+		},
+	})
+
+	ctx := context.WithValue(context.Background(), key{}, "value")
+	if err := parser.ExecuteArgsContext(ctx, []string{"serve"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := strings.Join(calls, " "); got != "pre run" {
+		t.Errorf("Expected pre run, got %s", got)
+	}
+
+	// A canceled context stops the sequence before the next hook
+	calls = nil
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := parser.ExecuteArgsContext(canceled, []string{"serve"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if len(calls) != 0 {
+		t.Errorf("Expected no hooks to run, got %v", calls)
+	}
+}
+
+// TestMiddleware tests the order in which middleware wraps command execution
+func TestMiddleware(t *testing.T) {
+	var calls []string
+	mw := func(name string) uargs.Middleware {
+		return func(next uargs.RunFunc) uargs.RunFunc {
+			return func(ctx context.Context, parsed map[string]interface{}) error {
+				calls = append(calls, name+">")
+				err := next(ctx, parsed)
+				calls = append(calls, "<"+name)
+				return err
+			}
+		}
+	}
+	hook := func(name string) uargs.RunFunc {
+		return func(context.Context, map[string]interface{}) error {
+			calls = append(calls, name)
+			return nil
+		}
+	}
+
+	parser := uargs.NewParser(nil)
+	parser.Use(mw("root1"), mw("root2"))
+	remote := parser.AddCommand(uargs.Command{Name: "remote", Middleware: []uargs.Middleware{mw("remote")}, PersistentPreRun: hook("pre")})
+	remote.Use(mw("remote-use"))
+	remote.AddCommand(uargs.Command{Name: "add", Middleware: []uargs.Middleware{mw("add")}, Run: hook("run")})
+	denied := errors.New("denied")
+	remote.AddCommand(uargs.Command{Name: "drop", Run: hook("drop"), Middleware: []uargs.Middleware{
+		func(uargs.RunFunc) uargs.RunFunc {
+			return func(context.Context, map[string]interface{}) error { return denied }
+		},
+	}})
+
+	if err := parser.ExecuteArgs([]string{"remote", "add"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := "root1> root2> remote> remote-use> add> pre run <add <remote-use <remote <root2 <root1"
+	if got := strings.Join(calls, " "); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+
+	// Middleware can stop the execution
+	calls = nil
+	if err := parser.ExecuteArgs([]string{"remote", "drop"}); !errors.Is(err, denied) {
+		t.Errorf("Expected the middleware error, got %v", err)
+	}
+	if got := strings.Join(calls, " "); got != "root1> root2> remote> remote-use> <remote-use <remote <root2 <root1" {
+		t.Errorf("Expected the hooks and Run to be skipped, got %s", got)
+	}
+}
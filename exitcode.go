@@ -0,0 +1,120 @@
+package uargs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// ExitCodes maps the kinds of errors returned by Parse and Execute to process
+// exit codes (see ExitCode and WithExitCodes)
+type ExitCodes struct {
+	// Handler is used for errors of Run, hooks, middleware, and any other error
+	// not caused by the command line
+	Handler int
+	// Usage is used for command lines that do not fit the definitions, such as
+	// unknown flags or commands and missing or conflicting arguments
+	Usage int
+	// Validation is used for values that fail type conversion or validation,
+	// including AddValidator checks
+	Validation int
+}
+
+// DefaultExitCodes are the exit codes used unless changed with WithExitCodes
+var DefaultExitCodes = ExitCodes{Handler: 1, Usage: 2, Validation: 3}
+
+// errorKind classifies errors for exit codes
+type errorKind int
+
+const (
+	usageErrorKind errorKind = iota
+	validationErrorKind
+)
+
+// classifiedError marks err with the kind of failure, keeping its message
+type classifiedError struct {
+	kind errorKind
+	err  error
+}
+
+// Error returns the message of the underlying error
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *classifiedError) Unwrap() error {
+	return e.err
+}
+
+// classify marks err with kind unless it is nil or already classified
+func classify(kind errorKind, err error) error {
+	var classified *classifiedError
+	if err == nil || errors.As(err, &classified) {
+		return err
+	}
+	return &classifiedError{kind: kind, err: err}
+}
+
+// ExitCode returns the process exit code for an error returned by Parse or
+// Execute: 0 for nil, ErrHelp, ErrVersion, ErrPrintFlags, and ErrSelfTest, and
+// otherwise the code set with WithExitCodes for its kind (DefaultExitCodes: 2
+// for usage errors, 3 for validation errors, and 1 for errors of Run and the
+// hooks). Errors with an ExitCode() int method, such as the *exec.ExitError of
+// a plugin, choose their own code.
+//
+// Example:
+//
+//	if _, err := parser.Parse(); err != nil {
+//		fmt.Fprintln(os.Stderr, err)
+//		os.Exit(parser.ExitCode(err))
+//	}
+func (p *Parser) ExitCode(err error) int {
+	if err == nil || informational(err) {
+		return 0
+	}
+	var coder interface{ ExitCode() int }
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+	codes := DefaultExitCodes
+	if p != nil && p.exitCodes != nil {
+		codes = *p.exitCodes
+	}
+	var classified *classifiedError
+	if !errors.As(err, &classified) {
+		return codes.Handler
+	}
+	if classified.kind == validationErrorKind {
+		return codes.Validation
+	}
+	return codes.Usage
+}
+
+// ExecuteAndExit runs Execute and exits the process with ExitCode of its
+// error, after writing the error to standard error. Help, the version, and
+// --print-flags exit with 0, and the failure of a plugin is not written again.
+//
+// Example:
+//
+//	func main() {
+//		parser := uargs.NewParser(args)
+//		parser.AddCommand(uargs.Command{Name: "serve", Run: serve})
+//		parser.ExecuteAndExit()
+//	}
+func (p *Parser) ExecuteAndExit() {
+	err := p.Execute()
+	code := p.ExitCode(err)
+	var exitErr *exec.ExitError
+	if code != 0 && !errors.As(err, &exitErr) {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	os.Exit(code)
+}
+
+// informational reports whether err reports a successful early exit, such as
+// ErrHelp, rather than a failure
+func informational(err error) bool {
+	return errors.Is(err, ErrHelp) || errors.Is(err, ErrVersion) || errors.Is(err, ErrPrintFlags) || errors.Is(err, ErrSelfTest)
+}
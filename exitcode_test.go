@@ -0,0 +1,83 @@
+package uargs_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// codeError is an error that chooses its own exit code
+type codeError int
+
+func (e codeError) Error() string { return fmt.Sprintf("code %d", int(e)) }
+func (e codeError) ExitCode() int { return int(e) }
+
+// TestExitCode tests mapping errors to process exit codes
+func TestExitCode(t *testing.T) {
+	newParser := func(opts ...uargs.Option) *uargs.Parser {
+		parser := uargs.NewParser([]uargs.ArgDef{
+			{Name: "port", Usage: "Port", Type: uargs.Int, Min: uargs.Bound(1)},
+			{Name: "name", Usage: "Name", Type: uargs.String, Validate: func(v interface{}) error {
+				if v == "bad" {
+					return errors.New("bad name")
+				}
+				return nil
+			}},
+		}, opts...)
+		parser.AddValidator(func(parsed map[string]interface{}) error {
+			if parsed["name"] == "taken" {
+				return errors.New("name is taken")
+			}
+			return nil
+		})
+		parser.AddCommand(uargs.Command{Name: "serve", Run: func(context.Context, map[string]interface{}) error {
+			return errors.New("address in use")
+		}})
+		parser.AddCommand(uargs.Command{Name: "custom", Run: func(context.Context, map[string]interface{}) error {
+			return fmt.Errorf("wrapped: %w", codeError(42))
+		}})
+		parser.AddCommand(uargs.Command{Name: "ok", Run: func(context.Context, map[string]interface{}) error {
+			return nil
+		}})
+		return parser
+	}
+	parser := newParser(uargs.WithHelp(io.Discard))
+
+	cases := map[string]struct {
+		argv []string
+		code int
+	}{
+		"success":         {[]string{"ok"}, 0},
+		"help":            {[]string{"--help"}, 0},
+		"unknown flag":    {[]string{"--bogus"}, 2},
+		"unknown command": {[]string{"bogus"}, 2},
+		"missing command": {nil, 2},
+		"not a number":    {[]string{"--port", "x", "ok"}, 3},
+		"out of range":    {[]string{"--port", "0", "ok"}, 3},
+		"validate":        {[]string{"--name", "bad", "ok"}, 3},
+		"validator":       {[]string{"--name", "taken", "ok"}, 3},
+		"handler":         {[]string{"serve"}, 1},
+		"own code":        {[]string{"custom"}, 42},
+	}
+	for name, c := range cases {
+		err := parser.ExecuteArgs(c.argv)
+		if code := parser.ExitCode(err); code != c.code {
+			t.Errorf("%s: expected exit code %d, got %d (%v)", name, c.code, code, err)
+		}
+	}
+
+	// Codes can be changed per kind
+	codes := uargs.DefaultExitCodes
+	codes.Usage = 64
+	custom := newParser(uargs.WithExitCodes(codes))
+	if code := custom.ExitCode(custom.ExecuteArgs([]string{"--bogus"})); code != 64 {
+		t.Errorf("Expected exit code 64, got %d", code)
+	}
+	if code := custom.ExitCode(custom.ExecuteArgs([]string{"serve"})); code != 1 {
+		t.Errorf("Expected exit code 1, got %d", code)
+	}
+}
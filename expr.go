@@ -0,0 +1,296 @@
+package uargs
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// exprNode is a node of a compiled RequiredIf expression
+type exprNode interface {
+	// eval computes the node's value from the parsed values
+	eval(values map[string]interface{}) interface{}
+}
+
+// identNode is a reference to the value of an argument, nil if it has none
+type identNode struct{ name string }
+
+// litNode is a string or float64 literal
+type litNode struct{ value interface{} }
+
+// notNode negates the truth of its operand
+type notNode struct{ x exprNode }
+
+// binaryNode applies a logical or comparison operator to two operands
+type binaryNode struct {
+	op   string
+	l, r exprNode
+}
+
+func (n identNode) eval(values map[string]interface{}) interface{} { return values[n.name] }
+func (n litNode) eval(map[string]interface{}) interface{}          { return n.value }
+func (n notNode) eval(values map[string]interface{}) interface{}   { return !truthy(n.x.eval(values)) }
+
+func (n binaryNode) eval(values map[string]interface{}) interface{} {
+	switch n.op {
+	case "&&":
+		return truthy(n.l.eval(values)) && truthy(n.r.eval(values))
+	case "||":
+		return truthy(n.l.eval(values)) || truthy(n.r.eval(values))
+	}
+	l, r := n.l.eval(values), n.r.eval(values)
+	if l == nil || r == nil {
+		return n.op == "!=" && (l != nil || r != nil)
+	}
+	lf, lok := toFloat(l)
+	rf, rok := toFloat(r)
+	switch n.op {
+	case "==", "!=":
+		equal := fmt.Sprint(l) == fmt.Sprint(r)
+		if lok && rok {
+			equal = lf == rf
+		}
+		return equal == (n.op == "==")
+	case "<":
+		return lok && rok && lf < rf
+	case "<=":
+		return lok && rok && lf <= rf
+	case ">":
+		return lok && rok && lf > rf
+	case ">=":
+		return lok && rok && lf >= rf
+	}
+	return false
+}
+
+// truthy reports whether v counts as true: a value that is present and not
+// empty, zero, or false
+func truthy(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case []string:
+		return len(v) > 0
+	case []int:
+		return len(v) > 0
+	case []float64:
+		return len(v) > 0
+	}
+	f, ok := toFloat(v)
+	return !ok || f != 0
+}
+
+// toFloat converts numeric values, including numeric strings, to float64
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// exprParser compiles RequiredIf expressions by recursive descent
+type exprParser struct {
+	src    string
+	pos    int
+	tok    string // Current token, "" at the end of the input
+	tokPos int    // Offset of the current token
+	known  map[string]ArgDef
+}
+
+// compileExpr compiles a RequiredIf expression such as
+// "format == 'pdf' && !template". Identifiers must name defined arguments.
+func compileExpr(src string, known map[string]ArgDef) (exprNode, error) {
+	ep := &exprParser{src: src, known: known}
+	if err := ep.next(); err != nil {
+		return nil, err
+	}
+	n, err := ep.or()
+	if err != nil {
+		return nil, err
+	}
+	if ep.tok != "" {
+		return nil, fmt.Errorf("unexpected %q at offset %d", ep.tok, ep.tokPos)
+	}
+	return n, nil
+}
+
+// next advances to the following token
+func (ep *exprParser) next() error {
+	for ep.pos < len(ep.src) && (ep.src[ep.pos] == ' ' || ep.src[ep.pos] == '\t') {
+		ep.pos++
+	}
+	ep.tokPos = ep.pos
+	if ep.pos >= len(ep.src) {
+		ep.tok = ""
+		return nil
+	}
+	rest := ep.src[ep.pos:]
+	for _, op := range []string{"&&", "||", "==", "!=", "<=", ">=", "!", "<", ">", "(", ")"} {
+		if strings.HasPrefix(rest, op) {
+			ep.tok = op
+			ep.pos += len(op)
+			return nil
+		}
+	}
+	end := 1
+	switch c := rest[0]; {
+	case c == '\'' || c == '"':
+		i := strings.IndexByte(rest[1:], c)
+		if i < 0 {
+			return fmt.Errorf("unterminated string at offset %d", ep.pos)
+		}
+		end = i + 2
+	case isIdentByte(c) || c == '-' || c == '.':
+		for end < len(rest) && (isIdentByte(rest[end]) || rest[end] == '-' || rest[end] == '.') {
+			end++
+		}
+	default:
+		return fmt.Errorf("unexpected %q at offset %d", c, ep.pos)
+	}
+	ep.tok = rest[:end]
+	ep.pos += end
+	return nil
+}
+
+// isIdentByte reports whether c may appear in an argument name or number
+func isIdentByte(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// or parses a chain of && expressions joined by ||
+func (ep *exprParser) or() (exprNode, error) {
+	l, err := ep.and()
+	for err == nil && ep.tok == "||" {
+		var r exprNode
+		if err = ep.next(); err == nil {
+			if r, err = ep.and(); err == nil {
+				l = binaryNode{"||", l, r}
+			}
+		}
+	}
+	return l, err
+}
+
+// and parses a chain of unary expressions joined by &&
+func (ep *exprParser) and() (exprNode, error) {
+	l, err := ep.unary()
+	for err == nil && ep.tok == "&&" {
+		var r exprNode
+		if err = ep.next(); err == nil {
+			if r, err = ep.unary(); err == nil {
+				l = binaryNode{"&&", l, r}
+			}
+		}
+	}
+	return l, err
+}
+
+// unary parses a negation or a comparison
+func (ep *exprParser) unary() (exprNode, error) {
+	if ep.tok == "!" {
+		if err := ep.next(); err != nil {
+			return nil, err
+		}
+		x, err := ep.unary()
+		return notNode{x}, err
+	}
+	l, err := ep.primary()
+	if err != nil {
+		return nil, err
+	}
+	switch op := ep.tok; op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		if err := ep.next(); err != nil {
+			return nil, err
+		}
+		r, err := ep.primary()
+		return binaryNode{op, l, r}, err
+	}
+	return l, nil
+}
+
+// primary parses a parenthesized expression, a literal, or an argument name
+func (ep *exprParser) primary() (exprNode, error) {
+	tok, pos := ep.tok, ep.tokPos
+	if err := ep.next(); err != nil {
+		return nil, err
+	}
+	switch {
+	case tok == "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case tok == "(":
+		n, err := ep.or()
+		if err != nil {
+			return nil, err
+		}
+		if ep.tok != ")" {
+			return nil, fmt.Errorf("missing ) at offset %d", ep.tokPos)
+		}
+		return n, ep.next()
+	case tok[0] == '\'' || tok[0] == '"':
+		return litNode{tok[1 : len(tok)-1]}, nil
+	case tok[0] == '-' || tok[0] == '.' || ('0' <= tok[0] && tok[0] <= '9'):
+		f, err := strconv.ParseFloat(tok, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at offset %d", tok, pos)
+		}
+		return litNode{f}, nil
+	case isIdentByte(tok[0]):
+		if _, ok := ep.known[tok]; !ok {
+			return nil, fmt.Errorf("unknown argument %s", tok)
+		}
+		return identNode{tok}, nil
+	}
+	return nil, fmt.Errorf("unexpected %q at offset %d", tok, pos)
+}
+
+// compileRules compiles the RequiredIf expression of every argument
+func (p *Parser) compileRules() error {
+	names := make([]string, 0, len(p.defs))
+	for name, def := range p.defs {
+		if def.RequiredIf != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		n, err := compileExpr(p.defs[name].RequiredIf, p.defs)
+		if err != nil {
+			return fmt.Errorf("RequiredIf of --%s: %v", name, err)
+		}
+		if p.rules == nil {
+			p.rules = make(map[string]exprNode)
+		}
+		p.rules[name] = n
+	}
+	return nil
+}
+
+// checkRules reports the first argument whose RequiredIf expression holds
+// for the parsed values although the argument was not given
+func (p *Parser) checkRules(used map[string]bool) error {
+	names := make([]string, 0, len(p.rules))
+	for name := range p.rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if used[name] || !truthy(p.rules[name].eval(p.parsed)) {
+			continue
+		}
+		def := p.defs[name]
+		return argError(def, fmt.Errorf("argument %s is required when %s", flagName(def), def.RequiredIf))
+	}
+	return nil
+}
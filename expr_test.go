@@ -0,0 +1,66 @@
+package uargs_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestRequiredIf tests value-dependent requirements written as expressions
+func TestRequiredIf(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "format", Usage: "Output format", Type: uargs.String, Default: "html"},
+		{Name: "template", Usage: "Template", Type: uargs.String},
+		{Name: "auth", Usage: "Auth method", Type: uargs.String},
+		{Name: "key", Usage: "API key", Type: uargs.String, RequiredIf: "auth == 'token'"},
+		{Name: "font", Usage: "Font", Type: uargs.String, RequiredIf: "format == 'pdf' && !template"},
+		{Name: "level", Usage: "Level", Type: uargs.Int},
+		{Name: "reason", Usage: "Reason", Type: uargs.String, RequiredIf: `(level >= 3 || auth == "none") && format != "text"`},
+	}
+	parser := uargs.NewParser(args)
+
+	tests := []struct {
+		argv []string
+		want string
+	}{
+		{[]string{"--auth", "password"}, ""},
+		{[]string{"--auth", "token"}, "argument --key is required when auth == 'token'"},
+		{[]string{"--auth", "token", "--key", "k"}, ""},
+		{[]string{"--format", "pdf"}, "argument --font is required when format == 'pdf' && !template"},
+		{[]string{"--format", "pdf", "--template", "t"}, ""},
+		{[]string{"--format", "pdf", "--font", "serif"}, ""},
+		{[]string{"--level", "2"}, ""},
+		{[]string{"--level", "3"}, "argument --reason is required when"},
+		{[]string{"--level", "3", "--format", "text"}, ""},
+		{[]string{"--auth", "none", "--reason", "testing"}, ""},
+	}
+	for _, tt := range tests {
+		_, err := parser.ParseArgs(tt.argv)
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("%v: unexpected error %v", tt.argv, err)
+		case tt.want != "" && (err == nil || !strings.HasPrefix(err.Error(), tt.want)):
+			t.Errorf("%v: expected error %q, got %v", tt.argv, tt.want, err)
+		}
+	}
+
+	if !strings.Contains(parser.Usage(), "API key (required when auth == 'token')") {
+		t.Errorf("Expected rule in usage, got:\n%s", parser.Usage())
+	}
+
+	invalid := map[string]string{
+		"mode == 'x'":     "RequiredIf of --a: unknown argument mode",
+		"b == 'x":         "RequiredIf of --a: unterminated string at offset 5",
+		"(b == 'x'":       "RequiredIf of --a: missing ) at offset 9",
+		"b ==":            "RequiredIf of --a: unexpected end of expression",
+		"b == 'x' b":      "RequiredIf of --a: unexpected \"b\" at offset 9",
+		"b == 'x' && # 1": "RequiredIf of --a: unexpected '#' at offset 12",
+	}
+	for expr, want := range invalid {
+		_, err := uargs.NewParserE([]uargs.ArgDef{{Name: "a", RequiredIf: expr}, {Name: "b"}})
+		if err == nil || err.Error() != want {
+			t.Errorf("%s: expected %q, got %v", expr, want, err)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package uargs
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// checkGraph reports references to unknown arguments in OptionalIfGiven,
+// RequiredIfGiven, ConflictsWith, and argument groups, and requirements that
+// can never be satisfied, such as an argument that requires another it
+// conflicts with, directly or through a chain of requirements.
+func (p *Parser) checkGraph() error {
+	names := make([]string, 0, len(p.defs))
+	for name := range p.defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		def := p.defs[name]
+		for _, field := range []struct {
+			name string
+			refs []string
+		}{
+			{"OptionalIfGiven", def.OptionalIfGiven},
+			{"RequiredIfGiven", def.RequiredIfGiven},
+			{"ConflictsWith", def.ConflictsWith},
+		} {
+			for _, ref := range field.refs {
+				if ref == name {
+					return fmt.Errorf("--%s lists itself in %s", name, field.name)
+				}
+				if _, ok := p.defs[ref]; !ok {
+					return fmt.Errorf("%s of --%s refers to unknown argument --%s", field.name, name, ref)
+				}
+			}
+		}
+	}
+	for _, g := range p.groups {
+		for _, ref := range g.names {
+			if _, ok := p.defs[ref]; !ok {
+				return fmt.Errorf("argument group refers to unknown argument --%s", ref)
+			}
+		}
+	}
+
+	// requires maps each argument to those that must be given along with it
+	requires := make(map[string][]string)
+	conflicts := make(map[string]map[string]bool)
+	addConflict := func(a, b string) {
+		if conflicts[a] == nil {
+			conflicts[a] = make(map[string]bool)
+		}
+		conflicts[a][b] = true
+	}
+	for _, name := range names {
+		def := p.defs[name]
+		for _, trigger := range def.RequiredIfGiven {
+			requires[trigger] = append(requires[trigger], name)
+		}
+		for _, other := range def.ConflictsWith {
+			addConflict(name, other)
+			addConflict(other, name)
+		}
+	}
+	for _, g := range p.groups {
+		if g.kind == groupTogether {
+			for _, a := range g.names {
+				requires[a] = append(requires[a], g.names...)
+			}
+		}
+	}
+
+	// closure returns the given arguments and everything they require, sorted
+	closure := func(start []string) []string {
+		seen := make(map[string]bool)
+		queue := append([]string(nil), start...)
+		for len(queue) > 0 {
+			name := queue[0]
+			queue = queue[1:]
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			queue = append(queue, requires[name]...)
+		}
+		all := make([]string, 0, len(seen))
+		for name := range seen {
+			all = append(all, name)
+		}
+		sort.Strings(all)
+		return all
+	}
+	// conflict returns the first conflicting pair in set, if any
+	conflict := func(set []string) (string, string, bool) {
+		for i, a := range set {
+			for _, b := range set[i+1:] {
+				if conflicts[a][b] {
+					return a, b, true
+				}
+			}
+		}
+		return "", "", false
+	}
+
+	for _, name := range names {
+		a, b, ok := conflict(closure([]string{name}))
+		switch {
+		case !ok:
+		case a == name || b == name:
+			if a == name {
+				a = b
+			}
+			return fmt.Errorf("contradictory requirements: giving --%s also requires --%s, which conflicts with it", name, a)
+		default:
+			return fmt.Errorf("contradictory requirements: giving --%s also requires --%s and --%s, which conflict", name, a, b)
+		}
+	}
+
+	var required []string
+	for _, name := range names {
+		if def := p.defs[name]; def.Required && len(def.OptionalIfGiven) == 0 {
+			required = append(required, name)
+		}
+	}
+	required = closure(required)
+	if a, b, ok := conflict(required); ok {
+		return fmt.Errorf("contradictory requirements: --%s and --%s are always required but conflict", a, b)
+	}
+	for _, g := range p.groups {
+		if g.kind != groupExactlyOne {
+			continue
+		}
+		var both []string
+		for _, name := range g.names {
+			if slices.Contains(required, name) {
+				both = append(both, name)
+			}
+		}
+		if len(both) > 1 {
+			return fmt.Errorf("contradictory requirements: --%s and --%s are always required but only one of them may be given", both[0], both[1])
+		}
+	}
+	return nil
+}
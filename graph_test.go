@@ -0,0 +1,88 @@
+package uargs_test
+
+import (
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestNewParserE tests construction-time checks of references between arguments
+func TestNewParserE(t *testing.T) {
+	tests := []struct {
+		name string
+		args []uargs.ArgDef
+		opts []uargs.Option
+		want string
+	}{
+		{
+			name: "unknown reference",
+			args: []uargs.ArgDef{{Name: "a", RequiredIfGiven: []string{"b"}}},
+			want: "RequiredIfGiven of --a refers to unknown argument --b",
+		},
+		{
+			name: "self reference",
+			args: []uargs.ArgDef{{Name: "a", ConflictsWith: []string{"a"}}},
+			want: "--a lists itself in ConflictsWith",
+		},
+		{
+			name: "unknown group member",
+			args: []uargs.ArgDef{{Name: "a"}},
+			opts: []uargs.Option{uargs.WithExactlyOneOf("a", "b")},
+			want: "argument group refers to unknown argument --b",
+		},
+		{
+			name: "requires a conflicting argument",
+			args: []uargs.ArgDef{
+				{Name: "a", RequiredIfGiven: []string{"b"}},
+				{Name: "b", ConflictsWith: []string{"a"}},
+			},
+			want: "contradictory requirements: giving --b also requires --a, which conflicts with it",
+		},
+		{
+			name: "conflict through a requirement cycle",
+			args: []uargs.ArgDef{
+				{Name: "a", RequiredIfGiven: []string{"c"}},
+				{Name: "b", RequiredIfGiven: []string{"a"}},
+				{Name: "c", RequiredIfGiven: []string{"b"}},
+				{Name: "d", RequiredIfGiven: []string{"c"}, ConflictsWith: []string{"b"}},
+			},
+			want: "contradictory requirements: giving --a also requires --b and --d, which conflict",
+		},
+		{
+			name: "required together but conflicting",
+			args: []uargs.ArgDef{{Name: "a"}, {Name: "b", ConflictsWith: []string{"a"}}},
+			opts: []uargs.Option{uargs.WithRequiredTogether("a", "b")},
+			want: "contradictory requirements: giving --a also requires --b, which conflicts with it",
+		},
+		{
+			name: "always required but conflicting",
+			args: []uargs.ArgDef{{Name: "a", Required: true}, {Name: "b", Required: true, ConflictsWith: []string{"a"}}},
+			want: "contradictory requirements: --a and --b are always required but conflict",
+		},
+		{
+			name: "always required in an exactly-one group",
+			args: []uargs.ArgDef{{Name: "a", Required: true}, {Name: "b", Required: true}},
+			opts: []uargs.Option{uargs.WithExactlyOneOf("a", "b")},
+			want: "contradictory requirements: --a and --b are always required but only one of them may be given",
+		},
+	}
+	for _, tt := range tests {
+		parser, err := uargs.NewParserE(tt.args, tt.opts...)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("%s: expected %q, got %v", tt.name, tt.want, err)
+		}
+		if _, err := parser.ParseArgs(nil); err == nil || err.Error() != tt.want {
+			t.Errorf("%s: expected Parse to report %q, got %v", tt.name, tt.want, err)
+		}
+	}
+
+	// Mutual requirements and mutual fallbacks are consistent
+	_, err := uargs.NewParserE([]uargs.ArgDef{
+		{Name: "user", Required: true, OptionalIfGiven: []string{"token"}, RequiredIfGiven: []string{"password"}},
+		{Name: "password", RequiredIfGiven: []string{"user"}, ConflictsWith: []string{"token"}},
+		{Name: "token", Required: true, OptionalIfGiven: []string{"user"}},
+	})
+	if err != nil {
+		t.Errorf("Expected a consistent graph, got %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package uargs
+
+import "fmt"
+
+// groupKind identifies the rule enforced by an argument group
+type groupKind int
+
+const (
+	// groupTogether requires all arguments of the group once any of them is given
+	groupTogether groupKind = iota
+	// groupExactlyOne requires exactly one argument of the group
+	groupExactlyOne
+	// groupAtLeastOne requires one or more arguments of the group
+	groupAtLeastOne
+)
+
+// argGroup is a constraint over a set of arguments, registered through options
+// such as WithRequiredTogether
+type argGroup struct {
+	kind  groupKind
+	names []string
+}
+
+// WithRequiredTogether declares that the named arguments must all be given if
+// any one of them is, as with a certificate and its key.
+//
+// Example:
+//
+//	parser := uargs.NewParser(args, uargs.WithRequiredTogether("tls-cert", "tls-key"))
+//	// --tls-cert a.pem alone fails with:
+//	// arguments --tls-cert, --tls-key must be used together (missing --tls-key)
+func WithRequiredTogether(names ...string) Option {
+	return func(p *Parser) {
+		p.groups = append(p.groups, argGroup{groupTogether, names})
+	}
+}
+
+// WithExactlyOneOf declares that exactly one of the named arguments must be
+// given, failing both when none or several are present. This suits choosing
+// an input source.
+//
+// Example:
+//
+//	parser := uargs.NewParser(args, uargs.WithExactlyOneOf("stdin", "file", "url"))
+func WithExactlyOneOf(names ...string) Option {
+	return func(p *Parser) {
+		p.groups = append(p.groups, argGroup{groupExactlyOne, names})
+	}
+}
+
+// WithAtLeastOneOf declares that one or more of the named arguments must be
+// given, as with a tool that needs at least one of --add, --remove, or --list.
+//
+// Example:
+//
+//	parser := uargs.NewParser(args, uargs.WithAtLeastOneOf("add", "remove", "list"))
+func WithAtLeastOneOf(names ...string) Option {
+	return func(p *Parser) {
+		p.groups = append(p.groups, argGroup{groupAtLeastOne, names})
+	}
+}
+
+// checkGroups reports the first argument group whose rule is violated
+func (p *Parser) checkGroups(used map[string]bool) error {
+	for _, g := range p.groups {
+		var given, missing []string
+		for _, name := range g.names {
+			if used[name] {
+				given = append(given, name)
+			} else {
+				missing = append(missing, name)
+			}
+		}
+		switch g.kind {
+		case groupTogether:
+			if len(given) > 0 && len(missing) > 0 {
+				return fmt.Errorf("arguments %s must be used together (missing %s)", p.flagNames(g.names), p.flagNames(missing))
+			}
+		case groupExactlyOne:
+			if len(given) == 0 {
+				return fmt.Errorf("one of %s is required", p.flagNames(g.names))
+			}
+			if len(given) > 1 {
+				return fmt.Errorf("only one of %s can be used (got %s)", p.flagNames(g.names), p.flagNames(given))
+			}
+		case groupAtLeastOne:
+			if len(given) == 0 {
+				return fmt.Errorf("at least one of %s is required", p.flagNames(g.names))
+			}
+		}
+	}
+	return nil
+}
+
+// describeGroup returns a sentence explaining a group's rule for usage output
+func (p *Parser) describeGroup(g argGroup) string {
+	switch g.kind {
+	case groupTogether:
+		return p.flagNames(g.names) + " must be used together"
+	case groupExactlyOne:
+		return "exactly one of " + p.flagNames(g.names) + " is required"
+	case groupAtLeastOne:
+		return "at least one of " + p.flagNames(g.names) + " is required"
+	}
+	return ""
+}
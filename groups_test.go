@@ -0,0 +1,77 @@
+package uargs_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestRequiredTogether tests groups of arguments that must be given together
+func TestRequiredTogether(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "tls-cert", Usage: "Certificate file", Type: uargs.File},
+		{Name: "tls-key", Usage: "Key file", Type: uargs.File},
+	}
+	parser := uargs.NewParser(args, uargs.WithRequiredTogether("tls-cert", "tls-key"))
+
+	if _, err := parser.ParseArgs(nil); err != nil {
+		t.Errorf("Expected no error when neither is given, got %v", err)
+	}
+	if _, err := parser.ParseArgs([]string{"--tls-cert", "a.pem", "--tls-key", "a.key"}); err != nil {
+		t.Errorf("Expected no error when both are given, got %v", err)
+	}
+
+	_, err := parser.ParseArgs([]string{"--tls-cert", "a.pem"})
+	want := "arguments --tls-cert | --tls-key must be used together (missing --tls-key)"
+	if err == nil || err.Error() != want {
+		t.Errorf("Expected error %q, got %v", want, err)
+	}
+
+	if !strings.Contains(parser.Usage(), "Constraints:\n  --tls-cert | --tls-key must be used together\n") {
+		t.Errorf("Expected group in usage, got %q", parser.Usage())
+	}
+}
+
+// TestExactlyOneOf tests groups where exactly one argument must be given
+func TestExactlyOneOf(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "stdin", Usage: "Read standard input", NumArgs: 0, Type: uargs.String},
+		{Name: "file", Usage: "Read a file", Type: uargs.File},
+		{Name: "url", Usage: "Fetch a URL", Type: uargs.String},
+	}
+	parser := uargs.NewParser(args, uargs.WithExactlyOneOf("stdin", "file", "url"))
+
+	if _, err := parser.ParseArgs([]string{"--url", "https://example.com"}); err != nil {
+		t.Errorf("Expected no error with one source, got %v", err)
+	}
+
+	_, err := parser.ParseArgs(nil)
+	if want := "one of --stdin | --file | --url is required"; err == nil || err.Error() != want {
+		t.Errorf("Expected error %q, got %v", want, err)
+	}
+
+	_, err = parser.ParseArgs([]string{"--file", "a.txt", "--url", "x"})
+	if want := "only one of --stdin | --file | --url can be used (got --file | --url)"; err == nil || err.Error() != want {
+		t.Errorf("Expected error %q, got %v", want, err)
+	}
+}
+
+// TestAtLeastOneOf tests groups where at least one argument must be given
+func TestAtLeastOneOf(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "add", Short: "a", Usage: "Add an item", Type: uargs.String},
+		{Name: "remove", Short: "r", Usage: "Remove an item", Type: uargs.String},
+		{Name: "list", Short: "l", Usage: "List items", NumArgs: 0, Type: uargs.String},
+	}
+	parser := uargs.NewParser(args, uargs.WithAtLeastOneOf("add", "remove", "list"))
+
+	if _, err := parser.ParseArgs([]string{"-a", "x", "-r", "y"}); err != nil {
+		t.Errorf("Expected no error with two actions, got %v", err)
+	}
+
+	_, err := parser.ParseArgs(nil)
+	if want := "at least one of -a, --add | -r, --remove | -l, --list is required"; err == nil || err.Error() != want {
+		t.Errorf("Expected error %q, got %v", want, err)
+	}
+}
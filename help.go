@@ -0,0 +1,54 @@
+package uargs
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// HelpName is the name of the flag and command registered by WithHelp
+const HelpName = "help"
+
+// isHelpFlag reports whether the token arg requests help, as --help or -h. An
+// argument named help or with the short name h takes precedence.
+func (p *Parser) isHelpFlag(arg string) bool {
+	if p.help == nil {
+		return false
+	}
+	if arg == "--"+HelpName {
+		_, defined := p.defs[HelpName]
+		return !defined
+	}
+	if arg == "-h" {
+		_, defined := p.shortToLong["h"]
+		return !defined
+	}
+	return false
+}
+
+// isHelpCommand reports whether the word arg is the help command, which exists
+// for parsers with subcommands unless one of them is called help
+func (p *Parser) isHelpCommand(arg string) bool {
+	return p.help != nil && arg == HelpName && len(p.commands) > 0 && p.findCommand(HelpName) == nil
+}
+
+// writeHelp writes the usage of the command named by the words in argv, as in
+// "help remote add", or of p itself if there are none, to w. Flags in argv are
+// ignored. It returns ErrHelp once the usage is written.
+func (p *Parser) writeHelp(w io.Writer, argv []string) error {
+	target := p
+	for _, arg := range argv {
+		if strings.HasPrefix(arg, "-") {
+			continue
+		}
+		next := target.findCommand(arg)
+		if next == nil {
+			return target.unknown(MatchCommand, arg, fmt.Sprintf("unknown command %s", arg))
+		}
+		target = next
+	}
+	if _, err := io.WriteString(w, target.Usage()); err != nil {
+		return err
+	}
+	return ErrHelp
+}
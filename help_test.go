@@ -0,0 +1,83 @@
+package uargs_test
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestHelp tests --help, -h, and the help command
+func TestHelp(t *testing.T) {
+	var out strings.Builder
+	parser := uargs.NewParser([]uargs.ArgDef{
+		{Name: "verbose", Short: "v", Usage: "Verbosity", Type: uargs.Int, Persistent: true},
+	}, uargs.WithHelp(&out))
+	remote := parser.AddCommand(uargs.Command{Name: "remote", Usage: "Manage remotes"})
+	add := remote.AddCommand(uargs.Command{Name: "add", Usage: "Add a remote", Args: []uargs.ArgDef{
+		{Name: "url", Usage: "Remote URL", Type: uargs.String, Required: true},
+	}})
+
+	// Test case 1: Each form shows the usage of the active command
+	cases := []struct {
+		argv   []string
+		parser *uargs.Parser
+	}{
+		{[]string{"--help"}, parser},
+		{[]string{"-h"}, parser},
+		{[]string{"help"}, parser},
+		{[]string{"remote", "--help"}, remote},
+		{[]string{"help", "remote"}, remote},
+		{[]string{"remote", "add", "--help"}, add},
+		{[]string{"remote", "add", "-h", "--url"}, add},
+		{[]string{"help", "remote", "add"}, add},
+		{[]string{"remote", "help", "add"}, add},
+	}
+	for _, c := range cases {
+		out.Reset()
+		if _, err := parser.ParseArgs(c.argv); !errors.Is(err, uargs.ErrHelp) {
+			t.Errorf("%v: expected ErrHelp, got %v", c.argv, err)
+		}
+		// Rows are not in a fixed order, so lines are compared as sets
+		if sortedLines(out.String()) != sortedLines(c.parser.Usage()) {
+			t.Errorf("%v: expected the usage of %q, got:\n%s", c.argv, c.parser.Name(), out.String())
+		}
+	}
+	usage := add.Usage()
+	if !strings.HasPrefix(usage, "Usage: remote add\n\nAdd a remote\n\n") || !strings.Contains(usage, "--url") || strings.Contains(usage, "Commands:") {
+		t.Errorf("Expected the add description and flags, got:\n%s", usage)
+	}
+
+	// Test case 2: Unknown commands after help are errors
+	if _, err := parser.ParseArgs([]string{"help", "remote", "ad"}); err == nil || err.Error() != "unknown command ad, did you mean add?" {
+		t.Errorf("Expected an unknown command error, got %v", err)
+	}
+
+	// Test case 3: Defined arguments and commands take precedence
+	out.Reset()
+	own := uargs.NewParser([]uargs.ArgDef{
+		{Name: "host", Short: "h", Usage: "Host", Type: uargs.String},
+	}, uargs.WithHelp(&out))
+	own.AddCommand(uargs.Command{Name: "help", Usage: "Open the manual"})
+	if _, err := own.ParseArgs([]string{"-h", "example.com", "help"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if own.Result().Command().Name() != "help" || out.Len() != 0 {
+		t.Errorf("Expected -h and help to be handled as defined, got help output %q", out.String())
+	}
+
+	// Test case 4: Help is off by default
+	plain := uargs.NewParser(nil)
+	if _, err := plain.ParseArgs([]string{"--help"}); err == nil || err.Error() != "unknown argument --help" {
+		t.Errorf("Expected --help to be unknown without WithHelp, got %v", err)
+	}
+}
+
+// sortedLines returns the lines of s in sorted order
+func sortedLines(s string) string {
+	lines := strings.Split(s, "\n")
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,86 @@
+package uargs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// parseINI reads an INI file of key = value (or key: value) lines, with ; and #
+// comments and [section] headers. Keys before the first section belong to the
+// root parser. The words of a section name, separated by dots or spaces, name
+// either a subcommand path, as in [remote add], or a prefix of argument names,
+// so that level in [log] sets --log-level; each split of the words is stored,
+// and the argument names of the parsers decide which one applies, with later
+// lines replacing earlier ones. Repeating a key adds values for multi-value
+// arguments.
+func (c *configFile) parseINI(data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	var section []string
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+		if line[0] == '[' {
+			if !strings.HasSuffix(line, "]") {
+				return fmt.Errorf("%s:%d: expected [section]", c.path, n)
+			}
+			section = strings.FieldsFunc(line[1:len(line)-1], func(r rune) bool {
+				return r == '.' || r == ' ' || r == '\t'
+			})
+			continue
+		}
+		end := strings.IndexAny(line, "=:")
+		if end < 0 {
+			return fmt.Errorf("%s:%d: expected key = value", c.path, n)
+		}
+		name := strings.TrimSpace(line[:end])
+		key := strings.Join(append(section[:len(section):len(section)], name), ".")
+		if name == "" || strings.ContainsAny(name, " \t") {
+			return fmt.Errorf("%s:%d: invalid key %q", c.path, n, name)
+		}
+		value, err := iniValue(strings.TrimSpace(line[end+1:]))
+		if err != nil {
+			return fmt.Errorf("%s:%d: %s: %v", c.path, n, key, err)
+		}
+		for i := 0; i <= len(section); i++ {
+			words := append(section[:i:i], strings.Join(append(section[i:len(section):len(section)], name), "-"))
+			alias := strings.Join(words, ".")
+			v, ok := c.values[alias]
+			if !ok || v.key != key {
+				v = configValue{key: key, line: n, column: indentOf(scanner.Text()) + 1}
+			}
+			v.args = append(v.args, value)
+			c.values[alias] = v
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%s: %v", c.path, err)
+	}
+	return nil
+}
+
+// iniValue unquotes the value of an INI line. Single and double quotes are
+// removed without escapes, and in unquoted values a ; or # after whitespace
+// starts a comment.
+func iniValue(s string) (string, error) {
+	if s != "" && (s[0] == '"' || s[0] == '\'') {
+		end := strings.IndexByte(s[1:], s[0])
+		if end < 0 {
+			return "", fmt.Errorf("unterminated quote %c", s[0])
+		}
+		if rest := strings.TrimSpace(s[end+2:]); rest != "" && rest[0] != ';' && rest[0] != '#' {
+			return "", fmt.Errorf("unexpected text after value")
+		}
+		return s[1 : end+1], nil
+	}
+	for i := 1; i < len(s); i++ {
+		if (s[i] == ';' || s[i] == '#') && (s[i-1] == ' ' || s[i-1] == '\t') {
+			s = s[:i]
+			break
+		}
+	}
+	return strings.TrimSpace(s), nil
+}
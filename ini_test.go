@@ -0,0 +1,83 @@
+package uargs_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestConfigINI tests taking values from an INI config file
+func TestConfigINI(t *testing.T) {
+	path := writeConfig(t, "app.ini", `; Legacy settings
+port = 8080
+host: "example.com ; not a comment"
+tags = a
+tags = b ; second tag
+
+[log]
+level = debug
+# full-line comment
+file = /var/log/app.log
+
+[remote add]
+name = origin
+`)
+	parser := uargs.NewParser([]uargs.ArgDef{
+		{Name: "port", Usage: "Port", Type: uargs.Int},
+		{Name: "host", Usage: "Host", Type: uargs.String},
+		{Name: "tags", Usage: "Tags", Type: uargs.String, NumArgs: 2},
+		{Name: "log-level", Usage: "Log level", Type: uargs.String},
+	}, uargs.WithConfigFile(path))
+	remote := parser.AddCommand(uargs.Command{Name: "remote", Usage: "Manage remotes"})
+	add := remote.AddCommand(uargs.Command{Name: "add", Usage: "Add a remote", Args: []uargs.ArgDef{
+		{Name: "name", Usage: "Remote name", Type: uargs.String},
+	}})
+	logs := parser.AddCommand(uargs.Command{Name: "log", Usage: "Show the log", Args: []uargs.ArgDef{
+		{Name: "file", Usage: "Log file", Type: uargs.String},
+	}})
+
+	// Test case 1: Sections name argument prefixes and subcommands
+	parsed, err := parser.ParseArgs([]string{"remote", "add"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parsed["port"] != 8080 || parsed["host"] != "example.com ; not a comment" || parsed["log-level"] != "debug" {
+		t.Errorf("Expected values from the config file, got %v", parsed)
+	}
+	if tags, ok := parsed["tags"].([]string); !ok || strings.Join(tags, "|") != "a|b" {
+		t.Errorf("Expected tags a and b, got %v", parsed["tags"])
+	}
+	if name := add.Result().Map()["name"]; name != "origin" {
+		t.Errorf("Expected name=origin, got %v", name)
+	}
+
+	// Test case 2: The same section serves a subcommand
+	if _, err := parser.ParseArgs([]string{"log"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if file := logs.Result().Map()["file"]; file != "/var/log/app.log" {
+		t.Errorf("Expected file=/var/log/app.log, got %v", file)
+	}
+
+	// Test case 3: Errors give the file, line, and key
+	tests := []struct {
+		content, expected string
+	}{
+		{"port = 80\n[log\nlevel = debug", "%s:2: expected [section]"},
+		{"port", "%s:1: expected key = value"},
+		{"[log]\nlevel = 'debug", "%s:2: log.level: unterminated quote '"},
+		{"port = 80\nport = http", `%s:1:1: port: expected int, got "http"`},
+	}
+	for _, test := range tests {
+		path := writeConfig(t, "app.conf", test.content)
+		parser := uargs.NewParser([]uargs.ArgDef{
+			{Name: "port", Usage: "Port", Type: uargs.Int, NumArgs: 2},
+		}, uargs.WithConfigFile(path))
+		_, err := parser.ParseArgs(nil)
+		expected := strings.ReplaceAll(test.expected, "%s", path)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected an error containing %q, got %v", expected, err)
+		}
+	}
+}
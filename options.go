@@ -0,0 +1,454 @@
+package uargs
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Option configures optional Parser behaviour and is passed to NewParser
+type Option func(*Parser)
+
+// WithStdin sets the reader returned for File arguments given as "-".
+// It defaults to os.Stdin.
+func WithStdin(r io.Reader) Option {
+	return func(p *Parser) {
+		p.stdin = r
+	}
+}
+
+// WithEnvExpansion makes the parser expand $VAR and ${VAR} references in argument
+// values before type conversion. Use $$ to produce a literal dollar sign.
+//
+// Example:
+//
+//	parser := uargs.NewParser(args, uargs.WithEnvExpansion())
+//	// --data-dir '${HOME}/data' is parsed as "/home/user/data"
+func WithEnvExpansion() Option {
+	return func(p *Parser) {
+		p.expandEnv = true
+	}
+}
+
+// WithAccessibleUsage makes Usage return the screen-reader-friendly layout of
+// AccessibleUsage. The same layout can be selected by users through the
+// UARGS_ACCESSIBLE environment variable.
+func WithAccessibleUsage() Option {
+	return func(p *Parser) {
+		p.accessible = true
+	}
+}
+
+// WithSortedUsage makes Usage and AccessibleUsage list arguments in
+// alphabetical order instead of the order they were defined in. Subcommands
+// added later use the same order.
+func WithSortedUsage() Option {
+	return func(p *Parser) {
+		p.sortUsage = true
+	}
+}
+
+// WithLogger makes the parser emit debug events to logger as it works: tokens
+// consumed, flags matched, the source each value was resolved from, and the
+// validators that ran. This helps diagnose why an unexpected value was produced.
+//
+// Example:
+//
+//	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+//	parser := uargs.NewParser(args, uargs.WithLogger(logger))
+func WithLogger(logger *slog.Logger) Option {
+	return func(p *Parser) {
+		p.logger = logger
+	}
+}
+
+// WithWarnings sets where non-fatal messages such as deprecation notices are
+// written, keeping them apart from returned errors. It defaults to os.Stderr;
+// pass io.Discard to silence warnings.
+func WithWarnings(w io.Writer) Option {
+	return func(p *Parser) {
+		p.warnings = w
+	}
+}
+
+// WithPrintFlags registers a hidden --print-flags flag. When it is given, Parse
+// writes a JSON description of every argument to w (os.Stdout if nil),
+// including its effective value, and returns ErrPrintFlags. External wrappers
+// can use it to introspect the tool they drive. An argument named print-flags
+// takes precedence over the built-in flag.
+//
+// Example:
+//
+//	parser := uargs.NewParser(args, uargs.WithPrintFlags(nil))
+//	if _, err := parser.Parse(); errors.Is(err, uargs.ErrPrintFlags) {
+//		os.Exit(0)
+//	}
+func WithPrintFlags(w io.Writer) Option {
+	return func(p *Parser) {
+		if w == nil {
+			w = os.Stdout
+		}
+		p.printFlags = w
+	}
+}
+
+// WithHelp registers --help and -h, and a help command for parsers with
+// subcommands. When help is requested, Parse writes the usage of the active
+// command to w (os.Stdout if nil) and returns ErrHelp, so "mytool add --help"
+// and "mytool help add" both show the flags of add. Arguments named help or
+// with the short name h, and commands named help, take precedence. Subcommands
+// added later inherit the setting.
+//
+// Example:
+//
+//	parser := uargs.NewParser(args, uargs.WithHelp(nil))
+//	if _, err := parser.Parse(); errors.Is(err, uargs.ErrHelp) {
+//		os.Exit(0)
+//	}
+func WithHelp(w io.Writer) Option {
+	return func(p *Parser) {
+		if w == nil {
+			w = os.Stdout
+		}
+		p.help = w
+	}
+}
+
+// WithPlugins resolves unknown commands to executables on PATH named
+// "<prefix>-<command>", as git and kubectl plugins are. The prefix defaults to
+// the program name if empty. Execute runs the plugin with the arguments after
+// the command name and returns its failure as an *exec.ExitError, whose
+// ExitCode can be passed to os.Exit. Built-in commands take precedence.
+//
+// Example:
+//
+//	parser := uargs.NewParser(args, uargs.WithPlugins("mytool"))
+//	// "mytool hello --loud" runs mytool-hello --loud
+func WithPlugins(prefix string) Option {
+	return func(p *Parser) {
+		if prefix == "" {
+			prefix = strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe")
+		}
+		p.pluginPrefix = prefix
+	}
+}
+
+// WithOutput sets where RunREPL writes its prompts, help text, and errors, and
+// where the version requested with WithVersion is written. It defaults to
+// os.Stdout; input is read from the reader set with WithStdin.
+func WithOutput(w io.Writer) Option {
+	return func(p *Parser) {
+		p.output = w
+	}
+}
+
+// WithExitCodes changes the exit codes returned by ExitCode and used by
+// ExecuteAndExit for each kind of error (see DefaultExitCodes)
+//
+// Example:
+//
+//	codes := uargs.DefaultExitCodes
+//	codes.Usage = 64 // EX_USAGE
+//	parser := uargs.NewParser(args, uargs.WithExitCodes(codes))
+func WithExitCodes(codes ExitCodes) Option {
+	return func(p *Parser) {
+		p.exitCodes = &codes
+	}
+}
+
+// WithDeprecatedCommands shows or hides deprecated commands in help text,
+// command lists in errors, and suggestions. They are shown with their
+// deprecation message by default, and run normally either way. Subcommands
+// added later inherit the setting.
+func WithDeprecatedCommands(show bool) Option {
+	return func(p *Parser) {
+		p.hideDeprecated = !show
+	}
+}
+
+// WithVersion registers --version and -V, and a version command for parsers
+// with subcommands. They write info to the output set with WithOutput, as JSON
+// when followed by --json, and make Parse return ErrVersion. Empty fields of
+// info are filled in from BuildVersion. Arguments named version or with the
+// short name V, and commands named version, take precedence.
+//
+// Example:
+//
+//	// Set with -ldflags "-X main.version=v1.4.2"
+//	var version string
+//	parser := uargs.NewParser(args, uargs.WithVersion(uargs.VersionInfo{Version: version}))
+func WithVersion(info VersionInfo) Option {
+	return func(p *Parser) {
+		build := BuildVersion()
+		if info.Version == "" {
+			info.Version = build.Version
+		}
+		if info.Commit == "" {
+			info.Commit = build.Commit
+		}
+		if info.Date == "" {
+			info.Date = build.Date
+		}
+		p.version = &info
+	}
+}
+
+// WithEnvPrefix binds every argument to an environment variable named after
+// it, as MYAPP_LOG_LEVEL for --log-level with the prefix "MYAPP", unless its
+// Env field names another variable or is "-". Subcommands added later use the
+// same prefix.
+//
+// Example:
+//
+//	parser := uargs.NewParser(args, uargs.WithEnvPrefix("MYAPP"))
+func WithEnvPrefix(prefix string) Option {
+	return func(p *Parser) {
+		p.envPrefix = strings.TrimSuffix(prefix, "_")
+	}
+}
+
+// WithDotEnv reads environment variables for arguments with an Env variable
+// (or WithEnvPrefix) from a dotenv file of KEY=VALUE lines as well, without
+// changing the process environment. Variables set in the process take
+// precedence. The path defaults to ".env", which may be missing; an explicit
+// path must exist. The file is read on every parse.
+//
+// Example:
+//
+//	parser := uargs.NewParser(args, uargs.WithEnvPrefix("MYAPP"), uargs.WithDotEnv(""))
+func WithDotEnv(path string) Option {
+	return func(p *Parser) {
+		p.dotEnv, p.dotEnvPath = true, path
+	}
+}
+
+// WithConfigFile reads values for arguments that were given neither on the
+// command line nor through their environment variable from the config file at
+// path, before falling back to Default. The format follows the extension:
+// ".json" files hold an object whose keys are argument names, with nested
+// objects for the arguments of subcommands; ".toml" files hold keys named
+// after arguments, with [tables] for subcommands; and ".ini", ".cfg", and
+// ".conf" files hold key = value lines, with [sections] that name a
+// subcommand, as in [remote add], or a prefix of argument names, as in [log]
+// for --log-level. Values are converted and checked like command-line values.
+// The file is read on every parse and must exist.
+//
+// Example:
+//
+//	// {"port": 8080, "tags": ["a", "b"], "remote": {"add": {"name": "origin"}}}
+//	parser := uargs.NewParser(args, uargs.WithConfigFile("config.json"))
+func WithConfigFile(path string) Option {
+	return func(p *Parser) {
+		p.configPath = path
+	}
+}
+
+// WithConfigFlag registers a persistent --config PATH argument that chooses the
+// config file, overriding WithConfigFile. Like other arguments, it can also be
+// set through its environment variable. An argument named config defined by
+// the caller is used instead, so its Short, Usage, or Env can be customized.
+// Values of every argument are then taken from, in order of precedence: the
+// command line, environment variables, the config file, and Default.
+//
+// Example:
+//
+//	parser := uargs.NewParser(args, uargs.WithConfigFile("/etc/mytool.toml"), uargs.WithConfigFlag())
+//	// mytool --config ./dev.toml serve
+func WithConfigFlag() Option {
+	return func(p *Parser) {
+		p.configFlag = true
+		if _, ok := p.defs[ConfigName]; !ok {
+			p.defs[ConfigName] = ArgDef{Name: ConfigName, Usage: "Config file to read argument values from", Type: String, NumArgs: 1, Persistent: true}
+			p.order = append(p.order, ConfigName)
+		}
+	}
+}
+
+// WithConfigDiscovery searches the standard locations for a config file of
+// app when no path is given with WithConfigFile or --config: config.json,
+// config.toml, config.ini, config.cfg, or config.conf in the app directory
+// under the user config directory ($XDG_CONFIG_HOME or ~/.config on Linux,
+// ~/Library/Application Support on macOS, %APPDATA% on Windows), and on macOS
+// also under $XDG_CONFIG_HOME or ~/.config. The first file found is read, and
+// finding none is not an error. Result.ConfigFile reports the file that was
+// read.
+//
+// Example:
+//
+//	// Reads ~/.config/mytool/config.toml on Linux, if it exists
+//	parser := uargs.NewParser(args, uargs.WithConfigDiscovery("mytool"))
+func WithConfigDiscovery(app string) Option {
+	return func(p *Parser) {
+		p.configApp = app
+	}
+}
+
+// WithConfigLayers reads the config files at paths, such as a system, a user,
+// and a project file, in order, with later files replacing the values of
+// earlier ones. Files that do not exist are skipped. The file given with
+// --config or WithConfigFile, or found by WithConfigDiscovery, is read last
+// and takes precedence over all layers. Result.Config reports the merged keys
+// and the file each came from.
+//
+// Example:
+//
+//	parser := uargs.NewParser(args, uargs.WithConfigLayers(
+//		"/etc/mytool/config.toml",
+//		filepath.Join(home, ".config", "mytool", "config.toml"),
+//		".mytool.toml",
+//	))
+func WithConfigLayers(paths ...string) Option {
+	return func(p *Parser) {
+		p.configLayers = paths
+	}
+}
+
+// WithProfiles registers a persistent --profile NAME argument that applies a
+// named bundle of values, such as "staging" or "prod", to the arguments that
+// were given neither on the command line nor through their environment
+// variable. Profile values take precedence over the config file and Default.
+// profiles maps profile names to values keyed like config files: by argument
+// name, prefixed with the command path for subcommands, as in "serve.workers".
+// Values are strings, ints, float64s, or slices of them, converted and checked
+// like command-line values. Config files can define profiles too, under
+// profiles.NAME (as in a [profiles.prod] TOML table), replacing the values set
+// here, and choose a profile with a profile key. profiles may be nil.
+//
+// Example:
+//
+//	parser := uargs.NewParser(args, uargs.WithProfiles(map[string]map[string]interface{}{
+//		"staging": {"host": "staging.example.com", "replicas": 1},
+//		"prod":    {"host": "example.com", "replicas": 5, "serve.workers": 16},
+//	}))
+//	// mytool --profile prod serve
+func WithProfiles(profiles map[string]map[string]interface{}) Option {
+	return func(p *Parser) {
+		p.profileFlag, p.profiles = true, profiles
+		if _, ok := p.defs[ProfileName]; !ok {
+			p.defs[ProfileName] = ArgDef{Name: ProfileName, Usage: "Profile of argument values to apply", Type: String, NumArgs: 1, Persistent: true}
+			p.order = append(p.order, ProfileName)
+		}
+	}
+}
+
+// WithSecrets sets a provider for the values of Secret arguments that were
+// given neither on the command line nor through their environment variable,
+// profile, or config file, such as Keyring for the OS credential store. This
+// keeps tokens out of shell history and process listings. Secrets are looked
+// up by argument name, prefixed with the command path for subcommands, as in
+// "deploy.api-token". Subcommands added later use the same provider.
+//
+// Example:
+//
+//	parser := uargs.NewParser([]uargs.ArgDef{
+//		{Name: "api-token", Usage: "API token", Type: uargs.String, Secret: true, Required: true},
+//	}, uargs.WithSecrets(uargs.Keyring("mytool")))
+func WithSecrets(provider SecretProvider) Option {
+	return func(p *Parser) {
+		p.secrets = provider
+	}
+}
+
+// WithSource adds a source of values for arguments that were given neither on
+// the command line nor through the built-in sources (environment variables,
+// profiles, config files, and secrets), such as a secrets manager or a company
+// configuration service. Sources are consulted in the order they were added,
+// before falling back to Default. Subcommands added later use the same
+// sources.
+//
+// Example:
+//
+//	parser := uargs.NewParser(args, uargs.WithSource(consulSource), uargs.WithSource(vaultSource))
+func WithSource(src ValueSource) Option {
+	return func(p *Parser) {
+		p.sources = append(p.sources, src)
+	}
+}
+
+// WithPrecedence sets the order in which the sources of values are consulted
+// for arguments not given on the command line, the first with a value
+// winning, before falling back to Default. The sources are named by their
+// Origin: OriginEnv, OriginProfile, OriginConfig, OriginSecret, and
+// OriginCustom for all sources added with WithSource. Sources left out are not
+// consulted at all. The default order is env, profile, config, secret,
+// custom. The command line always takes precedence. Subcommands added later
+// use the same order.
+//
+// Example:
+//
+//	// Config files win over the environment, and profiles are ignored
+//	parser := uargs.NewParser(args, uargs.WithConfigFile("/etc/mytool.toml"),
+//		uargs.WithPrecedence(uargs.OriginConfig, uargs.OriginEnv))
+func WithPrecedence(origins ...Origin) Option {
+	return func(p *Parser) {
+		p.precedence = []Origin{}
+		for _, origin := range origins {
+			var err error
+			switch {
+			case !slices.Contains(defaultPrecedence, origin):
+				err = fmt.Errorf("WithPrecedence: unknown source %q, expected env, profile, config, secret, or custom", origin)
+			case slices.Contains(p.precedence, origin):
+				err = fmt.Errorf("WithPrecedence: duplicate source %q", origin)
+			}
+			if err != nil {
+				if p.defErr == nil {
+					p.defErr = err
+				}
+				continue
+			}
+			p.precedence = append(p.precedence, origin)
+		}
+	}
+}
+
+// WithEpilogue sets a callback that generates the footer of help text from the
+// environment it is shown in, so hints can be tailored to the situation. An
+// empty footer is omitted.
+//
+// Example:
+//
+//	parser := uargs.NewParser(args, uargs.WithEpilogue(func(env uargs.HelpEnv) string {
+//		if !env.Exists("~/.mytool.json") {
+//			return "Run 'mytool init' to create a config."
+//		}
+//		return ""
+//	}))
+func WithEpilogue(fn func(env HelpEnv) string) Option {
+	return func(p *Parser) {
+		p.epilogue = fn
+	}
+}
+
+// WithTerminal replaces DetectTerminal for checking the terminal requirements
+// of commands, for example in tests or for programs with their own detection
+func WithTerminal(detect func() TerminalInfo) Option {
+	return func(p *Parser) {
+		p.terminal = detect
+	}
+}
+
+// WithSecretAdvice turns the warning about Secret arguments given on the
+// command line on or off. It is on by default and only shown in interactive
+// sessions, once per parser.
+func WithSecretAdvice(enabled bool) Option {
+	return func(p *Parser) {
+		p.quietSecrets = !enabled
+	}
+}
+
+// WithSuggestionDistance sets the largest number of edits between a mistyped
+// flag or command and a name suggested for it. The default is 2; 0 turns
+// suggestions off. Subcommands added later inherit the setting.
+//
+// Example:
+//
+//	parser := uargs.NewParser(args, uargs.WithSuggestionDistance(1))
+func WithSuggestionDistance(n int) Option {
+	return func(p *Parser) {
+		p.suggestDistance = max(n, 0)
+	}
+}
@@ -0,0 +1,143 @@
+package uargs_test
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestWithEnvExpansion tests that $VAR references are expanded only when enabled
+func TestWithEnvExpansion(t *testing.T) {
+	originalArgs := os.Args
+	defer func() { os.Args = originalArgs }()
+	t.Setenv("UARGS_TEST_DIR", "/srv")
+
+	os.Args = []string{"app", "--dir", "${UARGS_TEST_DIR}/data", "--price", "$$5", "--count", "$UARGS_TEST_COUNT"}
+	t.Setenv("UARGS_TEST_COUNT", "7")
+
+	args := []uargs.ArgDef{
+		{Name: "dir", Usage: "Data directory", Type: uargs.String},
+		{Name: "price", Usage: "Price label", Type: uargs.String},
+		{Name: "count", Usage: "Count value", Type: uargs.Int},
+	}
+
+	parsed, err := uargs.NewParser(args, uargs.WithEnvExpansion()).Parse()
+	if err != nil {
+		t.Fatalf("Failed to parse valid arguments: %v", err)
+	}
+	if parsed["dir"] != "/srv/data" {
+		t.Errorf("Expected dir='/srv/data', got '%v'", parsed["dir"])
+	}
+	if parsed["price"] != "$5" {
+		t.Errorf("Expected price='$5', got '%v'", parsed["price"])
+	}
+	if parsed["count"] != 7 {
+		t.Errorf("Expected count=7, got %v", parsed["count"])
+	}
+
+	// Without the option values are left untouched
+	os.Args = []string{"app", "--dir", "${UARGS_TEST_DIR}"}
+	parsed, err = uargs.NewParser(args[:1]).Parse()
+	if err != nil {
+		t.Fatalf("Failed to parse valid arguments: %v", err)
+	}
+	if parsed["dir"] != "${UARGS_TEST_DIR}" {
+		t.Errorf("Expected dir to be unexpanded, got '%v'", parsed["dir"])
+	}
+}
+
+// TestWithLogger tests that parse pipeline events are logged at debug level
+func TestWithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	args := []uargs.ArgDef{
+		{Name: "port", Short: "p", Usage: "Port", Min: uargs.Bound(1), Type: uargs.Int},
+		{Name: "host", Usage: "Host", Default: "localhost", Type: uargs.String},
+	}
+
+	if _, err := uargs.NewParser(args, uargs.WithLogger(logger)).ParseArgs([]string{"-p", "80"}); err != nil {
+		t.Fatalf("Failed to parse valid arguments: %v", err)
+	}
+
+	for _, want := range []string{
+		`msg="flag matched" token=-p flag=port`,
+		`msg="token consumed" index=1 token=80 flag=port`,
+		`msg="validator run" flag=port validator=range`,
+		`msg="source resolved" flag=port source=cli`,
+		`msg="source resolved" flag=host source=default`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected log to contain %q, got:\n%s", want, buf.String())
+		}
+	}
+}
+
+// TestWithWarnings tests that deprecation notices go to the warnings writer
+func TestWithWarnings(t *testing.T) {
+	var warnings bytes.Buffer
+	args := []uargs.ArgDef{
+		{Name: "out", Usage: "Output file", Deprecated: "use --output instead", Type: uargs.String},
+		{Name: "output", Usage: "Output file", Type: uargs.String},
+	}
+	parser := uargs.NewParser(args, uargs.WithWarnings(&warnings))
+
+	parsed, err := parser.ParseArgs([]string{"--out", "a.txt"})
+	if err != nil {
+		t.Fatalf("Failed to parse valid arguments: %v", err)
+	}
+	if parsed["out"] != "a.txt" {
+		t.Errorf("Expected deprecated flag to still work, got %v", parsed["out"])
+	}
+	if want := "warning: --out is deprecated: use --output instead\n"; warnings.String() != want {
+		t.Errorf("Expected warning %q, got %q", want, warnings.String())
+	}
+
+	warnings.Reset()
+	if _, err := parser.ParseArgs([]string{"--output", "a.txt"}); err != nil {
+		t.Fatalf("Failed to parse valid arguments: %v", err)
+	}
+	if warnings.Len() != 0 {
+		t.Errorf("Expected no warnings, got %q", warnings.String())
+	}
+}
+
+// TestWithEpilogue tests help footers generated from the environment
+func TestWithEpilogue(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CI", "true")
+
+	var seen []uargs.HelpEnv
+	parser := uargs.NewParser([]uargs.ArgDef{{Name: "name", Usage: "Name"}}, uargs.WithEpilogue(func(env uargs.HelpEnv) string {
+		seen = append(seen, env)
+		if !env.Exists("~/.mytool.json") {
+			return "Run 'mytool init' to create a config."
+		}
+		return ""
+	}))
+	sub := parser.AddCommand(uargs.Command{Name: "serve"})
+
+	if usage := parser.Usage(); !strings.HasSuffix(usage, "\nRun 'mytool init' to create a config.\n") {
+		t.Errorf("Expected epilogue at the end of usage, got:\n%s", usage)
+	}
+	if len(seen) != 1 || !seen[0].CI || seen[0].OS != runtime.GOOS || len(seen[0].Command) != 0 {
+		t.Errorf("Expected root environment in CI, got %+v", seen)
+	}
+
+	if err := os.WriteFile(filepath.Join(home, ".mytool.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if usage := sub.Usage(); strings.Contains(usage, "mytool init") {
+		t.Errorf("Expected no epilogue once the config exists, got:\n%s", usage)
+	}
+	if last := seen[len(seen)-1]; len(last.Command) != 1 || last.Command[0] != "serve" {
+		t.Errorf("Expected the subcommand path, got %v", last.Command)
+	}
+}
@@ -23,14 +23,43 @@ package uargs
 //	inputFile := parsed["input"].(string)
 
 import (
-	_ "errors"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"math"
 	"os"
+	"os/user"
+	"path/filepath"
 	_ "reflect"
+	"slices"
 	"strconv"
 	"strings"
 )
 
+var (
+	// ErrNilParser is returned when a method is called on a nil *Parser
+	ErrNilParser = errors.New("uargs: nil Parser")
+	// ErrSelfTest is returned by Parse after it wrote a --self-test report
+	// without problems, enabled by WithSelfTest. Programs should exit
+	// successfully on it.
+	ErrSelfTest = errors.New("uargs: self-test passed")
+	// ErrPrintFlags is returned by Parse after it wrote the --print-flags dump
+	// enabled by WithPrintFlags. Programs should exit successfully on it.
+	ErrPrintFlags = errors.New("uargs: flags printed")
+	// ErrHelp is returned by Parse after it wrote the help text requested with
+	// --help, -h, or the help command enabled by WithHelp. Programs should exit
+	// successfully on it.
+	ErrHelp = errors.New("uargs: help requested")
+	// ErrVersion is returned by Parse after it wrote the version requested with
+	// --version, -V, or the version command enabled by WithVersion. Programs
+	// should exit successfully on it.
+	ErrVersion = errors.New("uargs: version requested")
+	// ErrNotParsed is returned when values are requested before a successful Parse
+	ErrNotParsed = errors.New("uargs: arguments have not been parsed")
+)
+
 // ArgType represents the data type of an argument value
 type ArgType string
 
@@ -41,36 +70,226 @@ const (
 	Int ArgType = "int"
 	// Float indicates the argument value should be parsed as a floating-point number
 	Float ArgType = "float"
+	// File indicates the argument value is a file path. A single value of "-" is treated
+	// as standard input and is returned as an io.Reader instead of a path string.
+	// Multi-value File arguments keep "-" as a plain string.
+	File ArgType = "file"
 )
 
+// StdinPath is the conventional File argument value meaning "read from standard input"
+const StdinPath = "-"
+
 // ArgDef defines the properties of a command-line argument
 type ArgDef struct {
 	// Name is the long name of the argument (used with --)
 	Name string
-	// Short is the single-character short name of the argument (used with -)
+	// Short is the short name of the argument (used with -). It is usually a single
+	// character, but multi-character aliases such as "th" are also accepted.
 	Short string
 	// Usage is a description of the argument for help text
 	Usage string
-	// NumArgs is the number of values expected for this argument (default: 1)
+	// DocsURL links to further documentation of the argument. It is shown in help
+	// text and appended to errors about the argument.
+	DocsURL string
+	// Example is a sample use of the argument, such as "--coords 10.5 20.3". It is
+	// shown under the argument in help text and appended to value errors.
+	Example string
+	// Metavar names the values of the argument in usage, as in "--input PATH",
+	// instead of its type. A single name is repeated for each value; several
+	// names separated by spaces, such as "X Y", name one value each.
+	Metavar string
+	// Group is the heading the argument is listed under in help text, such as
+	// "Connection options". Arguments without a Group are listed first, and
+	// groups follow in the order they first appear.
+	Group string
+	// NumArgs is the number of values expected for this argument (default: 1).
+	// Up to NumArgs values are consumed; fewer are accepted unless MinArgs is set.
 	NumArgs int
+	// MinArgs is the minimum number of values that must follow the flag
+	MinArgs int
+	// MaxArgs is the maximum number of values consumed, overriding NumArgs when
+	// set. Use -1 for no limit, e.g. MinArgs: 2, MaxArgs: -1 for "2 or more".
+	MaxArgs int
 	// Required indicates whether the argument must be provided
 	Required bool
 	// OptionalIfGiven makes this argument optional if any of the listed arguments are provided
 	OptionalIfGiven []string
-	// AcceptOverArgs allows accepting more values than specified by NumArgs
+	// RequiredIfGiven makes this argument required if any of the listed arguments are provided
+	RequiredIfGiven []string
+	// RequiredIf makes this argument required when the expression holds for the
+	// values of the other arguments, defaults included, e.g.
+	// "format == 'pdf' && !template". Names refer to arguments; operators are
+	// == != < <= > >= && || ! and parentheses; literals are quoted strings or
+	// numbers. A name alone is true when the argument has a non-empty value.
+	RequiredIf string
+	// Env names an environment variable, such as "MYAPP_PORT", whose value is
+	// used when the argument is not given on the command line. It is converted
+	// and checked like a command-line value and takes precedence over Default.
+	// Multi-value arguments split it like a shell would (see Split). It
+	// overrides the name derived from WithEnvPrefix; "-" disables the variable.
+	Env string
+	// EnvOnly makes the argument a setting that can only come from its
+	// environment variable, config files, or other value sources, such as
+	// credentials that must never appear in argv. Giving it on the command line
+	// is an error. Its value is typed and checked like any other.
+	EnvOnly bool
+	// Persistent makes the argument available to all subcommands, before or after
+	// the command name. Its value is included in the results of both this parser
+	// and the command. A command argument with the same name shadows it without
+	// error: the command and the commands nested in it only accept their own
+	// argument. A command argument with the same short name takes the short
+	// name, leaving the persistent argument its long name in that command.
+	Persistent bool
+	// ConflictsWith lists arguments that cannot be used together with this one
+	ConflictsWith []string
+	// AcceptOverArgs allows accepting more values than specified by NumArgs.
+	// It is equivalent to Greedy.
 	AcceptOverArgs bool
-	// Type specifies the data type of the argument value (String, Int, or Float)
+	// Greedy makes the argument consume all following values up to the next flag,
+	// regardless of NumArgs, as in --files a b c d e
+	Greedy bool
+	// Type specifies the data type of the argument value (String, Int, Float, or File)
 	Type ArgType
+	// Glob expands File values containing *, ?, or [ with filepath.Glob. The
+	// parsed value is always a []string of the matching paths, and a pattern
+	// without matches is an error unless GlobAllowEmpty is set.
+	Glob bool
+	// GlobAllowEmpty accepts Glob patterns that match no files
+	GlobAllowEmpty bool
+	// Readable requires File values to name an existing, readable file
+	Readable bool
+	// Writable requires File values to name a writable file, or a file that can
+	// be created in an existing directory, as for output paths
+	Writable bool
+	// Executable requires File values to name an existing executable file
+	Executable bool
+	// ExpandPath expands a leading ~ or ~user and $VAR references in File values
+	// and makes them absolute, for paths the shell did not expand
+	ExpandPath bool
+	// AllowFileRef replaces a value of the form @path with the contents of that file
+	// before type conversion. A leading @@ escapes a literal @.
+	AllowFileRef bool
+	// Default is the value stored in the parsed map when the argument is not given.
+	// It must match Type: string, int, or float64 (or a slice of them for multi-value
+	// arguments). An int default is accepted for Float arguments.
+	Default interface{}
+	// DefaultFunc computes the default lazily and is only called when the argument
+	// is not given. It takes precedence over Default, and its error is returned by Parse.
+	DefaultFunc func() (interface{}, error)
+	// Min is the smallest value accepted by Int and Float arguments (see Bound)
+	Min *float64
+	// Max is the largest value accepted by Int and Float arguments (see Bound)
+	Max *float64
+	// ClampToRange adjusts out-of-range values to the nearest bound with a warning
+	// instead of failing
+	ClampToRange bool
+	// Step requires numeric values to be a multiple of it (e.g. 4096), unless
+	// RoundToStep is set. Int arguments need a whole Step.
+	Step float64
+	// RoundToStep rounds numeric values to the nearest multiple of Step with a warning
+	RoundToStep bool
+	// MinLen is the minimum number of characters of a String value. A MinLen of 1
+	// or more also rejects empty values.
+	MinLen int
+	// MaxLen is the maximum number of characters of a String value (0 means no limit)
+	MaxLen int
+	// Choices restricts values to the listed ones. Values are checked before type
+	// conversion, so Choices also works for Int and Float arguments.
+	Choices []string
+	// IgnoreCase matches Choices and ChoiceAliases case-insensitively. The
+	// canonical spelling from Choices is stored in the parsed map.
+	IgnoreCase bool
+	// ChoiceAliases maps alternative spellings to an entry of Choices, such as
+	// "y" to "yes". The canonical choice is stored in the parsed map.
+	ChoiceAliases map[string]string
+	// Secret marks the value as sensitive, such as a password or token. Giving it
+	// on the command line in an interactive session writes a one-time warning
+	// suggesting safer alternatives (see WithSecretAdvice). Its value is
+	// redacted from errors, usage, debug output, --print-flags, and
+	// Result.Config, and never written by WriteConfig.
+	Secret bool
+	// Deprecated marks the argument as deprecated. It still works, but using it
+	// writes a warning with this message (e.g. "use --output instead").
+	Deprecated string
+	// Validate is called with the converted value (e.g. an int, or []int for
+	// multi-value arguments) to enforce domain rules. A returned error fails the
+	// parse and is reported with the flag name.
+	Validate func(value interface{}) error
+	// Transform normalizes each raw value before type conversion, e.g. to trim
+	// whitespace, lowercase, or make a path absolute
+	Transform func(string) (string, error)
+	// Merge combines the values of repeated occurrences of the argument, which are
+	// otherwise rejected as duplicates. It receives the value so far and the newly
+	// parsed value and returns the combined value (e.g. a union or a maximum).
+	Merge func(old, new interface{}) (interface{}, error)
+	// DeferredValidate is like Validate but is not run during parsing. It only runs
+	// when the application calls Result.Validate for this argument.
+	DeferredValidate func(value interface{}) error
+}
+
+// Bound returns a pointer to v, for use with the Min and Max fields of ArgDef
+//
+// Example:
+//
+//	{Name: "port", Type: uargs.Int, Min: uargs.Bound(1), Max: uargs.Bound(65535)}
+func Bound(v float64) *float64 {
+	return &v
 }
 
 // Parser represents a command-line argument parser
 type Parser struct {
-	defs        map[string]ArgDef      // Maps argument names to their definitions
-	shortToLong map[string]string      // Maps short names to their corresponding long names
-	parsed      map[string]interface{} // Stores parsed argument values
+	order           []string                                    // Names of the arguments in the order they were defined
+	defs            map[string]ArgDef                           // Maps argument names to their definitions
+	shortToLong     map[string]string                           // Maps short names to their corresponding long names
+	parsed          map[string]interface{}                      // Stores parsed argument values
+	stdin           io.Reader                                   // Reader returned for File arguments given as "-"
+	expandEnv       bool                                        // Expands $VAR references in values before conversion
+	accessible      bool                                        // Renders Usage in the screen-reader-friendly layout
+	sortUsage       bool                                        // Lists arguments alphabetically in Usage
+	defErr          error                                       // First error found in the argument definitions
+	done            bool                                        // Reports whether the last parse succeeded
+	logger          *slog.Logger                                // Receives debug events about the parse pipeline
+	warnings        io.Writer                                   // Receives non-fatal messages such as deprecations
+	groups          []argGroup                                  // Constraints over sets of arguments
+	result          *Result                                     // Values of the last successful parse
+	validators      []func(parsed map[string]interface{}) error // Cross-argument checks run after parsing
+	rules           map[string]exprNode                         // Compiled RequiredIf expressions
+	selfTest        io.Writer                                   // Receives the --self-test report, if enabled
+	printFlags      io.Writer                                   // Receives the --print-flags dump, if enabled
+	command         *Command                                    // Definition of the command this parser handles, nil for the root
+	commands        []*Parser                                   // Parsers of the subcommands, in the order they were added
+	parent          *Parser                                     // Parser of the enclosing command, nil for the root
+	epilogue        func(env HelpEnv) string                    // Generates the footer of help text
+	terminal        func() TerminalInfo                         // Detects the terminal for command requirements
+	quietSecrets    bool                                        // Disables the advice on secrets given on the command line
+	secretAdvised   bool                                        // Reports whether the secret advice was already written
+	inheritedFlags  map[string]bool                             // Persistent arguments inherited from the parent
+	suggestDistance int                                         // Largest edit distance of did-you-mean suggestions, 0 to disable
+	help            io.Writer                                   // Receives help text requested on the command line, if enabled
+	pluginPrefix    string                                      // Name prefix of plugin executables, "" to disable plugins
+	output          io.Writer                                   // Receives the prompts and messages of RunREPL
+	middleware      []Middleware                                // Wraps command execution, added with Use
+	exitCodes       *ExitCodes                                  // Exit codes set with WithExitCodes, nil for DefaultExitCodes
+	hideDeprecated  bool                                        // Leaves deprecated commands out of help text and suggestions
+	version         *VersionInfo                                // Shown by --version and the version command, if enabled
+	envPrefix       string                                      // Prefix of the environment variables derived from argument names
+	dotEnv          bool                                        // Reads environment variables from a dotenv file as well
+	dotEnvPath      string                                      // Path of the dotenv file, "" for DotEnvFile
+	configPath      string                                      // Config file read for arguments without a value, if any
+	configFlag      bool                                        // Lets --config choose the config file
+	configApp       string                                      // Application directory searched for a config file, if any
+	configLayers    []string                                    // Config files read first if they exist, lowest precedence first
+	profileFlag     bool                                        // Lets --profile choose a bundle of values
+	profiles        map[string]map[string]interface{}           // Profiles set with WithProfiles, by name
+	secrets         SecretProvider                              // Provider of Secret arguments set with WithSecrets
+	sources         []ValueSource                               // Sources added with WithSource
+	precedence      []Origin                                    // Order of the value sources set with WithPrecedence, nil for the default
+	only            map[string]bool                             // Arguments resolved by Bootstrap, nil for all
 }
 
-// NewParser creates a new Parser with the provided argument definitions
+// NewParser creates a new Parser with the provided argument definitions.
+// Invalid definitions, such as a Default that does not match the argument's Type,
+// are reported by Parse.
 //
 // Example:
 //
@@ -78,23 +297,116 @@ type Parser struct {
 //		{Name: "config", Short: "c", Usage: "Config file path", Type: github.com/utsav-56/uargs.String},
 //	}
 //	parser := github.com/utsav-56/uargs.NewParser(args)
-func NewParser(args []ArgDef) *Parser {
+func NewParser(args []ArgDef, opts ...Option) *Parser {
 	defs := make(map[string]ArgDef)
 	shortToLong := make(map[string]string)
-	for _, arg := range args {
+	var order []string
+	var defErr error
+	for i, arg := range args {
 		if arg.NumArgs == 0 {
 			arg.NumArgs = 1
 		}
+		err := checkDef(i, arg, defs, shortToLong)
+		if err == nil {
+			arg.Default, err = checkDefault(arg)
+		}
+		if err != nil {
+			if defErr == nil {
+				defErr = err
+			}
+			continue
+		}
 		defs[arg.Name] = arg
+		order = append(order, arg.Name)
 		if arg.Short != "" {
 			shortToLong[arg.Short] = arg.Name
 		}
 	}
-	return &Parser{defs, shortToLong, make(map[string]interface{})}
+	p := &Parser{
+		order:           order,
+		defs:            defs,
+		shortToLong:     shortToLong,
+		parsed:          make(map[string]interface{}),
+		stdin:           os.Stdin,
+		warnings:        os.Stderr,
+		output:          os.Stdout,
+		defErr:          defErr,
+		suggestDistance: defaultSuggestDistance,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(p)
+		}
+	}
+	if p.defErr == nil {
+		p.defErr = p.compileRules()
+	}
+	if p.defErr == nil {
+		p.defErr = p.checkGraph()
+	}
+	return p
+}
+
+// NewParserE is like NewParser but returns invalid definitions as an error
+// right away, instead of from Parse. This includes references to unknown
+// arguments and contradictory requirements, such as an argument required
+// together with one it conflicts with.
+//
+// Example:
+//
+//	parser, err := uargs.NewParserE(args)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func NewParserE(args []ArgDef, opts ...Option) (*Parser, error) {
+	p := NewParser(args, opts...)
+	return p, p.defErr
+}
+
+// checkDef reports misconfigurations of the i-th argument definition, given the
+// definitions accepted so far
+func checkDef(i int, arg ArgDef, defs map[string]ArgDef, shortToLong map[string]string) error {
+	switch {
+	case arg.Name == "":
+		return fmt.Errorf("argument definition #%d has an empty Name", i+1)
+	case strings.HasPrefix(arg.Name, "-") || strings.ContainsAny(arg.Name, " \t="):
+		return fmt.Errorf("argument name %q must not start with '-' or contain spaces or '='", arg.Name)
+	case strings.HasPrefix(arg.Short, "-") || strings.ContainsAny(arg.Short, " \t="):
+		return fmt.Errorf("short name %q of --%s must not start with '-' or contain spaces or '='", arg.Short, arg.Name)
+	case arg.NumArgs < 0:
+		return fmt.Errorf("--%s has negative NumArgs %d", arg.Name, arg.NumArgs)
+	case arg.MinArgs < 0 || arg.MaxArgs < -1 || (arg.MaxArgs > 0 && arg.MinArgs > arg.MaxArgs):
+		return fmt.Errorf("--%s has invalid arity MinArgs=%d MaxArgs=%d", arg.Name, arg.MinArgs, arg.MaxArgs)
+	case arg.Step < 0 || (arg.RoundToStep && arg.Step == 0):
+		return fmt.Errorf("--%s needs a positive Step, got %v", arg.Name, arg.Step)
+	case valueType(arg) == Int && arg.Step != math.Trunc(arg.Step):
+		return fmt.Errorf("--%s takes integers and needs a whole Step, got %v", arg.Name, arg.Step)
+	case arg.MinLen < 0 || arg.MaxLen < 0 || (arg.MaxLen > 0 && arg.MinLen > arg.MaxLen):
+		return fmt.Errorf("--%s has invalid length bounds MinLen=%d MaxLen=%d", arg.Name, arg.MinLen, arg.MaxLen)
+	case strings.ContainsAny(arg.Env, " \t="):
+		return fmt.Errorf("environment variable %q of --%s must not contain spaces or '='", arg.Env, arg.Name)
+	case arg.EnvOnly && arg.Short != "":
+		return fmt.Errorf("--%s is EnvOnly and cannot have a short name", arg.Name)
+	case len(strings.Fields(arg.Metavar)) > 1 && len(strings.Fields(arg.Metavar)) != maxArgs(arg):
+		return fmt.Errorf("--%s needs a single Metavar or one name per value, got %q", arg.Name, arg.Metavar)
+	}
+	for alias, choice := range arg.ChoiceAliases {
+		if !slices.Contains(arg.Choices, choice) {
+			return fmt.Errorf("alias %q of --%s maps to %q, which is not one of its Choices", alias, arg.Name, choice)
+		}
+	}
+	if _, ok := defs[arg.Name]; ok {
+		return fmt.Errorf("argument --%s is defined more than once", arg.Name)
+	}
+	if other, ok := shortToLong[arg.Short]; ok && arg.Short != "" {
+		return fmt.Errorf("short name -%s is used by both --%s and --%s", arg.Short, other, arg.Name)
+	}
+	return nil
 }
 
 // Parse parses command-line arguments and returns a map of argument names to their values.
 // It validates required arguments, checks for duplicates, and handles type conversions.
+// Arguments that were not given but have a Default are included with that value.
 //
 // Example:
 //
@@ -113,136 +425,568 @@ func NewParser(args []ArgDef) *Parser {
 //		countValue := count.(int)
 //	}
 func (p *Parser) Parse() (map[string]interface{}, error) {
-	argv := os.Args[1:]
+	var argv []string
+	if len(os.Args) > 1 {
+		argv = os.Args[1:]
+	}
+	return p.ParseArgs(argv)
+}
+
+// ParseArgs is like Parse but parses the given arguments instead of os.Args.
+// The program name must not be included. Each call starts from a clean state,
+// and unexpected panics are converted into errors.
+//
+// Example:
+//
+//	parsed, err := parser.ParseArgs([]string{"--input", "data.csv"})
+func (p *Parser) ParseArgs(argv []string) (map[string]interface{}, error) {
+	return p.parse(argv, nil)
+}
+
+// parse implements ParseArgs. Values in shared are treated as already given,
+// but unlike other given arguments each may be overridden once by argv.
+func (p *Parser) parse(argv []string, shared map[string]interface{}) (parsed map[string]interface{}, err error) {
+	if p == nil {
+		return nil, ErrNilParser
+	}
+	// The self-test reports definition errors too
+	if p.isSelfTest(argv) {
+		return nil, classify(usageErrorKind, p.runSelfTest())
+	}
+	if p.defErr != nil {
+		return nil, p.defErr
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			p.done = false
+			parsed, err = nil, fmt.Errorf("uargs: internal error while parsing: %v", r)
+		}
+		// Errors not marked as validation errors are usage errors
+		err = p.withCommandDocs(classify(usageErrorKind, err))
+	}()
+	p.done = false
+	p.clearCommands()
+	p.parsed = make(map[string]interface{})
 	used := make(map[string]bool)
+	inherited := make(map[string]bool)
+	for name, v := range shared {
+		p.parsed[name] = v
+		used[name] = true
+		inherited[name] = true
+	}
 
-	for i := 0; i < len(argv); i++ {
+	printing := false
+	var selected *Parser
+	var plugin *Plugin
+	var rest []string        // Arguments left for the selected subcommand or plugin
+	var passthrough []string // Arguments after "--"
+	for i := 0; i < len(argv) && selected == nil && plugin == nil; i++ {
 		arg := argv[i]
+		if arg == "--" {
+			if cmd := p.defaultCommand(); cmd != nil {
+				selected, rest = cmd, argv[i:]
+			} else {
+				passthrough = append([]string{}, argv[i+1:]...)
+			}
+			break
+		}
+		if p.isHelpFlag(arg) {
+			return nil, p.writeHelp(p.help, nil)
+		}
+		if p.isHelpCommand(arg) {
+			return nil, p.writeHelp(p.help, argv[i+1:])
+		}
+		if p.isVersionFlag(arg) || p.isVersionCommand(arg) {
+			return nil, p.writeVersion(argv[i+1:])
+		}
 		if strings.HasPrefix(arg, "--") {
 			name := arg[2:]
-			if def, ok := p.defs[name]; ok {
-				if used[name] {
-					return nil, fmt.Errorf("duplicate argument --%s", name)
+			if p.isPrintFlags(name) {
+				printing = true
+			} else if def, ok := p.defs[name]; ok {
+				if def.EnvOnly {
+					return nil, argError(def, fmt.Errorf("%s cannot be given on the command line%s", flagName(def), p.settingHint(def)))
 				}
-				used[name] = true
-				val, err := p.collectArgs(argv, &i, def)
-				if err != nil {
-					return nil, err
+				if err := p.consume(argv, &i, def, used, inherited); err != nil {
+					return nil, argError(def, err)
 				}
-				p.parsed[name] = val
+			} else if cmd := p.defaultCommand(); cmd != nil {
+				// Flags unknown here may belong to the default command
+				selected, rest = cmd, argv[i:]
 			} else {
-				return nil, fmt.Errorf("unknown argument --%s", name)
+				return nil, p.unknown(MatchFlag, arg, fmt.Sprintf("unknown argument --%s", name))
 			}
 		} else if strings.HasPrefix(arg, "-") {
 			short := arg[1:]
-			if len(short) > 1 {
-				return nil, fmt.Errorf("invalid short argument usage: -%s", short)
-			}
-			if name, ok := p.shortToLong[short]; ok {
-				if used[name] {
-					return nil, fmt.Errorf("duplicate argument -%s/--%s", short, name)
+			// Multi-character shorts such as -th are matched before rejecting the token
+			name, ok := p.shortToLong[short]
+			switch cmd := p.defaultCommand(); {
+			case ok:
+				if err := p.consume(argv, &i, p.defs[name], used, inherited); err != nil {
+					return nil, argError(p.defs[name], err)
 				}
-				used[name] = true
-				def := p.defs[name]
-				val, err := p.collectArgs(argv, &i, def)
-				if err != nil {
-					return nil, err
-				}
-				p.parsed[name] = val
-			} else {
-				return nil, fmt.Errorf("unknown short argument -%s", short)
+			case cmd != nil:
+				selected, rest = cmd, argv[i:]
+			case len(short) > 1:
+				return nil, p.unknown(MatchFlag, arg, fmt.Sprintf("invalid short argument usage: -%s", short))
+			default:
+				return nil, p.unknown(MatchFlag, arg, fmt.Sprintf("unknown short argument -%s", short))
 			}
+		} else if cmd := p.findCommand(arg); cmd != nil {
+			// The rest of the command line belongs to the subcommand
+			selected, rest = cmd, argv[i+1:]
+		} else if found := p.findPlugin(arg); found != nil {
+			// The rest of the command line belongs to the plugin
+			plugin, rest = found, argv[i+1:]
 		} else {
-			return nil, fmt.Errorf("unexpected token %s", arg)
+			return nil, p.unexpected(argv, i)
+		}
+	}
+	if printing {
+		// Required and group checks are skipped so wrappers can introspect without valid input
+		if err := p.fillMissing(); err != nil {
+			return nil, err
+		}
+		return nil, p.writeFlags()
+	}
+	if selected == nil && plugin == nil {
+		selected = p.defaultCommand()
+	}
+	if selected != nil {
+		// Persistent arguments given before the command are passed on to it, and
+		// those given after it count as given here too
+		shared := make(map[string]interface{})
+		for name := range selected.inheritedFlags {
+			if used[name] {
+				shared[name] = p.parsed[name]
+			}
+		}
+		if _, err := selected.parse(rest, shared); err != nil {
+			return nil, err
+		}
+		for name := range selected.inheritedFlags {
+			if selected.result.given[name] {
+				p.parsed[name] = selected.parsed[name]
+				used[name] = true
+			}
+		}
+		if err := selected.checkTerminal(); err != nil {
+			return nil, err
+		}
+		if msg := selected.command.Deprecated; msg != "" {
+			p.warnf("command %s is deprecated: %s", strings.Join(selected.path(), " "), msg)
 		}
 	}
 
+	for name := range used {
+		if inherited[name] {
+			continue
+		}
+		p.debug("source resolved", "flag", name, "source", "cli")
+		if msg := p.defs[name].Deprecated; msg != "" {
+			p.warnf("%s is deprecated: %s", p.flagNameOf(name), msg)
+		}
+		if p.defs[name].Secret {
+			p.adviseSecret(p.defs[name])
+		}
+	}
+
+	sources, cfg, err := p.valueSources()
+	if err != nil {
+		return nil, err
+	}
+	found, err := p.applySources(sources)
+	if err != nil {
+		return nil, err
+	}
+	// Values from the environment, config files, and other sources count as
+	// given for the constraints between arguments, unlike defaults
+	set := maps.Clone(used)
+	for name := range found {
+		set[name] = true
+	}
+	if err := p.checkConflicts(set); err != nil {
+		return nil, err
+	}
+	if err := p.checkGroups(set); err != nil {
+		return nil, err
+	}
+
 	for name, def := range p.defs {
 		if def.Required && p.parsed[name] == nil {
 			optional := false
 			for _, opt := range def.OptionalIfGiven {
-				if used[opt] {
+				if set[opt] {
 					optional = true
 					break
 				}
 			}
 			if !optional {
-				return nil, fmt.Errorf("missing required argument --%s", name)
+				if def.EnvOnly {
+					return nil, argError(def, fmt.Errorf("missing required setting %s%s", def.Name, p.settingHint(def)))
+				}
+				return nil, argError(def, fmt.Errorf("missing required argument %s", flagName(def)))
 			}
 		}
+		if !set[name] {
+			for _, trigger := range def.RequiredIfGiven {
+				if set[trigger] {
+					return nil, argError(def, fmt.Errorf("argument %s is required when %s is given", flagName(def), p.flagNameOf(trigger)))
+				}
+			}
+		}
+	}
+	if err := p.applyDefaults(); err != nil {
+		return nil, err
+	}
+	if err := p.checkRules(set); err != nil {
+		return nil, err
+	}
+	if err := p.runValidators(); err != nil {
+		return nil, err
 	}
 
+	p.done = true
+	p.result = newResult(p, p.parsed, used)
+	maps.Copy(p.result.sources, found)
+	p.result.command = selected
+	p.result.afterDash = passthrough
+	p.result.config = cfg
+	if plugin != nil {
+		p.result.plugin, p.result.pluginArgs = plugin, rest
+	}
 	return p.parsed, nil
 }
 
+// Get returns the parsed value of the named argument. It returns ErrNotParsed
+// if the last parse did not succeed, and false if the argument was not given
+// and has no default.
+//
+// Example:
+//
+//	if v, ok, err := parser.Get("count"); err == nil && ok {
+//		count := v.(int)
+//	}
+func (p *Parser) Get(name string) (interface{}, bool, error) {
+	if p == nil {
+		return nil, false, ErrNilParser
+	}
+	if !p.done {
+		return nil, false, ErrNotParsed
+	}
+	if _, ok := p.defs[name]; !ok {
+		return nil, false, fmt.Errorf("unknown argument --%s", name)
+	}
+	v, ok := p.parsed[name]
+	return v, ok, nil
+}
+
+// maxArgs returns the maximum number of values an argument consumes, or -1 when
+// there is no limit
+func maxArgs(def ArgDef) int {
+	if def.Greedy || def.AcceptOverArgs {
+		return -1
+	}
+	if def.MaxArgs != 0 {
+		return def.MaxArgs
+	}
+	return max(def.NumArgs, def.MinArgs)
+}
+
+// unexpected builds the error for a stray value at argv[i]. When the value
+// directly follows a flag whose values are complete, the error names that flag.
+// Parsers with subcommands or plugins report other stray values as unknown
+// commands.
+func (p *Parser) unexpected(argv []string, i int) error {
+	k := i - 1
+	for k >= 0 && p.isValue(argv[k]) {
+		k--
+	}
+	if k >= 0 {
+		if def, ok := p.lookup(argv[k]); ok && maxArgs(def) == i-1-k {
+			return fmt.Errorf("too many values for %s (expects at most %d): %v", flagName(def), maxArgs(def), redactValue(def, argv[i]))
+		}
+	}
+	if len(p.commands) > 0 || p.pluginPrefix != "" {
+		return p.unknown(MatchCommand, argv[i], fmt.Sprintf("unknown command %s", argv[i]))
+	}
+	return fmt.Errorf("unexpected token %s", argv[i])
+}
+
+// consume handles one occurrence of the flag at argv[*i], collecting its values
+// and storing them in the parsed map. A repeated flag is an error unless its
+// definition has a Merge function to combine the occurrences. Inherited values
+// are replaced by their first occurrence.
+func (p *Parser) consume(argv []string, i *int, def ArgDef, used, inherited map[string]bool) error {
+	name := def.Name
+	if inherited[name] {
+		delete(inherited, name)
+		delete(used, name)
+	}
+	if used[name] && def.Merge == nil {
+		return fmt.Errorf("duplicate argument %s", flagName(def))
+	}
+	p.debug("flag matched", "token", argv[*i], "flag", name)
+	val, err := p.collectArgs(argv, i, def)
+	if err != nil {
+		return err
+	}
+	if used[name] {
+		merged, err := def.Merge(p.parsed[name], val)
+		if err != nil {
+			return fmt.Errorf("cannot merge repeated %s: %v", flagName(def), redactError(def, err, p.parsed[name], val))
+		}
+		val = merged
+	}
+	used[name] = true
+	p.parsed[name] = val
+	return nil
+}
+
 // collectArgs collects argument values from the command-line arguments.
 // It handles multi-value arguments and type conversion based on the argument definition.
 // This is an internal function used by the Parse method.
 func (p *Parser) collectArgs(argv []string, i *int, def ArgDef) (interface{}, error) {
 	args := []string{}
-	for j := 0; j < def.NumArgs && *i+1 < len(argv); j++ {
+	limit := maxArgs(def)
+	for j := 0; (limit < 0 || j < limit) && *i+1 < len(argv); j++ {
 		next := argv[*i+1]
-		if strings.HasPrefix(next, "-") {
+		if !p.isValue(next) {
 			break
 		}
 		*i++
+		p.debug("token consumed", "index", *i, "token", redactValue(def, next), "flag", def.Name)
 		args = append(args, next)
 	}
-	if !def.AcceptOverArgs && len(args) > def.NumArgs {
-		return nil, fmt.Errorf("too many arguments for --%s", def.Name)
+	if len(args) < def.MinArgs {
+		return nil, fmt.Errorf("%s expects at least %d values, got %d%s", flagName(def), def.MinArgs, len(args), exampleHint(def))
 	}
+	val, err := p.convert(def, args)
+	return val, classify(validationErrorKind, err)
+}
 
+// convert turns the raw values of an argument into its typed value. The values
+// of Secret arguments are redacted from its errors.
+func (p *Parser) convert(def ArgDef, args []string) (interface{}, error) {
+	raw := slices.Clone(args)
+	val, err := p.convertArgs(def, args)
+	// args holds the values as far as they were expanded and transformed
+	return val, redactError(def, err, raw, args)
+}
+
+// convertArgs turns the raw values of an argument into its typed value. Values
+// are expanded, resolved, and transformed first, then converted according to
+// the argument's Type, checked against its constraints, and finally passed to
+// its Validate callback.
+func (p *Parser) convertArgs(def ArgDef, args []string) (interface{}, error) {
+	for k, s := range args {
+		if p.expandEnv {
+			s = expandEnv(s)
+		}
+		var err error
+		if def.AllowFileRef {
+			if s, err = readFileRef(s); err != nil {
+				return nil, fmt.Errorf("%s: %v", flagName(def), err)
+			}
+		}
+		if def.ExpandPath && valueType(def) == File && s != StdinPath {
+			if s, err = expandPath(s); err != nil {
+				return nil, fmt.Errorf("%s: %v", flagName(def), err)
+			}
+		}
+		if def.Transform != nil {
+			if s, err = def.Transform(s); err != nil {
+				return nil, fmt.Errorf("invalid value for %s: %v", flagName(def), err)
+			}
+		}
+		if len(def.Choices) > 0 {
+			if s, err = checkChoice(def, s); err != nil {
+				return nil, err
+			}
+		}
+		args[k] = s
+	}
+	if def.Glob && valueType(def) == File {
+		var err error
+		if args, err = expandGlobs(def, args); err != nil {
+			return nil, err
+		}
+	}
+
+	var val interface{}
 	switch def.Type {
 	case Int:
 		ints := []int{}
 		for _, s := range args {
 			n, err := strconv.Atoi(s)
 			if err != nil {
-				return nil, fmt.Errorf("--%s expects int, got '%s'", def.Name, s)
+				return nil, fmt.Errorf("%s expects int, got '%s'%s", flagName(def), s, exampleHint(def))
+			}
+			if n, err = p.checkInt(def, n); err != nil {
+				return nil, err
 			}
 			ints = append(ints, n)
 		}
-		if len(ints) == 1 {
-			return ints[0], nil
-		}
-		return ints, nil
+		val = collapse(ints)
 	case Float:
 		floats := []float64{}
 		for _, s := range args {
 			f, err := strconv.ParseFloat(s, 64)
 			if err != nil {
-				return nil, fmt.Errorf("--%s expects float, got '%s'", def.Name, s)
+				return nil, fmt.Errorf("%s expects float, got '%s'%s", flagName(def), s, exampleHint(def))
+			}
+			if f, err = p.checkNumber(def, f); err != nil {
+				return nil, err
 			}
 			floats = append(floats, f)
 		}
-		if len(floats) == 1 {
-			return floats[0], nil
+		val = collapse(floats)
+	case File:
+		if len(args) == 1 && args[0] == StdinPath {
+			val = p.stdin
+		} else {
+			for _, path := range args {
+				if err := checkFile(def, path); err != nil {
+					return nil, err
+				}
+			}
+			if def.Glob {
+				val = args
+			} else {
+				val = collapse(args)
+			}
 		}
-		return floats, nil
 	default:
-		if len(args) == 1 {
-			return args[0], nil
+		for _, s := range args {
+			if err := p.checkString(def, s); err != nil {
+				return nil, err
+			}
 		}
-		return args, nil
+		val = collapse(args)
 	}
+
+	if def.Validate != nil {
+		p.debug("validator run", "flag", def.Name, "validator", "custom")
+		if err := def.Validate(val); err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %v", flagName(def), err)
+		}
+	}
+	return val, nil
 }
 
-// Usage generates a formatted help text showing all defined arguments with their
-// names, short options, and usage descriptions. This is helpful for displaying
-// to users when invalid arguments are provided or when help is requested.
-//
-// Example:
-//
-//	if err != nil {
-//		fmt.Println(err)
-//		fmt.Println(parser.Usage())
-//		os.Exit(1)
-//	}
-func (p *Parser) Usage() string {
-	var b strings.Builder
-	b.WriteString("Usage:\n")
-	for _, def := range p.defs {
-		b.WriteString(fmt.Sprintf("  --%-10s -%s	%s\n", def.Name, def.Short, def.Usage))
+// collapse returns the only element of values, or values itself when it holds
+// zero or several elements
+func collapse[T any](values []T) interface{} {
+	if len(values) == 1 {
+		return values[0]
+	}
+	return values
+}
+
+// debug emits a debug event to the configured logger, if any
+func (p *Parser) debug(msg string, args ...any) {
+	if p.logger != nil {
+		p.logger.Debug(msg, args...)
+	}
+}
+
+// warnf writes a non-fatal message to the warnings writer, if any
+func (p *Parser) warnf(format string, args ...any) {
+	if p.warnings != nil {
+		fmt.Fprintf(p.warnings, "warning: "+format+"\n", args...)
+	}
+}
+
+// isValue reports whether a token is an argument value rather than a flag. Besides
+// tokens without a leading dash, this includes "-" (stdin) and negative numbers
+// such as -1 or -0.5 that do not collide with a short name. Words such as -inf
+// are not numbers here.
+func (p *Parser) isValue(token string) bool {
+	if !strings.HasPrefix(token, "-") || token == StdinPath {
+		return true
+	}
+	if !strings.ContainsRune("0123456789.", rune(token[1])) {
+		return false
+	}
+	if _, err := strconv.ParseFloat(token, 64); err == nil {
+		_, isShort := p.shortToLong[token[1:]]
+		return !isShort
+	}
+	return false
+}
+
+// expandEnv replaces $VAR and ${VAR} references in s with the values of the
+// corresponding environment variables. A literal dollar sign is written as $$.
+func expandEnv(s string) string {
+	return os.Expand(s, func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+		return os.Getenv(name)
+	})
+}
+
+// expandPath expands a leading ~ or ~user and environment variable references
+// in path, and returns it as an absolute path
+func expandPath(path string) (string, error) {
+	path = expandEnv(path)
+	if strings.HasPrefix(path, "~") {
+		name, rest := path[1:], ""
+		if i := strings.IndexAny(name, "/"+string(filepath.Separator)); i >= 0 {
+			name, rest = name[:i], name[i+1:]
+		}
+		var home string
+		if name == "" {
+			dir, err := os.UserHomeDir()
+			if err != nil {
+				return "", err
+			}
+			home = dir
+		} else {
+			u, err := user.Lookup(name)
+			if err != nil {
+				return "", fmt.Errorf("cannot expand ~%s: %v", name, err)
+			}
+			home = u.HomeDir
+		}
+		path = filepath.Join(home, rest)
+	}
+	return filepath.Abs(path)
+}
+
+// expandGlobs replaces the glob patterns among args with the paths they match
+func expandGlobs(def ArgDef, args []string) ([]string, error) {
+	paths := []string{}
+	for _, s := range args {
+		if !strings.ContainsAny(s, "*?[") {
+			paths = append(paths, s)
+			continue
+		}
+		matches, err := filepath.Glob(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid pattern %s: %v", flagName(def), s, err)
+		}
+		if len(matches) == 0 && !def.GlobAllowEmpty {
+			return nil, fmt.Errorf("%s: no files match %s", flagName(def), s)
+		}
+		paths = append(paths, matches...)
+	}
+	return paths, nil
+}
+
+// readFileRef resolves a value of the form @path to the contents of the file,
+// without its trailing newline. Values starting with @@ yield a literal @ and
+// any other value is returned unchanged.
+func readFileRef(s string) (string, error) {
+	if !strings.HasPrefix(s, "@") {
+		return s, nil
+	}
+	if strings.HasPrefix(s, "@@") {
+		return s[1:], nil
+	}
+	data, err := os.ReadFile(s[1:])
+	if err != nil {
+		return "", fmt.Errorf("cannot read %s: %v", s, err)
 	}
-	return b.String()
+	return strings.TrimRight(string(data), "\r\n"), nil
 }
@@ -1,7 +1,12 @@
 package uargs_test
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/utsav-56/uargs"
@@ -119,6 +124,27 @@ func Example_multiValue() {
 	println(tags[0])
 }
 
+// Example_fileStdin demonstrates reading a File argument given as "-" from stdin
+func Example_fileStdin() {
+	args := []uargs.ArgDef{
+		{Name: "input", Short: "i", Usage: "Input file", Type: uargs.File},
+	}
+
+	// "-" stands for the reader set with WithStdin, os.Stdin by default
+	parser := uargs.NewParser(args, uargs.WithStdin(strings.NewReader("hello from stdin")))
+	parsed, err := parser.ParseArgs([]string{"--input", "-"})
+	if err != nil {
+		panic(err)
+	}
+
+	data, err := io.ReadAll(parsed["input"].(io.Reader))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(data))
+	// Output: hello from stdin
+}
+
 // TestParser tests the core functionality of the Parser
 func TestParser(t *testing.T) {
 	// Save original args and restore after test
@@ -187,3 +213,446 @@ func TestParser(t *testing.T) {
 		t.Error("Expected error due to invalid number format, got nil")
 	}
 }
+
+// TestFileStdin tests that "-" is accepted as a File value and resolved to stdin
+func TestFileStdin(t *testing.T) {
+	originalArgs := os.Args
+	defer func() { os.Args = originalArgs }()
+
+	os.Args = []string{"app", "--input", "-", "--output", "out.txt"}
+
+	args := []uargs.ArgDef{
+		{Name: "input", Short: "i", Usage: "Input file", Type: uargs.File},
+		{Name: "output", Short: "o", Usage: "Output file", Type: uargs.File},
+	}
+
+	stdin := strings.NewReader("data")
+	parser := uargs.NewParser(args, uargs.WithStdin(stdin))
+	parsed, err := parser.Parse()
+	if err != nil {
+		t.Fatalf("Failed to parse valid arguments: %v", err)
+	}
+
+	if r, ok := parsed["input"].(io.Reader); !ok || r != stdin {
+		t.Errorf("Expected input to be the stdin reader, got %v", parsed["input"])
+	}
+	if parsed["output"] != "out.txt" {
+		t.Errorf("Expected output='out.txt', got '%v'", parsed["output"])
+	}
+}
+
+// TestMultiCharShort tests that multi-character short names are matched
+func TestMultiCharShort(t *testing.T) {
+	originalArgs := os.Args
+	defer func() { os.Args = originalArgs }()
+
+	os.Args = []string{"app", "-th", "0.5", "-t", "x"}
+
+	args := []uargs.ArgDef{
+		{Name: "threshold", Short: "th", Usage: "Threshold value", Type: uargs.Float},
+		{Name: "tag", Short: "t", Usage: "Tag", Type: uargs.String},
+	}
+
+	parsed, err := uargs.NewParser(args).Parse()
+	if err != nil {
+		t.Fatalf("Failed to parse valid arguments: %v", err)
+	}
+	if parsed["threshold"] != 0.5 {
+		t.Errorf("Expected threshold=0.5, got %v", parsed["threshold"])
+	}
+	if parsed["tag"] != "x" {
+		t.Errorf("Expected tag='x', got '%v'", parsed["tag"])
+	}
+
+	// Unknown multi-character shorts are still rejected
+	os.Args = []string{"app", "-tx"}
+	if _, err := uargs.NewParser(args).Parse(); err == nil {
+		t.Error("Expected error for unknown short argument -tx, got nil")
+	}
+}
+
+// TestAllowFileRef tests that @path values are replaced with file contents
+func TestAllowFileRef(t *testing.T) {
+	originalArgs := os.Args
+	defer func() { os.Args = originalArgs }()
+
+	path := filepath.Join(t.TempDir(), "server.pem")
+	if err := os.WriteFile(path, []byte("-----BEGIN CERT-----\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	args := []uargs.ArgDef{
+		{Name: "cert", Usage: "Certificate", AllowFileRef: true, Type: uargs.String},
+		{Name: "handle", Usage: "Handle", AllowFileRef: true, Type: uargs.String},
+		{Name: "mention", Usage: "Mention", Type: uargs.String},
+	}
+
+	os.Args = []string{"app", "--cert", "@" + path, "--handle", "@@user", "--mention", "@user"}
+	parsed, err := uargs.NewParser(args).Parse()
+	if err != nil {
+		t.Fatalf("Failed to parse valid arguments: %v", err)
+	}
+	if parsed["cert"] != "-----BEGIN CERT-----" {
+		t.Errorf("Expected file contents for cert, got '%v'", parsed["cert"])
+	}
+	if parsed["handle"] != "@user" {
+		t.Errorf("Expected handle='@user', got '%v'", parsed["handle"])
+	}
+	if parsed["mention"] != "@user" {
+		t.Errorf("Expected mention='@user', got '%v'", parsed["mention"])
+	}
+
+	os.Args = []string{"app", "--cert", "@" + path + ".missing"}
+	if _, err := uargs.NewParser(args).Parse(); err == nil {
+		t.Error("Expected error for missing file reference, got nil")
+	}
+}
+
+// TestMisconfiguration tests that invalid definitions and misuse return errors
+func TestMisconfiguration(t *testing.T) {
+	tests := []struct {
+		name string
+		args []uargs.ArgDef
+	}{
+		{"empty name", []uargs.ArgDef{{Name: "", Type: uargs.String}}},
+		{"dashed name", []uargs.ArgDef{{Name: "--input", Type: uargs.String}}},
+		{"negative NumArgs", []uargs.ArgDef{{Name: "tags", NumArgs: -1, Type: uargs.String}}},
+		{"duplicate name", []uargs.ArgDef{{Name: "a"}, {Name: "a"}}},
+		{"duplicate short", []uargs.ArgDef{{Name: "a", Short: "x"}, {Name: "b", Short: "x"}}},
+	}
+	for _, tt := range tests {
+		if _, err := uargs.NewParser(tt.args).ParseArgs(nil); err == nil {
+			t.Errorf("%s: expected definition error, got nil", tt.name)
+		}
+	}
+
+	// Value access before Parse
+	parser := uargs.NewParser([]uargs.ArgDef{{Name: "a"}})
+	if _, _, err := parser.Get("a"); !errors.Is(err, uargs.ErrNotParsed) {
+		t.Errorf("Expected ErrNotParsed, got %v", err)
+	}
+
+	// Methods on a nil parser
+	var nilParser *uargs.Parser
+	if _, err := nilParser.Parse(); !errors.Is(err, uargs.ErrNilParser) {
+		t.Errorf("Expected ErrNilParser, got %v", err)
+	}
+	if nilParser.Usage() != "" {
+		t.Error("Expected empty usage from nil parser")
+	}
+
+	// A zero-value parser parses without panicking
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("Expected no panic from a zero-value parser, got %v", r)
+		}
+	}()
+	if _, err := new(uargs.Parser).ParseArgs([]string{"--x"}); err == nil || err.Error() != "unknown argument --x" {
+		t.Errorf("Expected an unknown argument error from a zero-value parser, got %v", err)
+	}
+}
+
+// FuzzParseArgs asserts that parsing arbitrary input never panics
+func FuzzParseArgs(f *testing.F) {
+	f.Add("--input a.txt --count 3")
+	f.Add("-i - -c x --coords 1 2 3")
+	f.Add("-- - --count= -th @@x")
+	f.Add("\x00--tags a b c d")
+
+	args := []uargs.ArgDef{
+		{Name: "input", Short: "i", Usage: "Input file", Required: true, Type: uargs.File},
+		{Name: "count", Short: "c", Usage: "Count value", Default: 1, Type: uargs.Int},
+		{Name: "coords", Usage: "Coordinates", NumArgs: 2, Type: uargs.Float},
+		{Name: "tags", Short: "t", Usage: "Tags", NumArgs: 3, OptionalIfGiven: []string{"input"}, Type: uargs.String},
+		{Name: "threshold", Short: "th", Usage: "Threshold", AllowFileRef: true, Type: uargs.Float},
+	}
+
+	f.Fuzz(func(t *testing.T, line string) {
+		parser := uargs.NewParser(args)
+		if _, err := parser.ParseArgs(strings.Fields(line)); err != nil && strings.Contains(err.Error(), "internal error") {
+			t.Fatalf("Parse panicked on %q: %v", line, err)
+		}
+		parser.Usage()
+		parser.Hints(line, len(line)/2)
+		parser.Search(line)
+	})
+}
+
+// TestValidate tests per-argument Validate callbacks
+func TestValidate(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "config", Usage: "Config file", Type: uargs.String, Validate: func(v interface{}) error {
+			if !strings.HasSuffix(v.(string), ".json") {
+				return errors.New("must be a .json file")
+			}
+			return nil
+		}},
+		{Name: "primes", Usage: "Prime numbers", NumArgs: 2, Type: uargs.Int, Validate: func(v interface{}) error {
+			for _, n := range v.([]int) {
+				if n < 2 {
+					return fmt.Errorf("%d is not prime", n)
+				}
+			}
+			return nil
+		}},
+	}
+	parser := uargs.NewParser(args)
+
+	if _, err := parser.ParseArgs([]string{"--config", "app.json", "--primes", "3", "5"}); err != nil {
+		t.Fatalf("Failed to parse valid arguments: %v", err)
+	}
+
+	_, err := parser.ParseArgs([]string{"--config", "app.yaml"})
+	if err == nil || err.Error() != "invalid value for --config: must be a .json file" {
+		t.Errorf("Expected validation error for --config, got %v", err)
+	}
+
+	_, err = parser.ParseArgs([]string{"--primes", "3", "1"})
+	if err == nil || err.Error() != "invalid value for --primes: 1 is not prime" {
+		t.Errorf("Expected validation error for --primes, got %v", err)
+	}
+}
+
+// TestTransform tests that raw values are normalized before conversion
+func TestTransform(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "format", Usage: "Output format", Type: uargs.String, Transform: func(s string) (string, error) {
+			return strings.ToLower(strings.TrimSpace(s)), nil
+		}},
+		{Name: "size", Usage: "Size", Type: uargs.Int, Transform: func(s string) (string, error) {
+			if s == "" {
+				return "", errors.New("size is empty")
+			}
+			return strings.ReplaceAll(s, "_", ""), nil
+		}},
+	}
+	parser := uargs.NewParser(args)
+
+	parsed, err := parser.ParseArgs([]string{"--format", " JSON ", "--size", "1_000"})
+	if err != nil {
+		t.Fatalf("Failed to parse valid arguments: %v", err)
+	}
+	if parsed["format"] != "json" {
+		t.Errorf("Expected format='json', got '%v'", parsed["format"])
+	}
+	if parsed["size"] != 1000 {
+		t.Errorf("Expected size=1000, got %v", parsed["size"])
+	}
+
+	if _, err := parser.ParseArgs([]string{"--size", ""}); err == nil {
+		t.Error("Expected error from Transform, got nil")
+	}
+}
+
+// TestRequiredIfGiven tests arguments that become mandatory when another is given
+func TestRequiredIfGiven(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "user", Short: "u", Usage: "User name", Type: uargs.String},
+		{Name: "password", Short: "p", Usage: "Password", RequiredIfGiven: []string{"user"}, Type: uargs.String},
+	}
+	parser := uargs.NewParser(args)
+
+	if _, err := parser.ParseArgs(nil); err != nil {
+		t.Errorf("Expected --password to be optional without --user, got %v", err)
+	}
+	if _, err := parser.ParseArgs([]string{"-u", "admin", "-p", "secret"}); err != nil {
+		t.Errorf("Failed to parse valid arguments: %v", err)
+	}
+	_, err := parser.ParseArgs([]string{"--user", "admin"})
+	if err == nil || err.Error() != "argument -p, --password is required when -u, --user is given" {
+		t.Errorf("Expected missing --password error, got %v", err)
+	}
+}
+
+// TestMerge tests combining repeated occurrences of an argument
+func TestMerge(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "level", Short: "l", Usage: "Level", Type: uargs.Int, Merge: func(old, new interface{}) (interface{}, error) {
+			return max(old.(int), new.(int)), nil
+		}},
+		{Name: "tag", Short: "t", Usage: "Tag", Type: uargs.String, Merge: func(old, new interface{}) (interface{}, error) {
+			if old == new {
+				return nil, fmt.Errorf("tag %v given twice", new)
+			}
+			return fmt.Sprintf("%v,%v", old, new), nil
+		}},
+		{Name: "name", Short: "n", Usage: "Name", Type: uargs.String},
+	}
+	parser := uargs.NewParser(args)
+
+	parsed, err := parser.ParseArgs([]string{"-l", "2", "--level", "5", "-l", "3", "-t", "a", "-t", "b"})
+	if err != nil {
+		t.Fatalf("Failed to parse valid arguments: %v", err)
+	}
+	if parsed["level"] != 5 {
+		t.Errorf("Expected level=5, got %v", parsed["level"])
+	}
+	if parsed["tag"] != "a,b" {
+		t.Errorf("Expected tag='a,b', got %v", parsed["tag"])
+	}
+
+	if _, err := parser.ParseArgs([]string{"-t", "a", "-t", "a"}); err == nil {
+		t.Error("Expected merge error, got nil")
+	}
+	if _, err := parser.ParseArgs([]string{"-n", "a", "-n", "b"}); err == nil {
+		t.Error("Expected duplicate error without Merge, got nil")
+	}
+}
+
+// TestVariableArity tests MinArgs and MaxArgs
+func TestVariableArity(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "coords", Short: "c", Usage: "Coordinates", MinArgs: 2, MaxArgs: 2, Type: uargs.Float},
+		{Name: "tags", Short: "t", Usage: "Tags", MinArgs: 1, MaxArgs: 3, Type: uargs.String},
+		{Name: "files", Short: "f", Usage: "Files", MinArgs: 2, MaxArgs: -1, Type: uargs.String},
+	}
+	parser := uargs.NewParser(args)
+
+	parsed, err := parser.ParseArgs([]string{"-c", "1", "2", "-t", "a", "-f", "x", "y", "z", "w"})
+	if err != nil {
+		t.Fatalf("Failed to parse valid arguments: %v", err)
+	}
+	if len(parsed["coords"].([]float64)) != 2 {
+		t.Errorf("Expected 2 coords, got %v", parsed["coords"])
+	}
+	if parsed["tags"] != "a" {
+		t.Errorf("Expected tags='a', got %v", parsed["tags"])
+	}
+	if len(parsed["files"].([]string)) != 4 {
+		t.Errorf("Expected 4 files, got %v", parsed["files"])
+	}
+
+	_, err = parser.ParseArgs([]string{"-c", "1"})
+	if err == nil || err.Error() != "-c, --coords expects at least 2 values, got 1" {
+		t.Errorf("Expected too few values error, got %v", err)
+	}
+	if _, err := parser.ParseArgs([]string{"-t", "a", "b", "c", "d"}); err == nil {
+		t.Error("Expected error for a fourth tag, got nil")
+	}
+
+	bad := []uargs.ArgDef{{Name: "x", MinArgs: 3, MaxArgs: 2}}
+	if _, err := uargs.NewParser(bad).ParseArgs(nil); err == nil {
+		t.Error("Expected error for MinArgs greater than MaxArgs, got nil")
+	}
+}
+
+// TestGreedy tests arguments that consume all following values
+func TestGreedy(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "files", Short: "f", Usage: "Files", Greedy: true, Type: uargs.String},
+		{Name: "extra", Short: "e", Usage: "Extra values", AcceptOverArgs: true, Type: uargs.Int},
+		{Name: "tags", Short: "t", Usage: "Tags", NumArgs: 2, Type: uargs.String},
+	}
+	parser := uargs.NewParser(args)
+
+	parsed, err := parser.ParseArgs([]string{"--files", "a", "b", "c", "d", "e", "-e", "1", "2", "3"})
+	if err != nil {
+		t.Fatalf("Failed to parse valid arguments: %v", err)
+	}
+	if files := parsed["files"].([]string); len(files) != 5 {
+		t.Errorf("Expected 5 files, got %v", files)
+	}
+	if extra := parsed["extra"].([]int); len(extra) != 3 {
+		t.Errorf("Expected AcceptOverArgs to take 3 values, got %v", extra)
+	}
+
+	_, err = parser.ParseArgs([]string{"-t", "a", "b", "c"})
+	if want := "too many values for -t, --tags (expects at most 2): c"; err == nil || err.Error() != want {
+		t.Errorf("Expected error %q, got %v", want, err)
+	}
+}
+
+// TestExpandPath tests tilde and environment expansion of File arguments
+func TestExpandPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("DATA_DIR", "/srv/data")
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := []uargs.ArgDef{
+		{Name: "input", Usage: "Input", Type: uargs.File, ExpandPath: true},
+		{Name: "raw", Usage: "Raw path", Type: uargs.File},
+	}
+	parser := uargs.NewParser(args)
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"~/data.csv", filepath.Join(home, "data.csv")},
+		{"~", home},
+		{"$DATA_DIR/a.csv", "/srv/data/a.csv"},
+		{"${HOME}/b.csv", filepath.Join(home, "b.csv")},
+		{"rel/c.csv", filepath.Join(cwd, "rel", "c.csv")},
+	}
+	for _, tt := range tests {
+		parsed, err := parser.ParseArgs([]string{"--input", tt.value})
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.value, err)
+			continue
+		}
+		if parsed["input"] != tt.want {
+			t.Errorf("%s: expected %s, got %v", tt.value, tt.want, parsed["input"])
+		}
+	}
+
+	parsed, err := parser.ParseArgs([]string{"--raw", "~/data.csv"})
+	if err != nil || parsed["raw"] != "~/data.csv" {
+		t.Errorf("Expected paths to be left alone without ExpandPath, got %v, %v", parsed["raw"], err)
+	}
+	if _, err := parser.ParseArgs([]string{"--input", "~no-such-user-xyz/a"}); err == nil {
+		t.Errorf("Expected error for an unknown user")
+	}
+}
+
+// TestGlob tests glob expansion of File arguments
+func TestGlob(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.log", "b.log", "c.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	args := []uargs.ArgDef{
+		{Name: "logs", Usage: "Log files", Type: uargs.File, Glob: true, Greedy: true},
+		{Name: "extra", Usage: "Extra files", Type: uargs.File, Glob: true, GlobAllowEmpty: true},
+	}
+	parser := uargs.NewParser(args)
+
+	// Test case 1: Patterns expand, literal paths are kept
+	parsed, err := parser.ParseArgs([]string{"--logs", filepath.Join(dir, "*.log"), "plain.txt"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []string{filepath.Join(dir, "a.log"), filepath.Join(dir, "b.log"), "plain.txt"}
+	if fmt.Sprint(parsed["logs"]) != fmt.Sprint(want) {
+		t.Errorf("Expected %v, got %v", want, parsed["logs"])
+	}
+
+	// Test case 2: A single match is still a slice
+	parsed, err = parser.ParseArgs([]string{"--logs", filepath.Join(dir, "c.*")})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got, ok := parsed["logs"].([]string); !ok || len(got) != 1 {
+		t.Errorf("Expected a one-element []string, got %#v", parsed["logs"])
+	}
+
+	// Test case 3: No matches
+	pattern := filepath.Join(dir, "*.csv")
+	_, err = parser.ParseArgs([]string{"--logs", pattern})
+	if err == nil || err.Error() != "--logs: no files match "+pattern {
+		t.Errorf("Expected no-match error, got %v", err)
+	}
+	parsed, err = parser.ParseArgs([]string{"--extra", pattern})
+	if err != nil {
+		t.Fatalf("Expected empty matches to be allowed, got %v", err)
+	}
+	if got, ok := parsed["extra"].([]string); !ok || len(got) != 0 {
+		t.Errorf("Expected an empty []string, got %#v", parsed["extra"])
+	}
+}
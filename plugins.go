@@ -0,0 +1,86 @@
+package uargs
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Plugin is an external executable that provides a command, found on PATH by
+// its name "<prefix>-<command>" as set with WithPlugins
+type Plugin struct {
+	// Name is the command the plugin provides, such as "hello" for mytool-hello
+	Name string
+	// Path is the location of the executable
+	Path string
+}
+
+// findPlugin returns the plugin providing the command name, or nil if plugins
+// are disabled or there is none on PATH
+func (p *Parser) findPlugin(name string) *Plugin {
+	if p.pluginPrefix == "" || name == "" || strings.ContainsAny(name, `/\`) {
+		return nil
+	}
+	path, err := exec.LookPath(p.pluginPrefix + "-" + name)
+	if err != nil {
+		return nil
+	}
+	return &Plugin{Name: name, Path: path}
+}
+
+// Plugins returns the plugins found on PATH, sorted by name, so help output
+// can list them next to the built-in commands. When several directories hold
+// the same plugin, the first one on PATH wins, as when it is run. It returns
+// nil unless plugins were enabled with WithPlugins.
+//
+// Example:
+//
+//	for _, plugin := range parser.Plugins() {
+//		fmt.Printf("  %s\t(plugin at %s)\n", plugin.Name, plugin.Path)
+//	}
+func (p *Parser) Plugins() []Plugin {
+	if p == nil || p.pluginPrefix == "" {
+		return nil
+	}
+	prefix := p.pluginPrefix + "-"
+	seen := make(map[string]bool)
+	var plugins []Plugin
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			file := entry.Name()
+			name := strings.TrimPrefix(file, prefix)
+			if runtime.GOOS == "windows" {
+				name = strings.TrimSuffix(name, filepath.Ext(name))
+			}
+			if !strings.HasPrefix(file, prefix) || name == "" || seen[name] || p.findCommand(name) != nil {
+				continue
+			}
+			// Only executables that LookPath would run count, which skips
+			// directories and files without execute permission
+			path, err := exec.LookPath(filepath.Join(dir, file))
+			if err != nil {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, Plugin{Name: name, Path: path})
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
+
+// runPlugin runs plugin with args, connected to the standard streams. A
+// non-zero exit status is returned as an *exec.ExitError.
+func (p *Parser) runPlugin(ctx context.Context, plugin *Plugin, args []string) error {
+	cmd := exec.CommandContext(ctx, plugin.Path, args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = p.stdin, os.Stdout, os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,76 @@
+package uargs_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestPlugins tests resolving unknown commands to executables on PATH
+func TestPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Plugin scripts need a POSIX shell")
+	}
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out.txt")
+	script := "#!/bin/sh\necho \"$@\" > " + out + "\nexit 3\n"
+	if err := os.WriteFile(filepath.Join(dir, "mytool-hello"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "mytool-notes.txt"), []byte("not a plugin"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "mytool-status"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+
+	parser := uargs.NewParser([]uargs.ArgDef{
+		{Name: "verbose", Usage: "Verbosity", Type: uargs.Int},
+	}, uargs.WithPlugins("mytool"))
+	parser.AddCommand(uargs.Command{Name: "status", Usage: "Show the status"})
+
+	// Test case 1: Parse selects the plugin and keeps the arguments after it
+	if _, err := parser.ParseArgs([]string{"--verbose", "1", "hello", "--loud", "world"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	plugin, args := parser.Result().Plugin()
+	if plugin == nil || plugin.Name != "hello" || plugin.Path != filepath.Join(dir, "mytool-hello") {
+		t.Fatalf("Expected the hello plugin, got %+v", plugin)
+	}
+	if len(args) != 2 || args[0] != "--loud" || args[1] != "world" {
+		t.Errorf("Expected the plugin arguments, got %v", args)
+	}
+
+	// Test case 2: Execute forwards the arguments and the exit code
+	err := parser.ExecuteArgs([]string{"hello", "a", "b"})
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Errorf("Expected exit code 3, got %v", err)
+	}
+	if data, _ := os.ReadFile(out); string(data) != "a b\n" {
+		t.Errorf("Expected the plugin to get a b, got %q", data)
+	}
+
+	// Test case 3: Built-in commands win, and other names stay unknown
+	if _, err := parser.ParseArgs([]string{"status"}); err != nil || parser.Result().Command().Name() != "status" {
+		t.Errorf("Expected the built-in status command, got %v", err)
+	}
+	if _, err := parser.ParseArgs([]string{"bye"}); err == nil || err.Error() != "unknown command bye" {
+		t.Errorf("Expected an unknown command error, got %v", err)
+	}
+
+	// Test case 4: Plugins lists executables only, without shadowed ones
+	plugins := parser.Plugins()
+	if len(plugins) != 1 || plugins[0].Name != "hello" {
+		t.Errorf("Expected only the hello plugin, got %+v", plugins)
+	}
+	if uargs.NewParser(nil).Plugins() != nil {
+		t.Errorf("Expected no plugins without WithPlugins")
+	}
+}
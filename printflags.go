@@ -0,0 +1,64 @@
+package uargs
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+// PrintFlagsName is the name of the hidden flag registered by WithPrintFlags
+const PrintFlagsName = "print-flags"
+
+// flagInfo is the --print-flags description of one argument. Field names are
+// part of the output format and must stay stable.
+type flagInfo struct {
+	Name       string      `json:"name"`
+	Short      string      `json:"short,omitempty"`
+	Type       ArgType     `json:"type"`
+	Usage      string      `json:"usage"`
+	Required   bool        `json:"required"`
+	Choices    []string    `json:"choices,omitempty"`
+	Default    interface{} `json:"default,omitempty"`
+	Value      interface{} `json:"value,omitempty"`
+	Deprecated string      `json:"deprecated,omitempty"`
+}
+
+// isPrintFlags reports whether --name requests the --print-flags dump
+func (p *Parser) isPrintFlags(name string) bool {
+	if p.printFlags == nil || name != PrintFlagsName {
+		return false
+	}
+	_, defined := p.defs[name]
+	return !defined
+}
+
+// writeFlags writes the --print-flags dump, sorted by argument name, with the
+// values parsed so far. It returns ErrPrintFlags once the dump is written.
+func (p *Parser) writeFlags() error {
+	flags := make([]flagInfo, 0, len(p.defs))
+	for name, def := range p.defs {
+		value := p.parsed[name]
+		if _, ok := value.(io.Reader); ok {
+			value = StdinPath
+		}
+		flags = append(flags, flagInfo{
+			Name:       name,
+			Short:      def.Short,
+			Type:       valueType(def),
+			Usage:      def.Usage,
+			Required:   def.Required,
+			Choices:    def.Choices,
+			Default:    redactValue(def, def.Default),
+			Value:      redactValue(def, value),
+			Deprecated: def.Deprecated,
+		})
+	}
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+
+	enc := json.NewEncoder(p.printFlags)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(map[string]interface{}{"flags": flags}); err != nil {
+		return err
+	}
+	return ErrPrintFlags
+}
@@ -0,0 +1,75 @@
+package uargs_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestPrintFlags tests the hidden --print-flags introspection dump
+func TestPrintFlags(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "user", Short: "u", Usage: "User name", Type: uargs.String, Required: true},
+		{Name: "format", Usage: "Output format", Choices: []string{"json", "text"}, Default: "text"},
+		{Name: "count", Usage: "Count", Type: uargs.Int},
+	}
+	var out bytes.Buffer
+	parser := uargs.NewParser(args, uargs.WithPrintFlags(&out))
+
+	// Test case 1: Dump despite the missing required argument
+	_, err := parser.ParseArgs([]string{"--count", "3", "--print-flags"})
+	if !errors.Is(err, uargs.ErrPrintFlags) {
+		t.Fatalf("Expected ErrPrintFlags, got %v", err)
+	}
+	var dump struct {
+		Flags []struct {
+			Name     string      `json:"name"`
+			Short    string      `json:"short"`
+			Type     string      `json:"type"`
+			Required bool        `json:"required"`
+			Choices  []string    `json:"choices"`
+			Value    interface{} `json:"value"`
+		} `json:"flags"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &dump); err != nil {
+		t.Fatalf("Expected JSON output, got %v:\n%s", err, out.String())
+	}
+	if len(dump.Flags) != 3 {
+		t.Fatalf("Expected 3 flags, got %+v", dump.Flags)
+	}
+	count, format, user := dump.Flags[0], dump.Flags[1], dump.Flags[2]
+	if count.Name != "count" || count.Type != "int" || count.Value != float64(3) {
+		t.Errorf("Expected count with its given value, got %+v", count)
+	}
+	if format.Value != "text" || len(format.Choices) != 2 {
+		t.Errorf("Expected format with choices and default value, got %+v", format)
+	}
+	if user.Short != "u" || !user.Required || user.Value != nil {
+		t.Errorf("Expected required user without value, got %+v", user)
+	}
+
+	// Test case 2: The flag is unknown unless enabled
+	if _, err := uargs.NewParser(args).ParseArgs([]string{"--print-flags"}); err == nil || err.Error() != "unknown argument --print-flags" {
+		t.Errorf("Expected unknown argument error, got %v", err)
+	}
+
+	// Test case 3: The flag is hidden from usage
+	if bytes.Contains([]byte(parser.Usage()), []byte("print-flags")) {
+		t.Errorf("Expected --print-flags to be hidden from usage")
+	}
+
+	// Test case 4: Subcommands dump their own flags
+	out.Reset()
+	parser.AddCommand(uargs.Command{Name: "serve", Usage: "Serve", Args: []uargs.ArgDef{
+		{Name: "workers", Usage: "Workers", Type: uargs.Int, Required: true},
+	}})
+	if _, err := parser.ParseArgs([]string{"-u", "x", "serve", "--print-flags"}); !errors.Is(err, uargs.ErrPrintFlags) {
+		t.Fatalf("Expected ErrPrintFlags from the subcommand, got %v", err)
+	}
+	if err := json.Unmarshal(out.Bytes(), &dump); err != nil || len(dump.Flags) != 1 || dump.Flags[0].Name != "workers" {
+		t.Errorf("Expected the workers flag of serve, got %v:\n%s", err, out.String())
+	}
+}
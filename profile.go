@@ -0,0 +1,101 @@
+package uargs
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ProfileName is the name of the flag registered by WithProfiles
+const ProfileName = "profile"
+
+// profilePrefix starts the config file keys of profiles, as in
+// "profiles.staging.port"
+const profilePrefix = "profiles."
+
+// profileValues returns the values of the profile chosen with --profile, its
+// environment variable, or the profile key of the config files, keyed as in
+// config files. Values set in the config files replace those set with
+// WithProfiles. It returns nil if no profile is chosen.
+func (p *Parser) profileValues(cfg *configSet) (map[string]configValue, error) {
+	name, _ := p.parsed[ProfileName].(string)
+	if name == "" {
+		for _, key := range p.configKeys(ProfileName) {
+			if v, ok := cfg.values[key]; ok && len(v.args) == 1 {
+				name = v.args[0]
+				break
+			}
+		}
+	}
+	if name == "" {
+		return nil, nil
+	}
+	values := make(map[string]configValue)
+	profile, found := p.profiles[name]
+	for key, value := range profile {
+		args, err := rawValues(value)
+		if err != nil {
+			return nil, fmt.Errorf("profile %s: %s: %v", name, key, err)
+		}
+		values[key] = configValue{profile: name, key: key, args: args}
+	}
+	prefix := profilePrefix + name + "."
+	for key, v := range cfg.values {
+		if rest, ok := strings.CutPrefix(key, prefix); ok {
+			v.profile = name
+			values[rest] = v
+			found = true
+		}
+	}
+	if !found {
+		names := p.profileNames(cfg)
+		if len(names) == 0 {
+			return nil, fmt.Errorf("unknown profile %s, no profiles are defined", name)
+		}
+		return nil, fmt.Errorf("unknown profile %s, expected one of %s", name, strings.Join(names, ", "))
+	}
+	return values, nil
+}
+
+// profileNames returns the sorted names of the profiles set with WithProfiles
+// or in the config files
+func (p *Parser) profileNames(cfg *configSet) []string {
+	var names []string
+	for name := range p.profiles {
+		names = append(names, name)
+	}
+	for key := range cfg.values {
+		if rest, ok := strings.CutPrefix(key, profilePrefix); ok {
+			name, _, _ := strings.Cut(rest, ".")
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// profileSource looks up arguments in the profile chosen with --profile
+type profileSource struct {
+	p      *Parser
+	cfg    *configSet
+	values map[string]configValue // Values of the profile, once it is chosen
+	err    error                  // Error choosing the profile
+	done   bool                   // Reports whether the profile was chosen
+}
+
+// Lookup returns the value of def from the chosen profile. The profile is
+// chosen on the first call, after earlier sources had their turn to set
+// --profile.
+func (s *profileSource) Lookup(path []string, def ArgDef) (SourceValue, bool, error) {
+	if !s.done {
+		s.values, s.err = s.p.profileValues(s.cfg)
+		s.done = true
+		if s.err != nil {
+			s.err = argError(s.p.defs[ProfileName], s.err)
+		}
+	}
+	if s.err != nil {
+		return SourceValue{}, false, s.err
+	}
+	return lookupConfig(s.p, s.values, def, OriginProfile)
+}
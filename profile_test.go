@@ -0,0 +1,92 @@
+package uargs_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestProfiles tests applying named bundles of values with --profile
+func TestProfiles(t *testing.T) {
+	path := writeConfig(t, "config.toml", `host = "config.example.com"
+replicas = 2
+
+[profiles.prod]
+replicas = 10
+
+[profiles.local]
+host = "localhost"
+`)
+	parser := uargs.NewParser([]uargs.ArgDef{
+		{Name: "host", Usage: "Host", Type: uargs.String, Default: "default.example.com"},
+		{Name: "replicas", Usage: "Replicas", Type: uargs.Int, Env: "MYAPP_REPLICAS", Default: 1},
+	}, uargs.WithConfigFile(path), uargs.WithProfiles(map[string]map[string]interface{}{
+		"staging": {"host": "staging.example.com", "replicas": 3},
+		"prod":    {"host": "example.com", "replicas": 5, "serve.workers": 16},
+	}))
+	serve := parser.AddCommand(uargs.Command{Name: "serve", Usage: "Serve", Args: []uargs.ArgDef{
+		{Name: "workers", Usage: "Workers", Type: uargs.Int, Default: 1},
+	}})
+
+	tests := []struct {
+		argv           []string
+		host           string
+		replicas       int
+		workers        int
+		replicasOrigin uargs.Origin
+	}{
+		{[]string{"serve"}, "config.example.com", 2, 1, uargs.OriginConfig},
+		{[]string{"--profile", "staging", "serve"}, "staging.example.com", 3, 1, uargs.OriginProfile},
+		{[]string{"serve", "--profile", "prod"}, "example.com", 10, 16, uargs.OriginProfile},
+		{[]string{"--profile", "local", "serve"}, "localhost", 2, 1, uargs.OriginConfig},
+		{[]string{"--profile", "prod", "--replicas", "7", "serve"}, "example.com", 7, 16, uargs.OriginCommandLine},
+	}
+	for _, test := range tests {
+		parsed, err := parser.ParseArgs(test.argv)
+		if err != nil {
+			t.Fatalf("Unexpected error for %v: %v", test.argv, err)
+		}
+		if parsed["host"] != test.host || parsed["replicas"] != test.replicas {
+			t.Errorf("%v: Expected host=%s and replicas=%d, got %v", test.argv, test.host, test.replicas, parsed)
+		}
+		if workers := serve.Result().Map()["workers"]; workers != test.workers {
+			t.Errorf("%v: Expected workers=%d, got %v", test.argv, test.workers, workers)
+		}
+		if origin := parser.Result().Origin("replicas"); origin != test.replicasOrigin {
+			t.Errorf("%v: Expected origin %s, got %s", test.argv, test.replicasOrigin, origin)
+		}
+	}
+
+	// Test case: The environment takes precedence over profiles
+	t.Setenv("MYAPP_REPLICAS", "4")
+	if parsed, err := parser.ParseArgs([]string{"--profile", "prod"}); err != nil || parsed["replicas"] != 4 {
+		t.Errorf("Expected replicas=4 from the environment, got %v (%v)", parsed, err)
+	}
+
+	// Test case: Unknown profiles list the known ones
+	_, err := parser.ParseArgs([]string{"--profile", "dev"})
+	if err == nil || err.Error() != "unknown profile dev, expected one of local, prod, staging" {
+		t.Errorf("Expected an unknown profile error, got %v", err)
+	}
+}
+
+// TestProfileFromConfig tests choosing the profile in the config file
+func TestProfileFromConfig(t *testing.T) {
+	path := writeConfig(t, "config.json", `{"profile": "prod", "profiles": {"prod": {"port": 443}}}`)
+	parser := uargs.NewParser([]uargs.ArgDef{
+		{Name: "port", Usage: "Port", Type: uargs.Int, Default: 80},
+	}, uargs.WithConfigFile(path), uargs.WithProfiles(nil))
+	parsed, err := parser.ParseArgs(nil)
+	if err != nil || parsed["port"] != 443 || parsed["profile"] != "prod" {
+		t.Errorf("Expected port=443 from the prod profile, got %v (%v)", parsed, err)
+	}
+
+	// Test case: Invalid profile values name the profile and key
+	parser = uargs.NewParser([]uargs.ArgDef{
+		{Name: "port", Usage: "Port", Type: uargs.Int},
+	}, uargs.WithProfiles(map[string]map[string]interface{}{"bad": {"port": "http"}}))
+	if _, err := parser.ParseArgs([]string{"--profile", "bad"}); err == nil || !strings.HasSuffix(err.Error(), "(from profile bad, key port)") {
+		t.Errorf("Expected an error naming the profile, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package uargs
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Prompt written by RunREPL and the names of its built-in commands. Commands
+// defined with these names take precedence.
+const (
+	replPrompt = "> "
+	replExit   = "exit"
+	replQuit   = "quit"
+)
+
+// RunREPL runs an interactive session: it reads command lines from the reader
+// set with WithStdin, splits them with Split, and runs them like Execute, so
+// the same definitions serve one-shot and interactive use. Errors are written
+// to the output set with WithOutput and the session goes on. The built-in
+// command "help [command]" shows usage, and "exit" or "quit" ends the session,
+// as does the end of input. Flags given to the process itself
+// (by the last successful Parse) are shared by every line, as with RunBatch.
+//
+// Example:
+//
+//	if len(os.Args) > 1 {
+//		err = parser.Execute()
+//	} else {
+//		err = parser.RunREPL()
+//	}
+func (p *Parser) RunREPL() error {
+	return p.RunREPLContext(context.Background())
+}
+
+// RunREPLContext is like RunREPL but passes ctx to Run and the hooks. The
+// session ends with ctx's error once ctx is done.
+func (p *Parser) RunREPLContext(ctx context.Context) error {
+	if p == nil {
+		return ErrNilParser
+	}
+	defer p.keepResult()()
+	shared := p.Result().givenValues()
+
+	scanner := bufio.NewScanner(p.stdin)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		io.WriteString(p.output, replPrompt)
+		if !scanner.Scan() {
+			io.WriteString(p.output, "\n")
+			return scanner.Err()
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		argv, err := Split(line)
+		if err != nil {
+			fmt.Fprintf(p.output, "error: %v\n", err)
+			continue
+		}
+		if len(argv) > 0 && p.findCommand(argv[0]) == nil {
+			switch argv[0] {
+			case replExit, replQuit:
+				return nil
+			case HelpName:
+				if err := p.writeHelp(p.output, argv[1:]); !errors.Is(err, ErrHelp) {
+					fmt.Fprintf(p.output, "error: %v\n", err)
+				}
+				continue
+			}
+		}
+		err = p.execute(ctx, argv, shared)
+		if err != nil && !errors.Is(err, ErrHelp) && !errors.Is(err, ErrVersion) && !errors.Is(err, ErrPrintFlags) && !errors.Is(err, ErrSelfTest) {
+			fmt.Fprintf(p.output, "error: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package uargs_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestRunREPL tests running command lines read interactively
+func TestRunREPL(t *testing.T) {
+	var out strings.Builder
+	var calls []string
+	input := strings.NewReader("add --name 'a b'\n\n# comment\nadd\nbogus\nhelp add\nhelp nope\nadd --name c\nexit\nadd --name never\n")
+	parser := uargs.NewParser([]uargs.ArgDef{
+		{Name: "verbose", Usage: "Verbosity", Type: uargs.Int, Default: 0, Persistent: true},
+	}, uargs.WithStdin(input), uargs.WithOutput(&out))
+	parser.AddCommand(uargs.Command{Name: "add", Usage: "Add an item",
+		Args: []uargs.ArgDef{{Name: "name", Usage: "Item name", Type: uargs.String, Required: true}},
+		Run: func(_ context.Context, parsed map[string]interface{}) error {
+			calls = append(calls, parsed["name"].(string))
+			return nil
+		},
+	})
+
+	// Process flags are shared with every line
+	if _, err := parser.ParseArgs([]string{"--verbose", "2"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := parser.RunREPL(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := strings.Join(calls, ","); got != "a b,c" {
+		t.Errorf("Expected runs for a b and c, got %s", got)
+	}
+	text := out.String()
+	for _, want := range []string{
+		"> error: missing required argument --name\n",
+		"> error: unknown command bogus\n",
+		"Usage: add\n\nAdd an item\n",
+		"> error: unknown command nope\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, text)
+		}
+	}
+	if v, _ := parser.Result().Get("verbose"); v != 2 {
+		t.Errorf("Expected the process result to be restored, got verbose=%v", v)
+	}
+
+	// The session ends at the end of input
+	out.Reset()
+	eof := uargs.NewParser(nil, uargs.WithStdin(strings.NewReader("")), uargs.WithOutput(&out))
+	if err := eof.RunREPL(); err != nil || out.String() != "> \n" {
+		t.Errorf("Expected a clean end of input, got %v, %q", err, out.String())
+	}
+}
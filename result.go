@@ -0,0 +1,531 @@
+package uargs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+)
+
+// Result gives access to the values of a successful parse. It is obtained from
+// Parser.Result after Parse or ParseArgs succeeds.
+type Result struct {
+	parser     *Parser
+	values     map[string]interface{}
+	given      map[string]bool   // Arguments given on the command line
+	sources    map[string]Source // Where each value came from
+	validated  map[string]error  // Outcomes of deferred validators that already ran
+	command    *Parser           // Parser of the subcommand that was selected, if any
+	plugin     *Plugin           // Plugin that was selected, if any
+	pluginArgs []string          // Arguments following the plugin's command name
+	afterDash  []string          // Arguments after "--", nil if there was none
+	config     *configSet        // Config files read by the parse
+}
+
+// Origin tells where the value of an argument came from
+type Origin string
+
+const (
+	// OriginCommandLine is the origin of values given on the command line
+	OriginCommandLine Origin = "cli"
+	// OriginEnv is the origin of values taken from an argument's Env variable
+	OriginEnv Origin = "env"
+	// OriginProfile is the origin of values taken from the profile chosen with
+	// --profile (see WithProfiles)
+	OriginProfile Origin = "profile"
+	// OriginConfig is the origin of values taken from the config file set with
+	// WithConfigFile
+	OriginConfig Origin = "config"
+	// OriginSecret is the origin of values of Secret arguments taken from the
+	// provider set with WithSecrets
+	OriginSecret Origin = "secret"
+	// OriginCustom is the origin of values taken from a source added with
+	// WithSource, unless the source sets another
+	OriginCustom Origin = "custom"
+	// OriginDefault is the origin of values taken from Default or DefaultFunc
+	OriginDefault Origin = "default"
+	// OriginProgrammatic is the origin of values stored with Result.Set
+	OriginProgrammatic Origin = "programmatic"
+)
+
+// Source describes where the value of an argument came from, as returned by
+// Result.Source
+type Source struct {
+	// Origin is the kind of source
+	Origin Origin
+	// Env is the environment variable the value was read from, for OriginEnv
+	Env string
+	// File is the config file or dotenv file the value was read from
+	File string
+	// Line is the line of the value in File, or 0 if unknown
+	Line int
+	// Key is the key of the value in the config file, profile, or secret
+	// provider
+	Key string
+	// Profile is the profile the value was taken from, for OriginProfile
+	Profile string
+}
+
+// String describes s, as in "env MYAPP_PORT" or
+// "config file /etc/mytool.toml:3, key port"
+func (s Source) String() string {
+	var b strings.Builder
+	b.WriteString(string(s.Origin))
+	switch s.Origin {
+	case OriginEnv:
+		fmt.Fprintf(&b, " %s", s.Env)
+		if s.File != "" {
+			fmt.Fprintf(&b, " (%s)", s.File)
+		}
+		return b.String()
+	case OriginConfig:
+		b.WriteString(" file")
+	case OriginProfile:
+		fmt.Fprintf(&b, " %s", s.Profile)
+	}
+	if s.File != "" {
+		fmt.Fprintf(&b, " %s", s.File)
+		if s.Line != 0 {
+			fmt.Fprintf(&b, ":%d", s.Line)
+		}
+	}
+	if s.Key != "" {
+		fmt.Fprintf(&b, ", key %s", s.Key)
+	}
+	return b.String()
+}
+
+// newResult wraps the parsed values of p and the names of the arguments given
+func newResult(p *Parser, values map[string]interface{}, given map[string]bool) *Result {
+	sources := make(map[string]Source, len(values))
+	for name := range values {
+		sources[name] = Source{Origin: OriginDefault}
+		if given[name] {
+			sources[name] = Source{Origin: OriginCommandLine}
+		}
+	}
+	return &Result{parser: p, values: values, given: given, sources: sources, validated: make(map[string]error)}
+}
+
+// Result returns the values of the last successful parse, or nil if the last
+// parse failed or Parse has not been called. Methods on a nil Result return
+// ErrNotParsed.
+//
+// Example:
+//
+//	if _, err := parser.Parse(); err != nil {
+//		log.Fatal(err)
+//	}
+//	res := parser.Result()
+func (p *Parser) Result() *Result {
+	if p == nil || !p.done {
+		return nil
+	}
+	return p.result
+}
+
+// Get returns the value of the named argument and whether it is present
+func (r *Result) Get(name string) (interface{}, bool) {
+	if r == nil {
+		return nil, false
+	}
+	v, ok := r.values[name]
+	return v, ok
+}
+
+// Map returns a copy of all parsed values keyed by argument name
+func (r *Result) Map() map[string]interface{} {
+	if r == nil {
+		return nil
+	}
+	values := make(map[string]interface{}, len(r.values))
+	for name, v := range r.values {
+		values[name] = v
+	}
+	return values
+}
+
+// Command returns the result of the subcommand selected on the command line,
+// or nil if none was
+//
+// Example:
+//
+//	if cmd := parser.Result().Command(); cmd != nil {
+//		fmt.Println("running", cmd.Name())
+//	}
+func (r *Result) Command() *Result {
+	if r == nil {
+		return nil
+	}
+	return r.command.Result()
+}
+
+// Plugin returns the plugin selected on the command line and the arguments
+// that follow its name, or nil if no plugin was selected. Execute runs it.
+//
+// Example:
+//
+//	if plugin, args := parser.Result().Plugin(); plugin != nil {
+//		fmt.Println("running", plugin.Path, args)
+//	}
+func (r *Result) Plugin() (*Plugin, []string) {
+	if r == nil || r.plugin == nil {
+		return nil, nil
+	}
+	return r.plugin, r.pluginArgs
+}
+
+// Passthrough returns the arguments after "--" on the command line, which are
+// left unparsed for another program, as in "mytool run -- ls -la". It looks
+// through the selected subcommands and reports false if there was no "--".
+//
+// Example:
+//
+//	if args, ok := parser.Result().Passthrough(); ok {
+//		fmt.Println("forwarding", args)
+//	}
+func (r *Result) Passthrough() ([]string, bool) {
+	for q := r; q != nil; q = q.Command() {
+		if q.afterDash != nil {
+			return append([]string(nil), q.afterDash...), true
+		}
+	}
+	return nil, false
+}
+
+// PassthroughCommand builds a command that runs the arguments after "--" as a
+// program and its arguments, connected to the standard streams. Arguments are
+// passed as given, without a shell, so quoting is preserved. ctx kills the
+// process when it is done.
+//
+// Example:
+//
+//	// mytool --retries 3 -- curl -s "https://example.com/a b"
+//	cmd, err := parser.Result().PassthroughCommand(ctx)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	err = cmd.Run()
+func (r *Result) PassthroughCommand(ctx context.Context) (*exec.Cmd, error) {
+	if r == nil {
+		return nil, ErrNotParsed
+	}
+	args, _ := r.Passthrough()
+	if len(args) == 0 {
+		return nil, fmt.Errorf("missing command after --")
+	}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd, nil
+}
+
+// ConfigFile returns the path of the config file the parse read values from,
+// whether given with --config, set with WithConfigFile, or found by
+// WithConfigDiscovery. It returns "" if no such file was read. Files set with
+// WithConfigLayers are reported by ConfigFiles.
+func (r *Result) ConfigFile() string {
+	if r == nil || r.config == nil {
+		return ""
+	}
+	return r.config.file
+}
+
+// ConfigFiles returns the paths of the config files the parse read values
+// from, lowest precedence first
+func (r *Result) ConfigFiles() []string {
+	if r == nil || r.config == nil {
+		return nil
+	}
+	return slices.Clone(r.config.files)
+}
+
+// Config returns the merged keys of the config files the parse read, such as
+// "port" or "remote.add.name", with the file and line each value came from.
+// Keys that no argument uses are included. The values of Secret arguments are
+// redacted.
+//
+// Example:
+//
+//	for key, v := range parser.Result().Config() {
+//		fmt.Printf("%s = %v (%s:%d)\n", key, v.Values, v.File, v.Line)
+//	}
+func (r *Result) Config() map[string]ConfigValue {
+	if r == nil || r.config == nil {
+		return nil
+	}
+	secrets := make(map[string]bool)
+	r.parser.secretKeys(secrets)
+	values := make(map[string]ConfigValue)
+	for key, v := range r.config.values {
+		// INI sections store each key under several aliases
+		if key != v.key {
+			continue
+		}
+		args := slices.Clone(v.args)
+		name := key
+		if rest, ok := strings.CutPrefix(key, profilePrefix); ok {
+			_, name, _ = strings.Cut(rest, ".")
+		}
+		if secrets[name] {
+			for i := range args {
+				args[i] = redacted
+			}
+		}
+		values[key] = ConfigValue{Values: args, File: v.path, Line: v.line, Column: v.column}
+	}
+	return values
+}
+
+// secretKeys adds the config keys of the Secret arguments of p and its
+// subcommands to keys
+func (p *Parser) secretKeys(keys map[string]bool) {
+	for name, def := range p.defs {
+		if def.Secret {
+			keys[strings.Join(append(p.path(), name), ".")] = true
+		}
+	}
+	for _, c := range p.commands {
+		c.secretKeys(keys)
+	}
+}
+
+// CommandPath returns the names of the nested subcommands selected on the
+// command line below r, such as ["remote", "add"] for "mytool remote add"
+func (r *Result) CommandPath() []string {
+	var path []string
+	for cmd := r.Command(); cmd != nil; cmd = cmd.Command() {
+		path = append(path, cmd.Name())
+	}
+	return path
+}
+
+// Name returns the name of the command the result belongs to, or "" for the
+// result of a parser created with NewParser
+func (r *Result) Name() string {
+	if r == nil {
+		return ""
+	}
+	return r.parser.Name()
+}
+
+// givenValues returns the values of the arguments given on the command line,
+// leaving out defaults
+func (r *Result) givenValues() map[string]interface{} {
+	if r == nil {
+		return nil
+	}
+	values := make(map[string]interface{}, len(r.given))
+	for name := range r.given {
+		values[name] = r.values[name]
+	}
+	return values
+}
+
+// Validate runs the DeferredValidate callbacks of the named arguments, so that
+// expensive checks (network reachability, hashing large files) only happen on
+// the code paths that need them. Arguments without a value are skipped. The
+// outcome of each validator is remembered, so repeated calls are cheap.
+//
+// Example:
+//
+//	if err := parser.Result().Validate("endpoint"); err != nil {
+//		log.Fatal(err)
+//	}
+func (r *Result) Validate(names ...string) error {
+	if r == nil {
+		return ErrNotParsed
+	}
+	for _, name := range names {
+		def, ok := r.parser.defs[name]
+		if !ok {
+			return fmt.Errorf("unknown argument --%s", name)
+		}
+		err, done := r.validated[name]
+		if !done {
+			err = r.validate(def)
+			r.validated[name] = err
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate runs the deferred validator of a single argument
+func (r *Result) validate(def ArgDef) error {
+	v, ok := r.values[def.Name]
+	if !ok || def.DeferredValidate == nil {
+		return nil
+	}
+	r.parser.debug("validator run", "flag", def.Name, "validator", "deferred")
+	if err := def.DeferredValidate(v); err != nil {
+		return fmt.Errorf("invalid value for %s: %v", flagName(def), redactError(def, err, v))
+	}
+	return nil
+}
+
+// Set replaces the value of the named argument, for example to force
+// --workers to 1 when --debug is given. The value goes through the same
+// conversion, constraints, and Validate callback as a command-line value, so
+// it may be given as a string or as a typed value such as an int. Validators
+// added with AddValidator are run on the updated values; if any check fails
+// the result is left unchanged. The value's Origin becomes OriginProgrammatic.
+// Snapshots taken earlier keep the old value.
+//
+// Example:
+//
+//	if debug, _ := res.Get("debug"); debug != nil {
+//		err = res.Set("workers", 1)
+//	}
+func (r *Result) Set(name string, value interface{}) error {
+	if r == nil {
+		return ErrNotParsed
+	}
+	p := r.parser
+	def, ok := p.defs[name]
+	if !ok {
+		return fmt.Errorf("unknown argument --%s", name)
+	}
+	args, err := rawValues(value)
+	if err != nil {
+		return argError(def, fmt.Errorf("cannot set %s: %v", flagName(def), redactError(def, err, fmt.Sprint(value))))
+	}
+	val, err := p.convert(def, args)
+	if err != nil {
+		return argError(def, err)
+	}
+
+	old, had := r.values[name]
+	r.values[name] = val
+	for _, fn := range p.validators {
+		if err := fn(r.values); err != nil {
+			if had {
+				r.values[name] = old
+			} else {
+				delete(r.values, name)
+			}
+			return err
+		}
+	}
+	r.sources[name] = Source{Origin: OriginProgrammatic}
+	delete(r.validated, name)
+	return nil
+}
+
+// rawValues formats a value given to Set as the strings it would have been
+// written as on the command line
+func rawValues(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case string, int, float64:
+		return []string{fmt.Sprint(v)}, nil
+	case []string:
+		return append([]string(nil), v...), nil
+	case []int:
+		args := make([]string, len(v))
+		for i, n := range v {
+			args[i] = fmt.Sprint(n)
+		}
+		return args, nil
+	case []float64:
+		args := make([]string, len(v))
+		for i, f := range v {
+			args[i] = fmt.Sprint(f)
+		}
+		return args, nil
+	}
+	return nil, fmt.Errorf("unsupported value %v (%T)", value, value)
+}
+
+// Origin returns where the value of the named argument came from, or "" if
+// the argument has no value
+func (r *Result) Origin(name string) Origin {
+	if r == nil {
+		return ""
+	}
+	return r.sources[name].Origin
+}
+
+// Source returns where the value of the named argument came from, including
+// the environment variable, config file, or profile, or the zero Source if the
+// argument has no value
+//
+// Example:
+//
+//	// port=8080 from config file /etc/mytool.toml:3, key port
+//	fmt.Printf("port=%v from %s\n", parsed["port"], parser.Result().Source("port"))
+func (r *Result) Source(name string) Source {
+	if r == nil {
+		return Source{}
+	}
+	return r.sources[name]
+}
+
+// Source returns where the value of the named argument came from in the last
+// successful parse, as Result.Source does
+func (p *Parser) Source(name string) Source {
+	return p.Result().Source(name)
+}
+
+// Snapshot returns a read-only copy of the current values. Hand snapshots to
+// independent handlers so that none of them can observe another's changes to
+// the Result.
+//
+// Example:
+//
+//	for _, h := range handlers {
+//		h(parser.Result().Snapshot())
+//	}
+func (r *Result) Snapshot() *Snapshot {
+	if r == nil {
+		return nil
+	}
+	values := make(map[string]interface{}, len(r.values))
+	for name, v := range r.values {
+		values[name] = copyValue(v)
+	}
+	return &Snapshot{values: values}
+}
+
+// Snapshot is an immutable view of parsed values, taken with Result.Snapshot
+type Snapshot struct {
+	values map[string]interface{}
+}
+
+// Get returns the value of the named argument and whether it is present.
+// Slice values are copies, so modifying them does not change the snapshot.
+func (s *Snapshot) Get(name string) (interface{}, bool) {
+	if s == nil {
+		return nil, false
+	}
+	v, ok := s.values[name]
+	return copyValue(v), ok
+}
+
+// Map returns a copy of all values keyed by argument name
+func (s *Snapshot) Map() map[string]interface{} {
+	if s == nil {
+		return nil
+	}
+	values := make(map[string]interface{}, len(s.values))
+	for name, v := range s.values {
+		values[name] = copyValue(v)
+	}
+	return values
+}
+
+// copyValue copies the slice values produced by parsing, so they cannot be
+// modified through another reference
+func copyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case []string:
+		return append([]string(nil), v...)
+	case []int:
+		return append([]int(nil), v...)
+	case []float64:
+		return append([]float64(nil), v...)
+	}
+	return v
+}
@@ -0,0 +1,272 @@
+package uargs_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestResultValidate tests deferred validators run on demand
+func TestResultValidate(t *testing.T) {
+	calls := 0
+	args := []uargs.ArgDef{
+		{Name: "endpoint", Usage: "Endpoint URL", Type: uargs.String, DeferredValidate: func(v interface{}) error {
+			calls++
+			if v.(string) == "unreachable" {
+				return errors.New("host is unreachable")
+			}
+			return nil
+		}},
+		{Name: "name", Usage: "Name", Type: uargs.String},
+	}
+	parser := uargs.NewParser(args)
+
+	if err := parser.Result().Validate("endpoint"); !errors.Is(err, uargs.ErrNotParsed) {
+		t.Errorf("Expected ErrNotParsed before parsing, got %v", err)
+	}
+
+	if _, err := parser.ParseArgs([]string{"--endpoint", "unreachable"}); err != nil {
+		t.Fatalf("Expected deferred validator not to run during parsing, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected no validator calls during parsing, got %d", calls)
+	}
+
+	res := parser.Result()
+	err := res.Validate("name", "endpoint")
+	if err == nil || err.Error() != "invalid value for --endpoint: host is unreachable" {
+		t.Errorf("Expected deferred validation error, got %v", err)
+	}
+	res.Validate("endpoint")
+	if calls != 1 {
+		t.Errorf("Expected validator outcome to be cached, got %d calls", calls)
+	}
+
+	if v, ok := res.Get("endpoint"); !ok || v != "unreachable" {
+		t.Errorf("Expected endpoint value from result, got %v", v)
+	}
+	if err := res.Validate("missing"); err == nil {
+		t.Error("Expected error for unknown argument, got nil")
+	}
+}
+
+// TestSnapshot tests read-only copies of results and overrides on the root
+func TestSnapshot(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "name", Usage: "Name", Type: uargs.String},
+		{Name: "ports", Usage: "Ports", Type: uargs.Int, Greedy: true},
+	}
+	parser := uargs.NewParser(args)
+	if _, err := parser.ParseArgs([]string{"--name", "web", "--ports", "80", "443"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	res := parser.Result()
+	snap := res.Snapshot()
+
+	if err := res.Set("name", "api"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v, _ := res.Get("name"); v != "api" {
+		t.Errorf("Expected name=api after Set, got %v", v)
+	}
+	if v, _ := snap.Get("name"); v != "web" {
+		t.Errorf("Expected snapshot to keep name=web, got %v", v)
+	}
+
+	ports, _ := snap.Get("ports")
+	ports.([]int)[0] = 8080
+	if v, _ := snap.Get("ports"); v.([]int)[0] != 80 {
+		t.Errorf("Expected snapshot slices to be immutable, got %v", v)
+	}
+	snap.Map()["name"] = "changed"
+	if v, _ := snap.Get("name"); v != "web" {
+		t.Errorf("Expected snapshot map to be immutable, got %v", v)
+	}
+
+	if err := res.Set("missing", 1); err == nil || err.Error() != "unknown argument --missing" {
+		t.Errorf("Expected unknown argument error, got %v", err)
+	}
+	var nilResult *uargs.Result
+	if err := nilResult.Set("name", "x"); !errors.Is(err, uargs.ErrNotParsed) {
+		t.Errorf("Expected ErrNotParsed, got %v", err)
+	}
+	if nilResult.Snapshot() != nil {
+		t.Errorf("Expected nil snapshot of a nil Result")
+	}
+}
+
+// TestResultSet tests validated programmatic overrides and their origin
+func TestResultSet(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "workers", Usage: "Workers", Type: uargs.Int, Min: uargs.Bound(1), Default: 4},
+		{Name: "debug", Usage: "Debug", Type: uargs.String},
+		{Name: "ratio", Usage: "Ratio", Type: uargs.Float, NumArgs: 2},
+		{Name: "mode", Usage: "Mode", Choices: []string{"fast", "safe"}, IgnoreCase: true},
+	}
+	parser := uargs.NewParser(args)
+	parser.AddValidator(func(parsed map[string]interface{}) error {
+		if parsed["debug"] != nil && parsed["workers"] != 1 {
+			return errors.New("--debug needs a single worker")
+		}
+		return nil
+	})
+	if _, err := parser.ParseArgs([]string{"--mode", "fast"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	res := parser.Result()
+	if res.Origin("mode") != uargs.OriginCommandLine || res.Origin("workers") != uargs.OriginDefault || res.Origin("debug") != "" {
+		t.Errorf("Expected cli, default, and no origin, got %q, %q, %q", res.Origin("mode"), res.Origin("workers"), res.Origin("debug"))
+	}
+
+	// Test case 1: Typed and string values are converted like command-line values
+	if err := res.Set("workers", "2"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v, _ := res.Get("workers"); v != 2 || res.Origin("workers") != uargs.OriginProgrammatic {
+		t.Errorf("Expected workers=2 set programmatically, got %v from %q", v, res.Origin("workers"))
+	}
+	if err := res.Set("ratio", []float64{0.5, 1.5}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := res.Set("mode", "SAFE"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v, _ := res.Get("mode"); v != "safe" {
+		t.Errorf("Expected canonical choice, got %v", v)
+	}
+
+	// Test case 2: Invalid values are rejected and leave the result unchanged
+	if err := res.Set("workers", 0); err == nil || err.Error() != "--workers value 0 out of range [1, +inf)" {
+		t.Errorf("Expected range error, got %v", err)
+	}
+	if err := res.Set("workers", "many"); err == nil {
+		t.Errorf("Expected type error")
+	}
+	if err := res.Set("debug", true); err == nil || err.Error() != "cannot set --debug: unsupported value true (bool)" {
+		t.Errorf("Expected unsupported value error, got %v", err)
+	}
+	if err := res.Set("debug", "on"); err == nil || err.Error() != "--debug needs a single worker" {
+		t.Errorf("Expected cross-argument validator error, got %v", err)
+	}
+	if _, ok := res.Get("debug"); ok {
+		t.Errorf("Expected debug to stay unset after a failed Set")
+	}
+	if v, _ := res.Get("workers"); v != 2 {
+		t.Errorf("Expected workers to stay 2, got %v", v)
+	}
+
+	// Test case 3: Overrides that keep the result consistent
+	if err := res.Set("workers", 1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := res.Set("debug", "on"); err != nil {
+		t.Errorf("Expected debug with a single worker to be accepted, got %v", err)
+	}
+}
+
+// TestPassthrough tests collecting the arguments after "--"
+func TestPassthrough(t *testing.T) {
+	parser := uargs.NewParser([]uargs.ArgDef{
+		{Name: "retries", Usage: "Retries", Type: uargs.Int, Default: 1},
+	})
+	run := parser.AddCommand(uargs.Command{Name: "run", Usage: "Run a program"})
+
+	// Test case 1: Arguments after -- are kept as given, even if they look like flags
+	if _, err := parser.ParseArgs([]string{"--retries", "3", "--", "curl", "-s", "--retries", "https://example.com/a b"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	args, ok := parser.Result().Passthrough()
+	if !ok || strings.Join(args, "|") != "curl|-s|--retries|https://example.com/a b" {
+		t.Errorf("Expected the arguments after --, got %q", args)
+	}
+	if v, _ := parser.Result().Get("retries"); v != 3 {
+		t.Errorf("Expected retries=3, got %v", v)
+	}
+	cmd, err := parser.Result().PassthroughCommand(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(cmd.Args, "|") != "curl|-s|--retries|https://example.com/a b" {
+		t.Errorf("Expected the command arguments as given, got %q", cmd.Args)
+	}
+
+	// Test case 2: A subcommand's -- is found from the root
+	if _, err := parser.ParseArgs([]string{"run", "--", "ls", "-la"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if args, ok := parser.Result().Passthrough(); !ok || strings.Join(args, " ") != "ls -la" {
+		t.Errorf("Expected ls -la, got %q", args)
+	}
+	if args, ok := run.Result().Passthrough(); !ok || len(args) != 2 {
+		t.Errorf("Expected the command's result to hold the arguments, got %q", args)
+	}
+
+	// Test case 3: Without --, or with nothing after it
+	if _, err := parser.ParseArgs(nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := parser.Result().Passthrough(); ok {
+		t.Errorf("Expected no passthrough arguments")
+	}
+	if _, err := parser.ParseArgs([]string{"--"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if args, ok := parser.Result().Passthrough(); !ok || len(args) != 0 {
+		t.Errorf("Expected an empty passthrough list, got %q, %v", args, ok)
+	}
+	if _, err := parser.Result().PassthroughCommand(context.Background()); err == nil || err.Error() != "missing command after --" {
+		t.Errorf("Expected a missing command error, got %v", err)
+	}
+}
+
+// TestSource tests reporting where each value came from
+func TestSource(t *testing.T) {
+	config := writeConfig(t, "config.toml", "timeout = 30\n\n[profiles.prod]\nreplicas = 5\n")
+	dotEnv := writeConfig(t, "app.env", "MYAPP_HOST=example.com\n")
+	parser := uargs.NewParser([]uargs.ArgDef{
+		{Name: "port", Usage: "Port", Type: uargs.Int},
+		{Name: "host", Usage: "Host", Type: uargs.String},
+		{Name: "user", Usage: "User", Type: uargs.String},
+		{Name: "timeout", Usage: "Timeout", Type: uargs.Int},
+		{Name: "replicas", Usage: "Replicas", Type: uargs.Int},
+		{Name: "retries", Usage: "Retries", Type: uargs.Int, Default: 3},
+	}, uargs.WithEnvPrefix("MYAPP"), uargs.WithDotEnv(dotEnv), uargs.WithConfigFile(config), uargs.WithProfiles(nil))
+	t.Setenv("MYAPP_USER", "admin")
+	if _, err := parser.ParseArgs([]string{"--port", "8080", "--profile", "prod"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		expected uargs.Source
+		text     string
+	}{
+		{"port", uargs.Source{Origin: uargs.OriginCommandLine}, "cli"},
+		{"host", uargs.Source{Origin: uargs.OriginEnv, Env: "MYAPP_HOST", File: dotEnv}, "env MYAPP_HOST (" + dotEnv + ")"},
+		{"user", uargs.Source{Origin: uargs.OriginEnv, Env: "MYAPP_USER"}, "env MYAPP_USER"},
+		{"timeout", uargs.Source{Origin: uargs.OriginConfig, File: config, Line: 1, Key: "timeout"}, "config file " + config + ":1, key timeout"},
+		{"replicas", uargs.Source{Origin: uargs.OriginProfile, File: config, Line: 4, Key: "profiles.prod.replicas", Profile: "prod"}, "profile prod " + config + ":4, key profiles.prod.replicas"},
+		{"retries", uargs.Source{Origin: uargs.OriginDefault}, "default"},
+		{"missing", uargs.Source{}, ""},
+	}
+	for _, test := range tests {
+		src := parser.Source(test.name)
+		if src != test.expected {
+			t.Errorf("%s: Expected %+v, got %+v", test.name, test.expected, src)
+		}
+		if src.String() != test.text {
+			t.Errorf("%s: Expected %q, got %q", test.name, test.text, src.String())
+		}
+	}
+
+	// Test case: Values set programmatically lose their previous source
+	if err := parser.Result().Set("timeout", 60); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if src := parser.Source("timeout"); src != (uargs.Source{Origin: uargs.OriginProgrammatic}) {
+		t.Errorf("Expected a programmatic source, got %+v", src)
+	}
+}
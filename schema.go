@@ -0,0 +1,124 @@
+package uargs
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// checkConfig verifies that the config file cfg holds only keys of known
+// arguments, with values of the right type and number, so that typos do not
+// go unnoticed. Keys may belong to any command of the parser tree, or to a
+// profile under profiles.NAME. The first problem in the file is reported with
+// its line and column.
+func (p *Parser) checkConfig(cfg *configFile) error {
+	root := p
+	for root.parent != nil {
+		root = root.parent
+	}
+	defs := make(map[string]ArgDef)
+	root.schemaDefs(defs)
+
+	// INI sections store each key under several aliases, of which one is enough
+	type entry struct {
+		v     configValue
+		def   ArgDef
+		known bool
+	}
+	entries := make(map[string]*entry)
+	for alias, v := range cfg.values {
+		e := entries[v.key]
+		if e == nil {
+			e = &entry{v: v}
+			entries[v.key] = e
+		}
+		name := alias
+		if rest, ok := strings.CutPrefix(alias, profilePrefix); ok {
+			_, name, _ = strings.Cut(rest, ".")
+		}
+		if def, ok := defs[name]; ok && !e.known {
+			e.v, e.def, e.known = v, def, true
+		}
+	}
+	sorted := slices.Collect(maps.Values(entries))
+	slices.SortFunc(sorted, func(a, b *entry) int {
+		return cmp.Or(cmp.Compare(a.v.line, b.v.line), cmp.Compare(a.v.column, b.v.column))
+	})
+	for _, e := range sorted {
+		at := fmt.Sprintf("%s:%d:%d", cfg.path, e.v.line, e.v.column)
+		if !e.known {
+			msg := fmt.Sprintf("%s: unknown key %s", at, e.v.key)
+			if key := closestKey(root, e.v.key, defs); key != "" {
+				msg += ", did you mean " + key + "?"
+			}
+			return errors.New(msg)
+		}
+		if err := p.checkConfigValue(e.def, e.v.args); err != nil {
+			err = fmt.Errorf("%s: %s: %v", at, e.v.key, redactError(e.def, err, e.v.args))
+			return argError(e.def, classify(validationErrorKind, err))
+		}
+	}
+	return nil
+}
+
+// schemaDefs adds the arguments of p and its subcommands to defs, by config
+// key
+func (p *Parser) schemaDefs(defs map[string]ArgDef) {
+	for name, def := range p.defs {
+		defs[strings.Join(append(p.path(), name), ".")] = def
+	}
+	for _, c := range p.commands {
+		c.schemaDefs(defs)
+	}
+}
+
+// closestKey returns the key of defs nearest to the unknown key, or "" if
+// none is close enough to suggest
+func closestKey(p *Parser, key string, defs map[string]ArgDef) string {
+	best, bestDistance := "", p.suggestDistance+1
+	for candidate := range defs {
+		d := editDistance(key, candidate)
+		if d < bestDistance || (d == bestDistance && candidate < best) {
+			best, bestDistance = candidate, d
+		}
+	}
+	if bestDistance >= len(key) {
+		return ""
+	}
+	return best
+}
+
+// checkConfigValue verifies the number and type of the values of def in a
+// config file. Values that are expanded, read from files, or transformed
+// before conversion are only checked once they are converted.
+func (p *Parser) checkConfigValue(def ArgDef, args []string) error {
+	if n := maxArgs(def); n >= 0 && len(args) > n {
+		return fmt.Errorf("expected at most %d values, got %d", n, len(args))
+	}
+	if len(args) < def.MinArgs {
+		return fmt.Errorf("expected at least %d values, got %d", def.MinArgs, len(args))
+	}
+	if def.Transform != nil || def.AllowFileRef || len(def.ChoiceAliases) > 0 {
+		return nil
+	}
+	for _, s := range args {
+		if p.expandEnv && strings.Contains(s, "$") {
+			continue
+		}
+		var err error
+		switch valueType(def) {
+		case Int:
+			_, err = strconv.Atoi(s)
+		case Float:
+			_, err = strconv.ParseFloat(s, 64)
+		}
+		if err != nil {
+			return fmt.Errorf("expected %s, got %v", valueType(def), redactValue(def, strconv.Quote(s)))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package uargs_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestConfigSchema tests rejecting unknown keys and mistyped values in config
+// files
+func TestConfigSchema(t *testing.T) {
+	newParser := func(path string) *uargs.Parser {
+		parser := uargs.NewParser([]uargs.ArgDef{
+			{Name: "port", Usage: "Port", Type: uargs.Int},
+			{Name: "log-level", Usage: "Log level", Type: uargs.String},
+			{Name: "pin", Usage: "PIN", Type: uargs.Int, Secret: true},
+			{Name: "ratio", Usage: "Ratio", Type: uargs.Float, Transform: func(s string) (string, error) {
+				return strings.TrimSuffix(s, "%"), nil
+			}},
+		}, uargs.WithConfigFile(path), uargs.WithProfiles(nil))
+		parser.AddCommand(uargs.Command{Name: "serve", Usage: "Serve", Args: []uargs.ArgDef{
+			{Name: "workers", Usage: "Workers", Type: uargs.Int},
+		}})
+		parser.AddCommand(uargs.Command{Name: "deploy", Usage: "Deploy", Args: []uargs.ArgDef{
+			{Name: "targets", Usage: "Targets", Type: uargs.String, NumArgs: 2},
+		}})
+		return parser
+	}
+
+	// Test case 1: Known keys of all commands, profiles, and INI sections are accepted
+	for name, content := range map[string]string{
+		"config.json": `{"port": 80, "deploy": {"targets": ["a", "b"]}, "profiles": {"prod": {"serve": {"workers": 8}}}}`,
+		"config.toml": "ratio = \"50%\"\n[serve]\nworkers = 4\n",
+		"config.ini":  "[log]\nlevel = debug\n[deploy]\ntargets = a\ntargets = b\n",
+	} {
+		if _, err := newParser(writeConfig(t, name, content)).ParseArgs([]string{"serve"}); err != nil {
+			t.Errorf("%s: Unexpected error: %v", name, err)
+		}
+	}
+
+	// Test case 2: Unknown keys are reported with their position and a suggestion
+	tests := []struct {
+		name, content, expected string
+	}{
+		{"config.json", "{\n  \"port\": 80,\n  \"serve\": {\"wrokers\": 4}\n}", "%s:3:13: unknown key serve.wrokers, did you mean serve.workers?"},
+		{"config.toml", "port = 80\n\n[deploy]\n  target = \"a\"\n", "%s:4:3: unknown key deploy.target, did you mean deploy.targets?"},
+		{"config.ini", "[profiles prod]\nprot = 80\n", "%s:2:1: unknown key profiles.prod.prot"},
+		{"config.json", `{"colour": "red"}`, "%s:1:2: unknown key colour"},
+	}
+	for _, test := range tests {
+		path := writeConfig(t, test.name, test.content)
+		_, err := newParser(path).ParseArgs([]string{"serve"})
+		expected := strings.ReplaceAll(test.expected, "%s", path)
+		if err == nil || err.Error() != expected {
+			t.Errorf("Expected %q, got %v", expected, err)
+		}
+	}
+
+	// Test case 3: Mistyped values are validation errors about the argument
+	path := writeConfig(t, "config.toml", "[serve]\n  workers = \"many\"\n")
+	parser := newParser(path)
+	_, err := parser.ParseArgs(nil)
+	var argErr *uargs.ArgError
+	expected := path + `:2:3: serve.workers: expected int, got "many"`
+	if !errors.As(err, &argErr) || argErr.Name != "workers" || err.Error() != expected || parser.ExitCode(err) != 3 {
+		t.Errorf("Expected %q as a validation error, got %v", expected, err)
+	}
+	path = writeConfig(t, "config.json", `{"deploy": {"targets": ["a", "b", "c"]}, "pin": "s3cret"}`)
+	if _, err = newParser(path).ParseArgs(nil); err == nil || !strings.Contains(err.Error(), "deploy.targets: expected at most 2 values, got 3") {
+		t.Errorf("Expected an error about the number of targets, got %v", err)
+	}
+	path = writeConfig(t, "config.json", `{"pin": "s3cret"}`)
+	if _, err = newParser(path).ParseArgs(nil); err == nil || strings.Contains(err.Error(), "s3cret") || !strings.Contains(err.Error(), "pin: expected int") {
+		t.Errorf("Expected a type error with the PIN redacted, got %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package uargs
+
+import (
+	"sort"
+	"strings"
+)
+
+// MatchKind describes what a SearchResult refers to
+type MatchKind string
+
+const (
+	// MatchFlag marks a result that refers to an argument
+	MatchFlag MatchKind = "flag"
+	// MatchCommand marks a result that refers to a subcommand
+	MatchCommand MatchKind = "command"
+)
+
+// SearchResult is a single ranked match returned by Search
+type SearchResult struct {
+	// Kind tells what the match refers to
+	Kind MatchKind
+	// Name is the long name of the matched item
+	Name string
+	// Usage is the description of the matched item
+	Usage string
+	// Score ranks the match; higher is better
+	Score int
+}
+
+// Scores used to rank search matches, from strongest to weakest
+const (
+	scoreExact       = 100
+	scoreShort       = 90
+	scorePrefix      = 80
+	scoreContains    = 60
+	scoreUsage       = 30
+	scoreSubsequence = 10
+)
+
+// Search returns the arguments and subcommands matching query, ranked by
+// relevance. Names, short names, and command aliases are matched first, then
+// usage text, and finally loose subsequence matches (e.g. "tmo" matches
+// "timeout"). Matching is case-insensitive. Nested commands are named by their
+// path below p, as in "remote add", and Hidden commands are left out.
+//
+// Example:
+//
+//	for _, r := range parser.Search("timeout") {
+//		fmt.Printf("--%s\t%s\n", r.Name, r.Usage)
+//	}
+func (p *Parser) Search(query string) []SearchResult {
+	if p == nil {
+		return nil
+	}
+	query = strings.ToLower(strings.TrimSpace(strings.TrimLeft(query, "-")))
+	if query == "" {
+		return nil
+	}
+
+	var results []SearchResult
+	for name, def := range p.defs {
+		score := matchScore(query, name, []string{def.Short}, def.Usage)
+		if score > 0 {
+			results = append(results, SearchResult{Kind: MatchFlag, Name: name, Usage: def.Usage, Score: score})
+		}
+	}
+	results = p.searchCommands(query, "", results)
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
+
+// searchCommands appends the visible subcommands of p matching query to
+// results, recursively, naming each with prefix before its name
+func (p *Parser) searchCommands(query, prefix string, results []SearchResult) []SearchResult {
+	for _, c := range p.visibleCommands() {
+		name := prefix + c.command.Name
+		score := matchScore(query, c.command.Name, c.command.Aliases, c.command.Usage)
+		if score > 0 {
+			results = append(results, SearchResult{Kind: MatchCommand, Name: name, Usage: c.command.Usage, Score: score})
+		}
+		results = c.searchCommands(query, name+" ", results)
+	}
+	return results
+}
+
+// matchScore rates how well a lower-cased query matches an item with the given
+// name, aliases, and usage text. A score of 0 means no match.
+func matchScore(query, name string, aliases []string, usage string) int {
+	name = strings.ToLower(name)
+	switch {
+	case name == query:
+		return scoreExact
+	case containsFold(aliases, query):
+		return scoreShort
+	case strings.HasPrefix(name, query):
+		return scorePrefix
+	case strings.Contains(name, query):
+		return scoreContains
+	case strings.Contains(strings.ToLower(usage), query):
+		return scoreUsage
+	case isSubsequence(query, name):
+		return scoreSubsequence
+	}
+	return 0
+}
+
+// containsFold reports whether list holds s, ignoring case
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if item != "" && strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSubsequence reports whether all characters of sub appear in s in order
+func isSubsequence(sub, s string) bool {
+	j := 0
+	for i := 0; i < len(s) && j < len(sub); i++ {
+		if s[i] == sub[j] {
+			j++
+		}
+	}
+	return j == len(sub)
+}
@@ -0,0 +1,54 @@
+package uargs_test
+
+import (
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestSearch tests ranking of flag matches
+func TestSearch(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "timeout", Short: "t", Usage: "Request timeout in seconds", Type: uargs.Int},
+		{Name: "connect-timeout", Usage: "Connection timeout", Type: uargs.Int},
+		{Name: "retries", Short: "r", Usage: "Retries before timing out", Type: uargs.Int},
+		{Name: "verbose", Short: "v", Usage: "Enable verbose output", Type: uargs.String},
+	}
+	parser := uargs.NewParser(args)
+
+	results := parser.Search("timeout")
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %+v", results)
+	}
+	if results[0].Name != "timeout" || results[1].Name != "connect-timeout" {
+		t.Errorf("Expected timeout before connect-timeout, got %+v", results)
+	}
+
+	results = parser.Search("--TIMING")
+	if len(results) != 1 || results[0].Name != "retries" {
+		t.Errorf("Expected usage match on retries, got %+v", results)
+	}
+
+	results = parser.Search("v")
+	if len(results) == 0 || results[0].Name != "verbose" {
+		t.Errorf("Expected short name match on verbose first, got %+v", results)
+	}
+
+	if results := parser.Search("  "); results != nil {
+		t.Errorf("Expected no results for empty query, got %+v", results)
+	}
+
+	remote := parser.AddCommand(uargs.Command{Name: "remote", Usage: "Manage remotes"})
+	remote.AddCommand(uargs.Command{Name: "remove", Aliases: []string{"rm"}, Usage: "Remove a remote"})
+	parser.AddCommand(uargs.Command{Name: "remake", Usage: "Internal", Hidden: true})
+
+	results = parser.Search("rm")
+	if len(results) == 0 || results[0].Kind != uargs.MatchCommand || results[0].Name != "remote remove" || results[0].Usage != "Remove a remote" {
+		t.Errorf("Expected an alias match on remote remove first, got %+v", results)
+	}
+
+	results = parser.Search("rem")
+	if len(results) != 2 || results[0].Name != "remote" || results[1].Name != "remote remove" {
+		t.Errorf("Expected remote and remote remove without hidden commands, got %+v", results)
+	}
+}
@@ -0,0 +1,134 @@
+package uargs
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// redacted replaces the values of Secret arguments in errors, usage, debug
+// output, and dumps
+const redacted = "[redacted]"
+
+// SecretProvider looks up the values of Secret arguments, such as tokens kept
+// in the OS credential store, when they are not given (see WithSecrets)
+type SecretProvider interface {
+	// Secret returns the secret stored under key: the argument name, prefixed
+	// with the command path for subcommands, as in "deploy.api-token". It
+	// returns false if there is none.
+	Secret(key string) (string, bool, error)
+}
+
+// SecretProviderFunc adapts a function to the SecretProvider interface
+type SecretProviderFunc func(key string) (string, bool, error)
+
+// Secret calls f(key)
+func (f SecretProviderFunc) Secret(key string) (string, bool, error) {
+	return f(key)
+}
+
+// keyring reads secrets from the OS credential store
+type keyring struct {
+	service string
+}
+
+// Keyring returns a SecretProvider that reads generic passwords of service
+// from the OS credential store, with the key as the account name: the login
+// keychain on macOS (through security) and the Secret Service on Linux and
+// other Unix systems (through secret-tool). Secrets are missing where the
+// store or its tool is not available, including Windows.
+//
+// Secrets are stored with the platform tools, such as:
+//
+//	security add-generic-password -s mytool -a api-token -w
+//	secret-tool store --label "mytool api-token" service mytool account api-token
+func Keyring(service string) SecretProvider {
+	return keyring{service: service}
+}
+
+// Secret runs the platform tool to look up key
+func (k keyring) Secret(key string) (string, bool, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", k.service, "-a", key, "-w")
+	case "windows", "plan9", "js", "wasip1":
+		return "", false, nil
+	default:
+		cmd = exec.Command("secret-tool", "lookup", "service", k.service, "account", key)
+	}
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if errors.Is(err, exec.ErrNotFound) || errors.As(err, &exitErr) {
+		// The tool is missing, or the secret is not stored
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	secret := strings.TrimSuffix(string(out), "\n")
+	return secret, secret != "", nil
+}
+
+// secretSource looks up Secret arguments with the provider set with
+// WithSecrets
+type secretSource struct {
+	p *Parser
+}
+
+// Lookup returns the secret of def if it is a Secret argument, under the first
+// of its keys that the provider has
+func (s *secretSource) Lookup(path []string, def ArgDef) (SourceValue, bool, error) {
+	if !def.Secret {
+		return SourceValue{}, false, nil
+	}
+	for _, key := range s.p.configKeys(def.Name) {
+		secret, ok, err := s.p.secrets.Secret(key)
+		if err != nil || ok {
+			return SourceValue{Args: []string{secret}, Source: Source{Origin: OriginSecret, Key: key}}, ok, err
+		}
+	}
+	return SourceValue{}, false, nil
+}
+
+// redactValue returns v, or redacted if def is a Secret argument and v is set
+func redactValue(def ArgDef, v interface{}) interface{} {
+	if def.Secret && v != nil {
+		return redacted
+	}
+	return v
+}
+
+// redactError replaces values, the raw or typed values of the Secret argument
+// def, in the message of err. Since callbacks such as Transform and Validate
+// may quote the value in any form, the error chain is dropped when the message
+// changes, keeping only its classification. Errors of other arguments are
+// returned unchanged.
+func redactError(def ArgDef, err error, values ...interface{}) error {
+	if err == nil || !def.Secret {
+		return err
+	}
+	var secrets []string
+	for _, v := range values {
+		args, _ := rawValues(v)
+		secrets = append(secrets, args...)
+	}
+	// Longer values first, so that a value containing another is replaced whole
+	slices.SortFunc(secrets, func(a, b string) int { return len(b) - len(a) })
+	msg := err.Error()
+	for _, s := range secrets {
+		if s != "" {
+			msg = strings.ReplaceAll(msg, s, redacted)
+		}
+	}
+	if msg == err.Error() {
+		return err
+	}
+	var classified *classifiedError
+	if errors.As(err, &classified) {
+		return classify(classified.kind, errors.New(msg))
+	}
+	return errors.New(msg)
+}
@@ -0,0 +1,223 @@
+package uargs_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestSecrets tests resolving Secret arguments from a SecretProvider
+func TestSecrets(t *testing.T) {
+	store := map[string]string{
+		"api-token": "s3cret",
+		"host":      "store.example.com",
+	}
+	var lookups []string
+	provider := uargs.SecretProviderFunc(func(key string) (string, bool, error) {
+		lookups = append(lookups, key)
+		secret, ok := store[key]
+		return secret, ok, nil
+	})
+	parser := uargs.NewParser([]uargs.ArgDef{
+		{Name: "api-token", Usage: "API token", Type: uargs.String, Secret: true, Required: true},
+		{Name: "host", Usage: "Host", Type: uargs.String, Default: "localhost"},
+	}, uargs.WithSecrets(provider))
+
+	// Test case 1: Secret arguments are taken from the provider, other arguments are not
+	parsed, err := parser.ParseArgs(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parsed["api-token"] != "s3cret" || parsed["host"] != "localhost" {
+		t.Errorf("Expected the token from the provider and the default host, got %v", parsed)
+	}
+	if src := parser.Source("api-token"); src != (uargs.Source{Origin: uargs.OriginSecret, Key: "api-token"}) {
+		t.Errorf("Expected a secret source, got %+v", src)
+	}
+	for _, key := range lookups {
+		if key == "host" {
+			t.Errorf("Expected no lookup of host, which is not secret")
+		}
+	}
+
+	// Test case 2: The command line and environment take precedence
+	parsed, err = parser.ParseArgs([]string{"--api-token", "cli"})
+	if err != nil || parsed["api-token"] != "cli" {
+		t.Errorf("Expected the token from the command line, got %v (%v)", parsed["api-token"], err)
+	}
+	t.Setenv("API_TOKEN", "env")
+	parser = uargs.NewParser([]uargs.ArgDef{
+		{Name: "api-token", Usage: "API token", Type: uargs.String, Secret: true, Env: "API_TOKEN"},
+	}, uargs.WithSecrets(provider))
+	parsed, err = parser.ParseArgs(nil)
+	if err != nil || parsed["api-token"] != "env" {
+		t.Errorf("Expected the token from the environment, got %v (%v)", parsed["api-token"], err)
+	}
+
+	// Test case 3: Subcommands look up keys prefixed with their path, then inherited keys
+	parser = uargs.NewParser([]uargs.ArgDef{
+		{Name: "api-token", Usage: "API token", Type: uargs.String, Secret: true, Persistent: true},
+	}, uargs.WithSecrets(provider))
+	status := parser.AddCommand(uargs.Command{Name: "status", Usage: "Show the status"})
+	deploy := parser.AddCommand(uargs.Command{Name: "deploy", Usage: "Deploy", Args: []uargs.ArgDef{
+		{Name: "key", Usage: "Deploy key", Type: uargs.String, Secret: true},
+	}})
+	store["deploy.key"] = "deploy-key"
+	if _, err := parser.ParseArgs([]string{"deploy"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if key := deploy.Result().Map()["key"]; key != "deploy-key" {
+		t.Errorf("Expected the deploy key from the provider, got %v", key)
+	}
+	if _, err := parser.ParseArgs([]string{"status"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if token := status.Result().Map()["api-token"]; token != "s3cret" {
+		t.Errorf("Expected the inherited token from the provider, got %v", token)
+	}
+
+	// Test case 4: Provider errors are reported for the argument
+	failing := uargs.SecretProviderFunc(func(key string) (string, bool, error) {
+		return "", false, errors.New("keyring locked")
+	})
+	parser = uargs.NewParser([]uargs.ArgDef{
+		{Name: "api-token", Usage: "API token", Type: uargs.String, Secret: true},
+	}, uargs.WithSecrets(failing))
+	_, err = parser.ParseArgs(nil)
+	var argErr *uargs.ArgError
+	if !errors.As(err, &argErr) || argErr.Name != "api-token" || !strings.Contains(err.Error(), "keyring locked") {
+		t.Errorf("Expected the provider error for --api-token, got %v", err)
+	}
+}
+
+// TestKeyring tests reading secrets with the OS credential store tools
+func TestKeyring(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Keyring is not supported on Windows")
+	}
+	tool := "secret-tool"
+	if runtime.GOOS == "darwin" {
+		tool = "security"
+	}
+	dir := t.TempDir()
+	t.Setenv("PATH", dir)
+	keyring := uargs.Keyring("mytool")
+
+	// Test case 1: A missing tool means no secret
+	if secret, ok, err := keyring.Secret("api-token"); ok || err != nil {
+		t.Errorf("Expected no secret without %s, got %q, %v, %v", tool, secret, ok, err)
+	}
+
+	// Test case 2: The tool is asked for the service and account
+	script := "#!/bin/sh\nif [ \"$3\" = mytool ] && [ \"$5\" = api-token ]; then echo s3cret; exit 0; fi\nexit 1\n"
+	if err := os.WriteFile(filepath.Join(dir, tool), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if secret, ok, err := keyring.Secret("api-token"); secret != "s3cret" || !ok || err != nil {
+		t.Errorf("Expected s3cret, got %q, %v, %v", secret, ok, err)
+	}
+	if secret, ok, err := keyring.Secret("other"); ok || err != nil {
+		t.Errorf("Expected no secret for another account, got %q, %v, %v", secret, ok, err)
+	}
+
+	// Test case 3: The parser resolves Secret arguments from the keyring
+	parser := uargs.NewParser([]uargs.ArgDef{
+		{Name: "api-token", Usage: "API token", Type: uargs.String, Secret: true, Required: true},
+	}, uargs.WithSecrets(keyring))
+	parsed, err := parser.ParseArgs(nil)
+	if err != nil || parsed["api-token"] != "s3cret" {
+		t.Errorf("Expected the token from the keyring, got %v (%v)", parsed, err)
+	}
+}
+
+// TestSecretRedaction tests that the values of Secret arguments are kept out
+// of errors, usage, debug output, and dumps
+func TestSecretRedaction(t *testing.T) {
+	var logs, dump bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	config := writeConfig(t, "config.json", `{"password": "from-config", "user": "alice"}`)
+	args := []uargs.ArgDef{
+		{Name: "pin", Usage: "PIN", Type: uargs.Int, Secret: true, Default: 1234},
+		{Name: "password", Usage: "Password", Type: uargs.String, Secret: true, Transform: func(s string) (string, error) {
+			return "", fmt.Errorf("%q is too weak", s)
+		}},
+		{Name: "user", Usage: "User", Type: uargs.String},
+	}
+	parser := uargs.NewParser(args, uargs.WithLogger(logger), uargs.WithPrintFlags(&dump))
+
+	// Test case 1: Invalid values are redacted from errors, including callback errors
+	_, err := parser.ParseArgs([]string{"--pin", "hunter2"})
+	if err == nil || strings.Contains(err.Error(), "hunter2") || !strings.Contains(err.Error(), "[redacted]") {
+		t.Errorf("Expected an error with the PIN redacted, got %v", err)
+	}
+	_, err = parser.ParseArgs([]string{"--pin", "1", "--password", "s3cret"})
+	if err == nil || strings.Contains(err.Error(), "s3cret") {
+		t.Errorf("Expected an error with the password redacted, got %v", err)
+	}
+	if strings.Contains(logs.String(), "hunter2") || strings.Contains(logs.String(), "s3cret") {
+		t.Errorf("Expected no secrets in the debug output, got %s", logs.String())
+	}
+
+	// Test case 2: Errors of other arguments keep their values
+	parser = uargs.NewParser([]uargs.ArgDef{
+		{Name: "count", Usage: "Count", Type: uargs.Int},
+	})
+	if _, err := parser.ParseArgs([]string{"--count", "many"}); err == nil || !strings.Contains(err.Error(), "many") {
+		t.Errorf("Expected the value in the error, got %v", err)
+	}
+
+	// Test case 3: Usage, --print-flags, config errors, and Result.Config redact secrets
+	parser = uargs.NewParser(args[:1], uargs.WithPrintFlags(&dump))
+	if usage := parser.Usage(); strings.Contains(usage, "1234") || !strings.Contains(usage, "(default: [redacted])") {
+		t.Errorf("Expected the default PIN redacted in usage, got %q", usage)
+	}
+	if _, err := parser.ParseArgs([]string{"--pin", "4321", "--print-flags"}); !errors.Is(err, uargs.ErrPrintFlags) {
+		t.Fatalf("Expected ErrPrintFlags, got %v", err)
+	}
+	if strings.Contains(dump.String(), "1234") || strings.Contains(dump.String(), "4321") {
+		t.Errorf("Expected the PIN redacted in the dump, got %s", dump.String())
+	}
+	parser = uargs.NewParser(args[1:], uargs.WithConfigFile(config))
+	if _, err := parser.ParseArgs(nil); err == nil || strings.Contains(err.Error(), "from-config") {
+		t.Errorf("Expected an error with the config password redacted, got %v", err)
+	}
+	parser = uargs.NewParser([]uargs.ArgDef{
+		{Name: "password", Usage: "Password", Type: uargs.String, Secret: true},
+		{Name: "user", Usage: "User", Type: uargs.String},
+	}, uargs.WithConfigFile(config))
+	parsed, err := parser.ParseArgs(nil)
+	if err != nil || parsed["password"] != "from-config" {
+		t.Fatalf("Expected the password from the config file, got %v (%v)", parsed, err)
+	}
+	values := parser.Result().Config()
+	if got := values["password"].Values; len(got) != 1 || got[0] != "[redacted]" {
+		t.Errorf("Expected the config password redacted, got %v", got)
+	}
+	if got := values["user"].Values; len(got) != 1 || got[0] != "alice" {
+		t.Errorf("Expected the config user alice, got %v", got)
+	}
+
+	// Test case 4: Malformed secrets in config files are left out of errors
+	for name, content := range map[string]string{
+		"config.toml": "password = hunter2secret",
+		"app.conf":    "password = 'x' hunter2secret",
+		"pin.toml":    `pin = "hunter2secret"`,
+	} {
+		parser = uargs.NewParser([]uargs.ArgDef{
+			{Name: "pin", Usage: "PIN", Type: uargs.Int, Secret: true},
+			{Name: "password", Usage: "Password", Type: uargs.String, Secret: true},
+		}, uargs.WithConfigFile(writeConfig(t, name, content)))
+		_, err := parser.ParseArgs(nil)
+		if err == nil || strings.Contains(err.Error(), "hunter2secret") {
+			t.Errorf("Expected an error without the secret for %s, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package uargs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SelfTestName is the name of the hidden flag registered by WithSelfTest
+const SelfTestName = "self-test"
+
+// WithSelfTest registers a hidden --self-test flag. When it is given, Parse
+// runs SelfTest on the whole command tree, writes its report to w (os.Stdout
+// if nil), and returns ErrSelfTest, or an error listing the problems found.
+// Release pipelines can run the built binary with --self-test to smoke-test
+// its command-line surface. An argument named self-test takes precedence over
+// the built-in flag.
+//
+// Example:
+//
+//	parser := uargs.NewParser(args, uargs.WithSelfTest(nil))
+//	if _, err := parser.Parse(); errors.Is(err, uargs.ErrSelfTest) {
+//		os.Exit(0)
+//	}
+func WithSelfTest(w io.Writer) Option {
+	return func(p *Parser) {
+		if w == nil {
+			w = os.Stdout
+		}
+		p.selfTest = w
+	}
+}
+
+// selfTestChecks are the checks run by SelfTest, in order. Each returns the
+// problems found in one parser of the command tree.
+var selfTestChecks = []struct {
+	name string
+	run  func(p *Parser) []error
+}{
+	{"definitions", (*Parser).lintDefs},
+	{"examples", (*Parser).checkExamples},
+	{"completion", (*Parser).checkHints},
+	{"sources", (*Parser).checkSources},
+}
+
+// SelfTest checks the command-line surface of p and its subcommands, so that
+// packagers can smoke-test a release binary in one command: the argument and
+// command definitions are linted, the Example of each argument is validated,
+// the Hints of every command are checked to offer each flag and choice, and
+// the values of absent arguments are resolved from environment variables, .env
+// files, config files, other value sources, and defaults. A report with one
+// line per check is written to w, if not nil, and the problems found are
+// returned joined.
+//
+// Example:
+//
+//	if err := parser.SelfTest(os.Stdout); err != nil {
+//		os.Exit(1)
+//	}
+func (p *Parser) SelfTest(w io.Writer) error {
+	if p == nil {
+		return ErrNilParser
+	}
+	var all []error
+	for _, check := range selfTestChecks {
+		var problems []error
+		if p.defErr == nil || check.name == "definitions" {
+			p.walk(func(q *Parser) {
+				problems = append(problems, check.run(q)...)
+			})
+		}
+		if w != nil {
+			status := "ok"
+			switch {
+			case p.defErr != nil && check.name != "definitions":
+				status = "skipped"
+			case len(problems) > 0:
+				status = "FAILED"
+			}
+			fmt.Fprintf(w, "%-12s %s\n", check.name, status)
+			for _, err := range problems {
+				fmt.Fprintf(w, "  %v\n", err)
+			}
+		}
+		all = append(all, problems...)
+	}
+	return errors.Join(all...)
+}
+
+// isSelfTest reports whether argv requests the --self-test report
+func (p *Parser) isSelfTest(argv []string) bool {
+	if p.selfTest == nil {
+		return false
+	}
+	if _, defined := p.defs[SelfTestName]; defined {
+		return false
+	}
+	end := slices.Index(argv, "--")
+	if end < 0 {
+		end = len(argv)
+	}
+	return slices.Contains(argv[:end], "--"+SelfTestName)
+}
+
+// runSelfTest writes the --self-test report. It returns ErrSelfTest if no
+// problems were found.
+func (p *Parser) runSelfTest() error {
+	if err := p.SelfTest(p.selfTest); err != nil {
+		return fmt.Errorf("self-test failed: %w", err)
+	}
+	return ErrSelfTest
+}
+
+// walk calls fn for p and each of its subcommands, depth first
+func (p *Parser) walk(fn func(q *Parser)) {
+	fn(p)
+	for _, c := range p.commands {
+		c.walk(fn)
+	}
+}
+
+// problemf formats a self-test problem found in p, prefixed with the command
+// path for subcommands
+func (p *Parser) problemf(format string, a ...interface{}) error {
+	msg := fmt.Sprintf(format, a...)
+	if path := p.path(); len(path) > 0 {
+		msg = strings.Join(path, " ") + ": " + msg
+	}
+	return errors.New(msg)
+}
+
+// ownDefs returns the arguments defined by p itself, in definition order,
+// leaving out the persistent ones inherited from its parent
+func (p *Parser) ownDefs() []ArgDef {
+	var defs []ArgDef
+	for _, name := range p.order {
+		if !p.inheritedFlags[name] {
+			defs = append(defs, p.defs[name])
+		}
+	}
+	return defs
+}
+
+// lintDefs reports definition errors and arguments and commands without
+// Usage text, which would leave gaps in help
+func (p *Parser) lintDefs() []error {
+	var problems []error
+	if p.parent == nil && p.defErr != nil {
+		problems = append(problems, p.defErr)
+	}
+	if p.parent != nil && p.command.Usage == "" && !p.command.Hidden {
+		problems = append(problems, p.problemf("command has no Usage text"))
+	}
+	for _, def := range p.ownDefs() {
+		if def.Usage == "" {
+			problems = append(problems, p.problemf("%s has no Usage text", flagName(def)))
+		}
+	}
+	return problems
+}
+
+// checkExamples reports Examples that do not use their argument or whose
+// values would be rejected
+func (p *Parser) checkExamples() []error {
+	var problems []error
+	for _, def := range p.ownDefs() {
+		if def.Example == "" {
+			continue
+		}
+		tokens, err := Split(def.Example)
+		if err == nil && (len(tokens) == 0 || (tokens[0] != "--"+def.Name && (def.Short == "" || tokens[0] != "-"+def.Short))) {
+			err = fmt.Errorf("does not start with --%s", def.Name)
+		}
+		if err == nil {
+			err = checkExampleValues(def, tokens[1:])
+		}
+		if err != nil {
+			problems = append(problems, p.problemf("example of --%s %q: %v", def.Name, def.Example, err))
+		}
+	}
+	return problems
+}
+
+// checkExampleValues verifies the number, type, and Choices of the values in
+// an Example, without touching files. Values that are transformed before
+// conversion are only checked by number.
+func checkExampleValues(def ArgDef, values []string) error {
+	if n := maxArgs(def); n >= 0 && len(values) > n {
+		return fmt.Errorf("expected at most %d values, got %d", n, len(values))
+	}
+	if len(values) < def.MinArgs {
+		return fmt.Errorf("expected at least %d values, got %d", def.MinArgs, len(values))
+	}
+	if def.Transform != nil {
+		return nil
+	}
+	for _, s := range values {
+		var err error
+		switch valueType(def) {
+		case Int:
+			_, err = strconv.Atoi(s)
+		case Float:
+			_, err = strconv.ParseFloat(s, 64)
+		}
+		if err != nil {
+			return fmt.Errorf("expected %s, got %q", valueType(def), s)
+		}
+		if len(def.Choices) > 0 {
+			if _, err := checkChoice(def, s); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkHints reports the command, arguments, and choices of p missing from the
+// Hints of the root parser for lines that select p. Only Hints is exercised, as
+// no shell completion scripts are generated.
+func (p *Parser) checkHints() (problems []error) {
+	defer func() {
+		if r := recover(); r != nil {
+			problems = append(problems, p.problemf("completion failed: %v", r))
+		}
+	}()
+	root := p
+	for root.parent != nil {
+		root = root.parent
+	}
+	prefix := ""
+	if path := p.path(); len(path) > 0 {
+		parentLine := strings.Join(path[:len(path)-1], " ")
+		if parentLine != "" {
+			parentLine += " "
+		}
+		if slices.Contains(p.parent.visibleCommands(), p) && !slices.ContainsFunc(root.Hints(parentLine, len(parentLine)), func(h Hint) bool {
+			return h.Kind == HintCommand && h.Name == p.command.Name
+		}) {
+			problems = append(problems, p.problemf("command is not offered for completion"))
+		}
+		prefix = strings.Join(path, " ") + " "
+	}
+	offered := make(map[string]bool)
+	for _, h := range root.Hints(prefix+"-", len(prefix)+1) {
+		offered[h.Name] = true
+	}
+	for _, def := range p.ownDefs() {
+		if def.EnvOnly {
+			continue
+		}
+		if !offered[def.Name] {
+			problems = append(problems, p.problemf("--%s is not offered for completion", def.Name))
+			continue
+		}
+		line := prefix + "--" + def.Name + " "
+		values := make(map[string]bool)
+		for _, h := range root.Hints(line, len(line)) {
+			if h.Kind == HintValue {
+				values[h.Text] = true
+			}
+		}
+		for _, choice := range def.Choices {
+			if !values[choice] {
+				problems = append(problems, p.problemf("choice %q of --%s is not offered for completion", choice, def.Name))
+			}
+		}
+	}
+	return problems
+}
+
+// checkSources resolves the values of the absent arguments of p, without
+// changing its last result
+func (p *Parser) checkSources() []error {
+	s := *p
+	s.parsed = make(map[string]interface{})
+	s.result, s.done = nil, false
+	if err := s.fillMissing(); err != nil {
+		return []error{p.problemf("%v", err)}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package uargs_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestSelfTest tests smoke-testing the command-line surface with SelfTest and
+// the --self-test flag
+func TestSelfTest(t *testing.T) {
+	newParser := func(out *bytes.Buffer, workersExample string, opts ...uargs.Option) *uargs.Parser {
+		opts = append(opts, uargs.WithSelfTest(out))
+		parser := uargs.NewParser([]uargs.ArgDef{
+			{Name: "format", Short: "f", Usage: "Output format", Choices: []string{"json", "text"}, Default: "text", Example: "-f json"},
+			{Name: "coords", Usage: "Coordinates", Type: uargs.Float, NumArgs: 2, Example: "--coords 10.5 20.3"},
+			{Name: "user", Usage: "User name", Type: uargs.String, Required: true},
+			{Name: "token", Usage: "API token", Type: uargs.String, EnvOnly: true},
+		}, opts...)
+		parser.AddCommand(uargs.Command{Name: "serve", Usage: "Serve", Args: []uargs.ArgDef{
+			{Name: "workers", Usage: "Workers", Type: uargs.Int, Example: workersExample},
+		}})
+		return parser
+	}
+
+	// Test case 1: A sound definition passes, despite the missing required argument
+	var out bytes.Buffer
+	parser := newParser(&out, "--workers 4")
+	_, err := parser.ParseArgs([]string{"--self-test"})
+	if !errors.Is(err, uargs.ErrSelfTest) || parser.ExitCode(err) != 0 {
+		t.Fatalf("Expected ErrSelfTest, got %v", err)
+	}
+	expected := "definitions  ok\nexamples     ok\ncompletion   ok\nsources      ok\n"
+	if out.String() != expected {
+		t.Errorf("Expected report %q, got %q", expected, out.String())
+	}
+
+	// Test case 2: Bad examples and missing Usage text are reported
+	out.Reset()
+	parser = newParser(&out, "--workers many")
+	parser.AddCommand(uargs.Command{Name: "stop", Args: []uargs.ArgDef{
+		{Name: "force", Type: uargs.String, Example: "--forec yes"},
+	}})
+	_, err = parser.ParseArgs([]string{"serve", "--self-test"})
+	if err == nil || errors.Is(err, uargs.ErrSelfTest) || parser.ExitCode(err) != 2 {
+		t.Fatalf("Expected the self-test to fail, got %v", err)
+	}
+	for _, problem := range []string{
+		"definitions  FAILED\n  stop: command has no Usage text\n  stop: --force has no Usage text\n",
+		`serve: example of --workers "--workers many": expected int, got "many"`,
+		`stop: example of --force "--forec yes": does not start with --force`,
+		"completion   ok\n",
+	} {
+		if !strings.Contains(out.String(), problem) {
+			t.Errorf("Expected the report to contain %q, got:\n%s", problem, out.String())
+		}
+	}
+
+	// Test case 3: Failing defaults, environment variables, and config files are
+	// reported by the sources check
+	out.Reset()
+	parser = uargs.NewParser([]uargs.ArgDef{{Name: "workers", Usage: "Workers", Type: uargs.Int, DefaultFunc: func() (interface{}, error) {
+		return nil, errors.New("no CPU count")
+	}}})
+	err = parser.SelfTest(&out)
+	if err == nil || !strings.Contains(err.Error(), "no CPU count") || !strings.Contains(out.String(), "sources      FAILED\n") {
+		t.Errorf("Expected the default problem in the sources check, got %v:\n%s", err, out.String())
+	}
+
+	out.Reset()
+	t.Setenv("SELFTEST_WORKERS", "many")
+	parser = uargs.NewParser([]uargs.ArgDef{{Name: "workers", Usage: "Workers", Type: uargs.Int, Env: "SELFTEST_WORKERS"}})
+	if err = parser.SelfTest(&out); err == nil || !strings.Contains(err.Error(), "SELFTEST_WORKERS") || !strings.Contains(out.String(), "sources      FAILED\n") {
+		t.Errorf("Expected the environment problem in the sources check, got %v:\n%s", err, out.String())
+	}
+
+	out.Reset()
+	config := writeConfig(t, "config.json", `{"workers": "many"}`)
+	parser = uargs.NewParser([]uargs.ArgDef{{Name: "workers", Usage: "Workers", Type: uargs.Int}}, uargs.WithConfigFile(config))
+	if err = parser.SelfTest(&out); err == nil || !strings.Contains(err.Error(), "workers") || !strings.Contains(out.String(), "sources      FAILED\n") {
+		t.Errorf("Expected the config problem in the sources check, got %v:\n%s", err, out.String())
+	}
+
+	// Test case 4: Definition errors are reported and the other checks skipped
+	out.Reset()
+	parser = uargs.NewParser([]uargs.ArgDef{{Name: "port", Usage: "Port", Type: uargs.Int, Default: "high"}}, uargs.WithSelfTest(&out))
+	if _, err = parser.ParseArgs([]string{"--self-test"}); err == nil || !strings.HasPrefix(out.String(), "definitions  FAILED\n") || !strings.Contains(out.String(), "examples     skipped\n") {
+		t.Errorf("Expected the definition error in the report, got %v:\n%s", err, out.String())
+	}
+
+	// Test case 5: Subcommand parsers inherit the flag
+	out.Reset()
+	serve := uargs.NewParser(nil, uargs.WithSelfTest(&out)).AddCommand(uargs.Command{Name: "serve", Usage: "Serve"})
+	if _, err = serve.ParseArgs([]string{"--self-test"}); !errors.Is(err, uargs.ErrSelfTest) {
+		t.Errorf("Expected ErrSelfTest from the subcommand parser, got %v", err)
+	}
+
+	// Test case 6: Without WithSelfTest, --self-test is an unknown argument
+	if _, err = uargs.NewParser(nil).ParseArgs([]string{"--self-test"}); err == nil || !strings.HasPrefix(err.Error(), "unknown argument --self-test") {
+		t.Errorf("Expected an unknown argument error, got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package uargs
+
+import (
+	"errors"
+	"strings"
+)
+
+// Split breaks a command line into arguments the way a POSIX shell would,
+// without expanding variables or globs. Single quotes preserve text literally,
+// double quotes allow \" \\ \$ and \` escapes, and a backslash outside quotes
+// escapes the next character.
+//
+// Example:
+//
+//	argv, err := uargs.Split(`--name "Jane Doe" --tag 'a b'`)
+//	// argv is ["--name", "Jane Doe", "--tag", "a b"]
+func Split(line string) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	inArg := false
+	quote := rune(0)
+	escaped := false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			if quote == '"' && !strings.ContainsRune(`"\$`+"`", r) {
+				cur.WriteRune('\\')
+			}
+			cur.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if escaped {
+		return nil, errors.New("unterminated escape at end of line")
+	}
+	if quote != 0 {
+		return nil, errors.New("unterminated quote " + string(quote))
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,41 @@
+package uargs_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestSplit tests shell-style tokenizing of command lines
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"", nil},
+		{"  --a   1 ", []string{"--a", "1"}},
+		{`--name "Jane Doe"`, []string{"--name", "Jane Doe"}},
+		{`--tag 'a "b" c'`, []string{"--tag", `a "b" c`}},
+		{`one\ arg two`, []string{"one arg", "two"}},
+		{`"say \"hi\"" "a\b"`, []string{`say "hi"`, `a\b`}},
+		{`--empty ""`, []string{"--empty", ""}},
+		{`x'y'"z"`, []string{"xyz"}},
+	}
+	for _, tt := range tests {
+		got, err := uargs.Split(tt.line)
+		if err != nil {
+			t.Errorf("Split(%q): unexpected error %v", tt.line, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Split(%q): expected %q, got %q", tt.line, tt.want, got)
+		}
+	}
+
+	for _, line := range []string{`--name "Jane`, `'open`, `trailing\`} {
+		if _, err := uargs.Split(line); err == nil {
+			t.Errorf("Split(%q): expected an error", line)
+		}
+	}
+}
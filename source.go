@@ -0,0 +1,147 @@
+package uargs
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ValueSource provides values for arguments that were not given on the
+// command line, such as environment variables, config files, or a company
+// configuration service. The built-in environment, profile, config file, and
+// secret sources implement it too. Sources added with WithSource are
+// consulted after the built-in ones, unless reordered with WithPrecedence, in
+// the order they were added, and before Default.
+type ValueSource interface {
+	// Lookup returns the value of def, an argument of the command at path (nil
+	// for the root parser), and whether the source has one. The value is
+	// converted and checked like a command-line value. An error fails the
+	// parse.
+	Lookup(path []string, def ArgDef) (SourceValue, bool, error)
+}
+
+// SourceValue is a value found by a ValueSource
+type SourceValue struct {
+	// Args are the raw values, as they would be given on the command line. Use
+	// Split for sources that hold multi-value arguments as a single string.
+	Args []string
+	// Source describes where the value came from, as reported by
+	// Result.Source. Its Origin defaults to OriginCustom.
+	Source Source
+}
+
+// ValueSourceFunc adapts a function to the ValueSource interface
+//
+// Example:
+//
+//	vault := uargs.ValueSourceFunc(func(path []string, def uargs.ArgDef) (uargs.SourceValue, bool, error) {
+//		if !def.Secret {
+//			return uargs.SourceValue{}, false, nil
+//		}
+//		secret, err := client.Read("mytool/" + def.Name)
+//		if err != nil || secret == "" {
+//			return uargs.SourceValue{}, false, err
+//		}
+//		return uargs.SourceValue{Args: []string{secret}, Source: uargs.Source{Key: "mytool/" + def.Name}}, true, nil
+//	})
+//	parser := uargs.NewParser(args, uargs.WithSource(vault))
+type ValueSourceFunc func(path []string, def ArgDef) (SourceValue, bool, error)
+
+// Lookup calls f(path, def)
+func (f ValueSourceFunc) Lookup(path []string, def ArgDef) (SourceValue, bool, error) {
+	return f(path, def)
+}
+
+// location describes s for error messages, as in "environment variable
+// MYAPP_PORT" or "config file /etc/mytool.toml:3, key port"
+func (s Source) location() string {
+	switch {
+	case s.Origin == OriginEnv:
+		return "environment variable " + s.Env
+	case s.File != "" && s.Line != 0:
+		return fmt.Sprintf("config file %s:%d, key %s", s.File, s.Line, s.Key)
+	case s.File != "":
+		return fmt.Sprintf("config file %s, key %s", s.File, s.Key)
+	case s.Origin == OriginProfile:
+		return fmt.Sprintf("profile %s, key %s", s.Profile, s.Key)
+	}
+	return s.String()
+}
+
+// defaultPrecedence is the order in which value sources are consulted, unless
+// set with WithPrecedence
+var defaultPrecedence = []Origin{OriginEnv, OriginProfile, OriginConfig, OriginSecret, OriginCustom}
+
+// valueSources returns the sources consulted for arguments without a value, in
+// the order set with WithPrecedence: by default the environment, the profile
+// chosen with --profile, the config files, the secret provider, and the
+// sources added with WithSource. It also returns the config files read.
+func (p *Parser) valueSources() ([]ValueSource, *configSet, error) {
+	dotEnv, err := p.loadDotEnv()
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg, err := p.loadConfig(dotEnv)
+	if err != nil {
+		return nil, nil, err
+	}
+	precedence := p.precedence
+	if precedence == nil {
+		precedence = defaultPrecedence
+	}
+	var sources []ValueSource
+	for _, origin := range precedence {
+		switch {
+		case origin == OriginEnv:
+			sources = append(sources, &envSource{p: p, dotEnv: dotEnv})
+		case origin == OriginProfile && p.profileFlag:
+			sources = append(sources, &profileSource{p: p, cfg: cfg})
+		case origin == OriginConfig:
+			sources = append(sources, &configSource{p: p, cfg: cfg})
+		case origin == OriginSecret && p.secrets != nil:
+			sources = append(sources, &secretSource{p: p})
+		case origin == OriginCustom:
+			sources = append(sources, p.sources...)
+		}
+	}
+	return sources, cfg, nil
+}
+
+// applySources stores the value of the first of sources that has one for
+// every argument without a value. Each source is consulted for all arguments
+// before the next, so that a source can depend on values set by earlier ones.
+// It returns where the values it set came from.
+func (p *Parser) applySources(sources []ValueSource) (map[string]Source, error) {
+	found := make(map[string]Source)
+	path := p.path()
+	for _, src := range sources {
+		for name, def := range p.defs {
+			if _, ok := p.parsed[name]; ok || (p.only != nil && !p.only[name]) {
+				continue
+			}
+			v, ok, err := src.Lookup(slices.Clone(path), def)
+			if err != nil {
+				var argErr *ArgError
+				if !errors.As(err, &argErr) {
+					err = argError(def, err)
+				}
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			if v.Source.Origin == "" {
+				v.Source.Origin = OriginCustom
+			}
+			val, err := p.convert(def, slices.Clone(v.Args))
+			if err != nil {
+				err = fmt.Errorf("%w (from %s)", err, v.Source.location())
+				return nil, argError(def, classify(validationErrorKind, err))
+			}
+			p.debug("source resolved", "flag", name, "source", string(v.Source.Origin))
+			p.parsed[name] = val
+			found[name] = v.Source
+		}
+	}
+	return found, nil
+}
@@ -0,0 +1,152 @@
+package uargs_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestValueSource tests adding custom sources of values
+func TestValueSource(t *testing.T) {
+	config := writeConfig(t, "config.json", `{"host": "config.example.com"}`)
+	store := map[string]string{
+		"host":          "store.example.com",
+		"port":          "8080",
+		"serve.workers": "4",
+		"tags":          "a 'b c'",
+	}
+	var lookups []string
+	remote := uargs.ValueSourceFunc(func(path []string, def uargs.ArgDef) (uargs.SourceValue, bool, error) {
+		key := strings.Join(append(path, def.Name), ".")
+		lookups = append(lookups, key)
+		raw, ok := store[key]
+		if !ok {
+			return uargs.SourceValue{}, false, nil
+		}
+		args, err := uargs.Split(raw)
+		return uargs.SourceValue{Args: args, Source: uargs.Source{Key: key}}, true, err
+	})
+	fallback := uargs.ValueSourceFunc(func(path []string, def uargs.ArgDef) (uargs.SourceValue, bool, error) {
+		if def.Name == "token" {
+			return uargs.SourceValue{Args: []string{"fallback"}, Source: uargs.Source{Origin: "vault", Key: "token"}}, true, nil
+		}
+		return uargs.SourceValue{}, false, nil
+	})
+	parser := uargs.NewParser([]uargs.ArgDef{
+		{Name: "host", Usage: "Host", Type: uargs.String},
+		{Name: "port", Usage: "Port", Type: uargs.Int, Default: 80},
+		{Name: "tags", Usage: "Tags", Type: uargs.String, NumArgs: 2},
+		{Name: "token", Usage: "Token", Type: uargs.String},
+	}, uargs.WithConfigFile(config), uargs.WithSource(remote), uargs.WithSource(fallback))
+	serve := parser.AddCommand(uargs.Command{Name: "serve", Usage: "Serve", Args: []uargs.ArgDef{
+		{Name: "workers", Usage: "Workers", Type: uargs.Int},
+	}})
+
+	// Test case 1: Sources fill what the command line and config files leave open
+	parsed, err := parser.ParseArgs([]string{"serve"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parsed["host"] != "config.example.com" || parsed["port"] != 8080 || parsed["token"] != "fallback" {
+		t.Errorf("Expected values from the config file and sources, got %v", parsed)
+	}
+	if tags, ok := parsed["tags"].([]string); !ok || strings.Join(tags, "|") != "a|b c" {
+		t.Errorf("Expected tags a and b c, got %v", parsed["tags"])
+	}
+	if workers := serve.Result().Map()["workers"]; workers != 4 {
+		t.Errorf("Expected workers=4 from the serve.workers key, got %v", workers)
+	}
+	for _, key := range lookups {
+		if key == "host" {
+			t.Errorf("Expected no lookup of host, which the config file set")
+		}
+	}
+	if src := parser.Source("port"); src != (uargs.Source{Origin: uargs.OriginCustom, Key: "port"}) {
+		t.Errorf("Expected a custom source, got %+v", src)
+	}
+	if src := parser.Source("token"); src.Origin != "vault" {
+		t.Errorf("Expected the vault origin, got %+v", src)
+	}
+
+	// Test case 2: Invalid values and lookup errors are reported for the argument
+	store["port"] = "http"
+	_, err = parser.ParseArgs(nil)
+	var argErr *uargs.ArgError
+	if !errors.As(err, &argErr) || argErr.Name != "port" || !strings.HasSuffix(err.Error(), "(from custom, key port)") {
+		t.Errorf("Expected an error about --port naming the source, got %v", err)
+	}
+	failing := uargs.ValueSourceFunc(func(path []string, def uargs.ArgDef) (uargs.SourceValue, bool, error) {
+		return uargs.SourceValue{}, false, errors.New("service unavailable")
+	})
+	parser = uargs.NewParser([]uargs.ArgDef{
+		{Name: "port", Usage: "Port", Type: uargs.Int},
+	}, uargs.WithSource(failing))
+	_, err = parser.ParseArgs(nil)
+	if !errors.As(err, &argErr) || argErr.Name != "port" || !strings.Contains(err.Error(), "service unavailable") {
+		t.Errorf("Expected the lookup error for --port, got %v", err)
+	}
+}
+
+// TestPrecedence tests reordering and dropping sources with WithPrecedence
+func TestPrecedence(t *testing.T) {
+	config := writeConfig(t, "config.json", `{"host": "config.example.com", "serve": {"workers": 8}}`)
+	t.Setenv("HOST", "env.example.com")
+	t.Setenv("WORKERS", "2")
+	custom := uargs.ValueSourceFunc(func(path []string, def uargs.ArgDef) (uargs.SourceValue, bool, error) {
+		return uargs.SourceValue{Args: []string{"1"}}, def.Name == "workers", nil
+	})
+	newParser := func(opts ...uargs.Option) (*uargs.Parser, *uargs.Parser) {
+		parser := uargs.NewParser([]uargs.ArgDef{
+			{Name: "host", Usage: "Host", Type: uargs.String, Env: "HOST", Default: "localhost"},
+		}, append([]uargs.Option{uargs.WithConfigFile(config), uargs.WithSource(custom)}, opts...)...)
+		serve := parser.AddCommand(uargs.Command{Name: "serve", Usage: "Serve", Args: []uargs.ArgDef{
+			{Name: "workers", Usage: "Workers", Type: uargs.Int, Env: "WORKERS"},
+		}})
+		return parser, serve
+	}
+
+	// Test case 1: The environment wins over config files by default
+	parser, serve := newParser()
+	parsed, err := parser.ParseArgs([]string{"serve"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parsed["host"] != "env.example.com" || serve.Result().Map()["workers"] != 2 {
+		t.Errorf("Expected values from the environment, got %v and %v", parsed, serve.Result().Map())
+	}
+
+	// Test case 2: Config files win when listed first, also for subcommands
+	parser, serve = newParser(uargs.WithPrecedence(uargs.OriginConfig, uargs.OriginEnv))
+	if parsed, err = parser.ParseArgs([]string{"serve"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parsed["host"] != "config.example.com" || parser.Result().Origin("host") != uargs.OriginConfig {
+		t.Errorf("Expected host from the config file, got %v (%s)", parsed["host"], parser.Result().Origin("host"))
+	}
+	if workers := serve.Result().Map()["workers"]; workers != 8 {
+		t.Errorf("Expected workers=8 from the config file, got %v", workers)
+	}
+
+	// Test case 3: Sources left out are not consulted
+	parser, serve = newParser(uargs.WithPrecedence(uargs.OriginCustom))
+	if parsed, err = parser.ParseArgs([]string{"serve"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parsed["host"] != "localhost" || serve.Result().Map()["workers"] != 1 {
+		t.Errorf("Expected the default host and workers=1 from the custom source, got %v and %v", parsed, serve.Result().Map())
+	}
+	parser, _ = newParser(uargs.WithPrecedence())
+	if parsed, err = parser.ParseArgs([]string{"--host", "cli.example.com"}); err != nil || parsed["host"] != "cli.example.com" {
+		t.Errorf("Expected host from the command line, got %v (%v)", parsed["host"], err)
+	}
+
+	// Test case 4: Unknown and repeated sources are definition errors
+	if _, err := uargs.NewParserE(nil, uargs.WithPrecedence("vault")); err == nil || !strings.Contains(err.Error(), `unknown source "vault"`) {
+		t.Errorf("Expected an error about the unknown source, got %v", err)
+	}
+	if _, err := uargs.NewParserE(nil, uargs.WithPrecedence(uargs.OriginEnv, uargs.OriginEnv)); err == nil || !strings.Contains(err.Error(), `duplicate source "env"`) {
+		t.Errorf("Expected an error about the duplicate source, got %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+package uargs
+
+import (
+	"sort"
+	"strings"
+)
+
+// defaultSuggestDistance is the largest edit distance between a mistyped name
+// and a suggestion, unless changed with WithSuggestionDistance
+const defaultSuggestDistance = 2
+
+// Penalties added to the edit distance of candidates that are less likely to
+// be what the user meant
+const (
+	penaltyAlias      = 0.5 // Command aliases rank below command names
+	penaltyDeprecated = 1   // Deprecated arguments and commands rank below current ones
+	penaltyOtherKind  = 1   // A command for a mistyped flag, or a long name for a short one
+)
+
+// Suggestion is a candidate for a mistyped flag or command name
+type Suggestion struct {
+	// Kind tells whether the suggestion is a flag or a command
+	Kind MatchKind
+	// Text is the suggestion as it would be typed, e.g. "--output" or "install"
+	Text string
+	// Name is the long name of the argument or the name of the command, which
+	// differs from Text for short names and aliases
+	Name string
+	// Distance is the number of edits between the mistyped name and Text
+	Distance int
+	// Score ranks the suggestion: the distance plus penalties for aliases,
+	// deprecated names, and the other kind of name; lower is better
+	Score float64
+}
+
+// UnknownError is returned by Parse for a flag or command that is not defined.
+// It carries ranked suggestions so programs can present them in their own way.
+type UnknownError struct {
+	// Kind tells whether the token was taken for a flag or a command
+	Kind MatchKind
+	// Token is the unknown token as given, e.g. "--ouput" or "instal"
+	Token string
+	// Suggestions holds the close matches, best first
+	Suggestions []Suggestion
+
+	msg string
+}
+
+// Error returns the message, followed by the best suggestions, if any
+func (e *UnknownError) Error() string {
+	if len(e.Suggestions) == 0 {
+		return e.msg
+	}
+	var best []string
+	for _, s := range e.Suggestions {
+		if s.Score != e.Suggestions[0].Score {
+			break
+		}
+		best = append(best, s.Text)
+	}
+	return e.msg + ", did you mean " + strings.Join(best, " or ") + "?"
+}
+
+// Suggest returns the flags and commands that token may be a typo of, best
+// first, as Parse reports them for unknown tokens. Tokens starting with "-" are
+// taken for flags, others for command names.
+//
+// Example:
+//
+//	for _, s := range parser.Suggest("instal") {
+//		fmt.Println(s.Text) // install
+//	}
+func (p *Parser) Suggest(token string) []Suggestion {
+	if p == nil || strings.TrimLeft(token, "-") == "" {
+		return nil
+	}
+	if strings.HasPrefix(token, "-") {
+		return p.suggest(MatchFlag, token)
+	}
+	return p.suggest(MatchCommand, token)
+}
+
+// unknown builds the error for the unknown token of the given kind
+func (p *Parser) unknown(kind MatchKind, token, msg string) error {
+	return &UnknownError{Kind: kind, Token: token, Suggestions: p.suggest(kind, token), msg: msg}
+}
+
+// suggest returns the flags and commands within the suggestion distance of
+// token, best first. Flag tokens are matched against long names, and short
+// flag tokens against short names too; command tokens are matched against
+// command names and aliases. Hidden commands are never suggested.
+func (p *Parser) suggest(kind MatchKind, token string) []Suggestion {
+	if p.suggestDistance <= 0 {
+		return nil
+	}
+	var out []Suggestion
+	add := func(s Suggestion, typed, candidate string, penalty float64) {
+		d := editDistance(strings.ToLower(typed), strings.ToLower(candidate))
+		// Short names need a closer match, or every short flag would qualify
+		if d > p.suggestDistance || d >= max(len(candidate), len(typed)) {
+			return
+		}
+		s.Distance, s.Score = d, float64(d)+penalty
+		out = append(out, s)
+	}
+
+	short := kind == MatchFlag && !strings.HasPrefix(token, "--")
+	name := strings.TrimLeft(token, "-")
+	for long, def := range p.defs {
+		if def.EnvOnly {
+			continue
+		}
+		penalty := 0.0
+		if def.Deprecated != "" {
+			penalty += penaltyDeprecated
+		}
+		s := Suggestion{Kind: MatchFlag, Text: "--" + long, Name: long}
+		switch {
+		case kind == MatchCommand:
+			add(s, name, long, penalty+penaltyOtherKind)
+		case short:
+			if def.Short != "" {
+				add(Suggestion{Kind: MatchFlag, Text: "-" + def.Short, Name: long}, name, def.Short, penalty)
+			}
+			add(s, name, long, penalty+penaltyOtherKind)
+		default:
+			add(s, name, long, penalty)
+		}
+	}
+	if kind == MatchCommand || !short {
+		penalty := 0.0
+		if kind != MatchCommand {
+			penalty = penaltyOtherKind
+		}
+		for _, c := range p.visibleCommands() {
+			penalty := penalty
+			if c.command.Deprecated != "" {
+				penalty += penaltyDeprecated
+			}
+			s := Suggestion{Kind: MatchCommand, Text: c.command.Name, Name: c.command.Name}
+			add(s, name, c.command.Name, penalty)
+			for _, alias := range c.command.Aliases {
+				s.Text = alias
+				add(s, name, alias, penalty+penaltyAlias)
+			}
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score < out[j].Score
+		}
+		return out[i].Text < out[j].Text
+	})
+	// A name and its alias can both match; keep the better one
+	seen := make(map[string]bool)
+	ranked := out[:0]
+	for _, s := range out {
+		key := string(s.Kind) + "\x00" + s.Name
+		if !seen[key] {
+			seen[key] = true
+			ranked = append(ranked, s)
+		}
+	}
+	return ranked
+}
+
+// editDistance returns the number of insertions, deletions, substitutions, and
+// transpositions of adjacent characters needed to turn a into b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	// Three rows of the distance matrix are enough to detect transpositions
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(rb)]
+}
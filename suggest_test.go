@@ -0,0 +1,115 @@
+package uargs_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestSuggestions tests did-you-mean suggestions for unknown flags and commands
+func TestSuggestions(t *testing.T) {
+	newParser := func(opts ...uargs.Option) *uargs.Parser {
+		parser := uargs.NewParser([]uargs.ArgDef{
+			{Name: "output", Short: "o", Usage: "Output file", Type: uargs.String},
+			{Name: "outdir", Usage: "Old output directory", Type: uargs.String, Deprecated: "use --output"},
+			{Name: "verbose", Short: "v", Usage: "Verbose output", Type: uargs.Int},
+		}, opts...)
+		parser.AddCommand(uargs.Command{Name: "install", Usage: "Install a package", Aliases: []string{"i", "add"}})
+		parser.AddCommand(uargs.Command{Name: "remove", Usage: "Remove a package", Aliases: []string{"rm"}})
+		return parser
+	}
+	parser := newParser()
+
+	// Test case 1: Messages name the best suggestions
+	cases := map[string][]string{
+		"unknown argument --ouput, did you mean --output?":                {"--ouput"},
+		"unknown argument --verbsoe, did you mean --verbose?":             {"--verbsoe"},
+		"invalid short argument usage: -verbose, did you mean --verbose?": {"-verbose"},
+		"unknown command instal, did you mean install?":                   {"instal"},
+		"unknown command rmeove, did you mean remove?":                    {"rmeove"},
+		"unknown command ad, did you mean add?":                           {"ad"},
+		"unknown argument --install, did you mean install?":               {"--install"},
+		"unknown argument --xyz":                                          {"--xyz"},
+		"unknown command frobnicate":                                      {"frobnicate"},
+		"unknown short argument -x":                                       {"-x"},
+	}
+	for want, argv := range cases {
+		_, err := parser.ParseArgs(argv)
+		if err == nil || err.Error() != want {
+			t.Errorf("Expected error %q for %v, got %v", want, argv, err)
+		}
+	}
+
+	// Test case 2: The structured error ranks deprecated names below current ones
+	_, err := parser.ParseArgs([]string{"--outit"})
+	var unknown *uargs.UnknownError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("Expected an UnknownError, got %v", err)
+	}
+	if unknown.Kind != uargs.MatchFlag || unknown.Token != "--outit" {
+		t.Errorf("Expected flag token --outit, got %s %s", unknown.Kind, unknown.Token)
+	}
+	if len(unknown.Suggestions) != 2 || unknown.Suggestions[0].Name != "output" || unknown.Suggestions[1].Name != "outdir" {
+		t.Fatalf("Expected suggestions output and outdir, got %+v", unknown.Suggestions)
+	}
+	if s := unknown.Suggestions[1]; s.Distance != 2 || s.Score != 3 {
+		t.Errorf("Expected outdir at distance 2 with score 3, got %+v", s)
+	}
+
+	// Test case 3: Aliases are suggested as typed, once per command
+	_, err = parser.ParseArgs([]string{"rn"})
+	if !errors.As(err, &unknown) {
+		t.Fatalf("Expected an UnknownError, got %v", err)
+	}
+	if len(unknown.Suggestions) == 0 || unknown.Suggestions[0].Text != "rm" || unknown.Suggestions[0].Name != "remove" {
+		t.Errorf("Expected alias rm of remove first, got %+v", unknown.Suggestions)
+	}
+	for i, s := range unknown.Suggestions {
+		for _, other := range unknown.Suggestions[i+1:] {
+			if s.Kind == other.Kind && s.Name == other.Name {
+				t.Errorf("Expected %s to be suggested once, got %+v", s.Name, unknown.Suggestions)
+			}
+		}
+	}
+
+	// Test case 4: The distance threshold is configurable
+	strict := newParser(uargs.WithSuggestionDistance(1))
+	if _, err := strict.ParseArgs([]string{"--otpt"}); err == nil || err.Error() != "unknown argument --otpt" {
+		t.Errorf("Expected no suggestion beyond distance 1, got %v", err)
+	}
+	off := newParser(uargs.WithSuggestionDistance(0))
+	if _, err := off.ParseArgs([]string{"--ouput"}); err == nil || err.Error() != "unknown argument --ouput" {
+		t.Errorf("Expected suggestions to be off, got %v", err)
+	}
+}
+
+// TestSuggest tests requesting suggestions without parsing
+func TestSuggest(t *testing.T) {
+	parser := uargs.NewParser([]uargs.ArgDef{{Name: "force", Short: "f", Usage: "Force", Type: uargs.Int}})
+	parser.AddCommand(uargs.Command{Name: "install", Usage: "Install a package"})
+	parser.AddCommand(uargs.Command{Name: "uninstall", Usage: "Remove a package"})
+
+	got := parser.Suggest("instal")
+	if len(got) != 1 || got[0].Kind != uargs.MatchCommand || got[0].Text != "install" || got[0].Distance != 1 {
+		t.Errorf("Expected install at distance 1, got %+v", got)
+	}
+	got = parser.Suggest("unistall")
+	if len(got) != 2 || got[0].Text != "uninstall" || got[1].Text != "install" {
+		t.Errorf("Expected uninstall, then install, got %+v", got)
+	}
+	got = parser.Suggest("--froce")
+	if len(got) != 1 || got[0].Kind != uargs.MatchFlag || got[0].Text != "--force" {
+		t.Errorf("Expected --force, got %+v", got)
+	}
+	if got := parser.Suggest("--"); got != nil {
+		t.Errorf("Expected no suggestions for an empty name, got %+v", got)
+	}
+
+	// Nested commands suggest among their own subcommands
+	remote := parser.AddCommand(uargs.Command{Name: "remote", Usage: "Manage remotes"})
+	remote.AddCommand(uargs.Command{Name: "add", Usage: "Add a remote"})
+	if _, err := parser.ParseArgs([]string{"remote", "ad"}); err == nil || err.Error() != "unknown command ad, did you mean add?" {
+		t.Errorf("Expected a suggestion from the nested command, got %v", err)
+	}
+}
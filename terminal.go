@@ -0,0 +1,84 @@
+package uargs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrTerminal is wrapped by the errors returned when a command's terminal
+// requirements (NeedsTTY, MinWidth, NeedsRawMode) are not met
+var ErrTerminal = errors.New("terminal requirements not met")
+
+// TerminalInfo describes the terminal a program runs in
+type TerminalInfo struct {
+	// Interactive reports whether both stdin and stdout are terminals
+	Interactive bool
+	// Width is the number of columns, or 0 if unknown
+	Width int
+	// RawMode reports whether stdin is a terminal that can be put in raw mode
+	RawMode bool
+}
+
+// DetectTerminal inspects stdin and stdout, and reads the width from the
+// COLUMNS environment variable
+func DetectTerminal() TerminalInfo {
+	in, out := isTerminal(os.Stdin), isTerminal(os.Stdout)
+	width, _ := strconv.Atoi(os.Getenv("COLUMNS"))
+	return TerminalInfo{Interactive: in && out, Width: max(width, 0), RawMode: in}
+}
+
+// isTerminal reports whether f is a character device such as a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// checkTerminal verifies the terminal requirements of the command handled by p
+func (p *Parser) checkTerminal() error {
+	cmd := p.command
+	if cmd == nil || (!cmd.NeedsTTY && cmd.MinWidth == 0 && !cmd.NeedsRawMode) {
+		return nil
+	}
+	detect := p.terminal
+	if detect == nil {
+		detect = DetectTerminal
+	}
+	term := detect()
+	name := strings.Join(p.path(), " ")
+	switch {
+	case cmd.NeedsTTY && !term.Interactive:
+		return fmt.Errorf("%w: command %s needs an interactive terminal; run it directly in a terminal instead of through a pipe or script", ErrTerminal, name)
+	case cmd.NeedsRawMode && !term.RawMode:
+		return fmt.Errorf("%w: command %s needs a terminal that supports raw mode; make sure stdin is not redirected", ErrTerminal, name)
+	case cmd.MinWidth > 0 && term.Width > 0 && term.Width < cmd.MinWidth:
+		return fmt.Errorf("%w: command %s needs a terminal at least %d columns wide, got %d; widen the window", ErrTerminal, name, cmd.MinWidth, term.Width)
+	}
+	return nil
+}
+
+// adviseSecret warns, once per parser and only in interactive sessions, that
+// the secret argument def was given on the command line
+func (p *Parser) adviseSecret(def ArgDef) {
+	if p.quietSecrets || p.secretAdvised {
+		return
+	}
+	detect := p.terminal
+	if detect == nil {
+		detect = DetectTerminal
+	}
+	if !detect().Interactive {
+		return
+	}
+	p.secretAdvised = true
+	alternative := "read it from a file or an environment variable instead"
+	switch {
+	case p.secrets != nil:
+		alternative = "store it in the secret store instead"
+	case def.AllowFileRef:
+		alternative = "use " + flagName(def) + " @file to read it from a file instead"
+	}
+	p.warnf("%s was given on the command line, where it can end up in shell history and process listings; %s", flagName(def), alternative)
+}
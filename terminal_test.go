@@ -0,0 +1,80 @@
+package uargs_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestTerminalRequirements tests per-command terminal capability checks
+func TestTerminalRequirements(t *testing.T) {
+	term := uargs.TerminalInfo{}
+	parser := uargs.NewParser(nil, uargs.WithTerminal(func() uargs.TerminalInfo { return term }))
+	parser.AddCommand(uargs.Command{Name: "shell", NeedsTTY: true})
+	parser.AddCommand(uargs.Command{Name: "top", MinWidth: 100, NeedsRawMode: true})
+	parser.AddCommand(uargs.Command{Name: "list"})
+
+	tests := []struct {
+		term uargs.TerminalInfo
+		cmd  string
+		want string
+	}{
+		{uargs.TerminalInfo{}, "list", ""},
+		{uargs.TerminalInfo{}, "shell", "terminal requirements not met: command shell needs an interactive terminal; run it directly in a terminal instead of through a pipe or script"},
+		{uargs.TerminalInfo{Interactive: true}, "shell", ""},
+		{uargs.TerminalInfo{Interactive: true}, "top", "terminal requirements not met: command top needs a terminal that supports raw mode; make sure stdin is not redirected"},
+		{uargs.TerminalInfo{RawMode: true, Width: 80}, "top", "terminal requirements not met: command top needs a terminal at least 100 columns wide, got 80; widen the window"},
+		{uargs.TerminalInfo{RawMode: true, Width: 120}, "top", ""},
+		{uargs.TerminalInfo{RawMode: true}, "top", ""},
+	}
+	for _, tt := range tests {
+		term = tt.term
+		_, err := parser.ParseArgs([]string{tt.cmd})
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("%s with %+v: unexpected error %v", tt.cmd, tt.term, err)
+		case tt.want != "" && (err == nil || err.Error() != tt.want || !errors.Is(err, uargs.ErrTerminal)):
+			t.Errorf("%s with %+v: expected %q, got %v", tt.cmd, tt.term, tt.want, err)
+		}
+	}
+}
+
+// TestSecretAdvice tests the warning about secrets given on the command line
+func TestSecretAdvice(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "token", Usage: "API token", Type: uargs.String, Secret: true},
+		{Name: "password", Usage: "Password", Type: uargs.String, Secret: true, AllowFileRef: true},
+	}
+	interactive := true
+	detect := func() uargs.TerminalInfo { return uargs.TerminalInfo{Interactive: interactive} }
+
+	var warnings bytes.Buffer
+	parser := uargs.NewParser(args, uargs.WithWarnings(&warnings), uargs.WithTerminal(detect))
+	if _, err := parser.ParseArgs([]string{"--password", "hunter2"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := "warning: --password was given on the command line, where it can end up in shell history and process listings; use --password @file to read it from a file instead\n"
+	if warnings.String() != want {
+		t.Errorf("Expected %q, got %q", want, warnings.String())
+	}
+	if _, err := parser.ParseArgs([]string{"--token", "abc"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if warnings.String() != want {
+		t.Errorf("Expected the advice only once, got %q", warnings.String())
+	}
+
+	// Not in scripts, and not when turned off
+	interactive = false
+	warnings.Reset()
+	parser = uargs.NewParser(args, uargs.WithWarnings(&warnings), uargs.WithTerminal(detect))
+	parser.ParseArgs([]string{"--token", "abc"})
+	interactive = true
+	parser = uargs.NewParser(args, uargs.WithWarnings(&warnings), uargs.WithTerminal(detect), uargs.WithSecretAdvice(false))
+	parser.ParseArgs([]string{"--token", "abc"})
+	if warnings.Len() != 0 {
+		t.Errorf("Expected no advice, got %q", warnings.String())
+	}
+}
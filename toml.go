@@ -0,0 +1,273 @@
+package uargs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseTOML reads the subset of TOML used by config files: key/value pairs
+// with bare, quoted, or dotted keys, [table] headers for the arguments of
+// subcommands, and values that are strings, integers, floats, booleans, or
+// arrays of them, which may span several lines. Errors give the line and, where
+// known, the key.
+func (c *configFile) parseTOML(data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	table := ""
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		if line[0] == '[' {
+			if strings.HasPrefix(line, "[[") {
+				return fmt.Errorf("%s:%d: arrays of tables are not supported", c.path, n)
+			}
+			end := strings.IndexByte(line, ']')
+			if end < 0 || !tomlBlank(line[end+1:]) {
+				return fmt.Errorf("%s:%d: expected [table]", c.path, n)
+			}
+			keys, rest, err := tomlKey(line[1:end])
+			if err != nil || rest != "" {
+				return fmt.Errorf("%s:%d: invalid table name %s", c.path, n, strings.TrimSpace(line[1:end]))
+			}
+			table = strings.Join(keys, ".") + "."
+			continue
+		}
+		start, col := n, indentOf(scanner.Text())+1
+		keys, rest, err := tomlKey(line)
+		if err != nil {
+			return fmt.Errorf("%s:%d: %v", c.path, n, err)
+		}
+		key := table + strings.Join(keys, ".")
+		if !strings.HasPrefix(rest, "=") {
+			return fmt.Errorf("%s:%d: %s: expected key = value", c.path, n, key)
+		}
+		value := strings.TrimSpace(rest[1:])
+		// Arrays continue on the following lines until their brackets close
+		for strings.HasPrefix(value, "[") && !tomlArrayClosed(value) && scanner.Scan() {
+			n++
+			value += "\n" + strings.TrimSpace(scanner.Text())
+		}
+		args, err := tomlValue(value)
+		if err != nil {
+			return fmt.Errorf("%s:%d: %s: %v", c.path, start, key, err)
+		}
+		if _, ok := c.values[key]; ok {
+			return fmt.Errorf("%s:%d: %s: duplicate key", c.path, start, key)
+		}
+		if len(args) > 0 {
+			c.values[key] = configValue{key: key, line: start, column: col, args: args}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%s: %v", c.path, err)
+	}
+	return nil
+}
+
+// tomlKey reads the bare, quoted, or dotted key at the start of s and returns
+// its parts and the rest of s
+func tomlKey(s string) ([]string, string, error) {
+	var keys []string
+	for {
+		s = strings.TrimSpace(s)
+		var key string
+		switch {
+		case s == "":
+			return nil, "", fmt.Errorf("expected a key")
+		case s[0] == '"' || s[0] == '\'':
+			end := strings.IndexByte(s[1:], s[0])
+			if end < 0 {
+				return nil, "", fmt.Errorf("unterminated quote %c in key", s[0])
+			}
+			key, s = s[1:end+1], s[end+2:]
+		default:
+			end := 0
+			for end < len(s) && isBareKeyChar(s[end]) {
+				end++
+			}
+			if end == 0 {
+				return nil, "", fmt.Errorf("invalid key %s", strings.Fields(s)[0])
+			}
+			key, s = s[:end], s[end:]
+		}
+		keys = append(keys, key)
+		s = strings.TrimSpace(s)
+		if !strings.HasPrefix(s, ".") {
+			return keys, s, nil
+		}
+		s = s[1:]
+	}
+}
+
+// isBareKeyChar reports whether c may appear in a bare TOML key
+func isBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// tomlBlank reports whether s holds only whitespace and a comment
+func tomlBlank(s string) bool {
+	s = strings.TrimSpace(s)
+	return s == "" || s[0] == '#'
+}
+
+// tomlArrayClosed reports whether the brackets of the array in s are balanced,
+// ignoring brackets in strings and comments
+func tomlArrayClosed(s string) bool {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		}
+	}
+	return depth <= 0
+}
+
+// tomlValue converts a TOML value, possibly followed by a comment, to
+// command-line values
+func tomlValue(s string) ([]string, error) {
+	if !strings.HasPrefix(s, "[") {
+		v, rest, err := tomlScalar(s)
+		if err != nil {
+			return nil, err
+		}
+		if !tomlBlank(rest) {
+			return nil, fmt.Errorf("unexpected text after value")
+		}
+		return []string{v}, nil
+	}
+	args := []string{}
+	s = s[1:]
+	for {
+		s = tomlSkip(s)
+		if strings.HasPrefix(s, "]") {
+			break
+		}
+		if strings.HasPrefix(s, "[") || strings.HasPrefix(s, "{") {
+			return nil, fmt.Errorf("nested arrays and tables are not supported")
+		}
+		v, rest, err := tomlScalar(s)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+		s = tomlSkip(rest)
+		if strings.HasPrefix(s, ",") {
+			s = s[1:]
+		} else if !strings.HasPrefix(s, "]") {
+			return nil, fmt.Errorf("expected , or ] in array")
+		}
+	}
+	if !tomlBlank(s[1:]) {
+		return nil, fmt.Errorf("unexpected text after value")
+	}
+	return args, nil
+}
+
+// tomlSkip skips whitespace, line breaks, and comments at the start of s
+func tomlSkip(s string) string {
+	for {
+		s = strings.TrimLeft(s, " \t\r\n")
+		if !strings.HasPrefix(s, "#") {
+			return s
+		}
+		end := strings.IndexByte(s, '\n')
+		if end < 0 {
+			return ""
+		}
+		s = s[end:]
+	}
+}
+
+// tomlScalar reads the string, number, or boolean at the start of s and returns
+// it as a command-line value, with the rest of s
+func tomlScalar(s string) (string, string, error) {
+	switch {
+	case s == "":
+		return "", "", fmt.Errorf("expected a value")
+	case s[0] == '\'':
+		end := strings.IndexByte(s[1:], '\'')
+		if end < 0 {
+			return "", "", fmt.Errorf("unterminated string")
+		}
+		return s[1 : end+1], s[end+2:], nil
+	case s[0] == '"':
+		var b strings.Builder
+		for i := 1; i < len(s); i++ {
+			switch c := s[i]; {
+			case c == '"':
+				return b.String(), s[i+1:], nil
+			case c == '\n':
+				return "", "", fmt.Errorf("unterminated string")
+			case c == '\\' && i+1 < len(s):
+				i++
+				switch s[i] {
+				case 'n':
+					b.WriteByte('\n')
+				case 't':
+					b.WriteByte('\t')
+				case 'r':
+					b.WriteByte('\r')
+				case 'b':
+					b.WriteByte('\b')
+				case 'f':
+					b.WriteByte('\f')
+				case '"', '\\':
+					b.WriteByte(s[i])
+				case 'u', 'U':
+					size := 4
+					if s[i] == 'U' {
+						size = 8
+					}
+					r, err := strconv.ParseUint(s[i+1:min(i+1+size, len(s))], 16, 32)
+					if err != nil || i+size >= len(s) {
+						return "", "", fmt.Errorf("invalid escape \\%c", s[i])
+					}
+					b.WriteRune(rune(r))
+					i += size
+				default:
+					return "", "", fmt.Errorf("invalid escape \\%c", s[i])
+				}
+			default:
+				b.WriteByte(c)
+			}
+		}
+		return "", "", fmt.Errorf("unterminated string")
+	}
+	end := strings.IndexAny(s, ",]# \t\r\n")
+	if end < 0 {
+		end = len(s)
+	}
+	word, rest := s[:end], s[end:]
+	if word == "true" || word == "false" {
+		return word, rest, nil
+	}
+	digits := strings.ReplaceAll(word, "_", "")
+	if n, err := strconv.ParseInt(digits, 0, 64); err == nil {
+		return strconv.FormatInt(n, 10), rest, nil
+	}
+	if _, err := strconv.ParseFloat(digits, 64); err == nil && !strings.ContainsAny(digits, "xXpP") {
+		return digits, rest, nil
+	}
+	return "", "", fmt.Errorf("invalid unquoted value")
+}
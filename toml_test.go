@@ -0,0 +1,88 @@
+package uargs_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestConfigTOML tests taking values from a TOML config file
+func TestConfigTOML(t *testing.T) {
+	path := writeConfig(t, "config.toml", `# Service settings
+port = 8_080
+host = "example.com" # inline comment
+ratio = 0.5
+mode = 'C:\fast'
+tags = [
+	"a",  # first
+	"b]",
+]
+"log-level" = "debug"
+remote.timeout = 0x1E
+
+[remote.add]
+name = "origin\tmain"
+`)
+	parser := uargs.NewParser([]uargs.ArgDef{
+		{Name: "port", Usage: "Port", Type: uargs.Int},
+		{Name: "host", Usage: "Host", Type: uargs.String},
+		{Name: "ratio", Usage: "Ratio", Type: uargs.Float},
+		{Name: "mode", Usage: "Mode", Type: uargs.String},
+		{Name: "tags", Usage: "Tags", Type: uargs.String, NumArgs: 2},
+		{Name: "log-level", Usage: "Log level", Type: uargs.String},
+	}, uargs.WithConfigFile(path))
+	remote := parser.AddCommand(uargs.Command{Name: "remote", Usage: "Manage remotes", Args: []uargs.ArgDef{
+		{Name: "timeout", Usage: "Timeout", Type: uargs.Int},
+	}})
+	add := remote.AddCommand(uargs.Command{Name: "add", Usage: "Add a remote", Args: []uargs.ArgDef{
+		{Name: "name", Usage: "Remote name", Type: uargs.String},
+	}})
+
+	parsed, err := parser.ParseArgs([]string{"remote", "add"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parsed["port"] != 8080 || parsed["host"] != "example.com" || parsed["ratio"] != 0.5 || parsed["mode"] != `C:\fast` || parsed["log-level"] != "debug" {
+		t.Errorf("Expected values from the config file, got %v", parsed)
+	}
+	if tags, ok := parsed["tags"].([]string); !ok || strings.Join(tags, "|") != "a|b]" {
+		t.Errorf("Expected tags a and b], got %v", parsed["tags"])
+	}
+	if timeout := remote.Result().Map()["timeout"]; timeout != 30 {
+		t.Errorf("Expected timeout=30, got %v", timeout)
+	}
+	if name := add.Result().Map()["name"]; name != "origin\tmain" {
+		t.Errorf("Expected name=origin<tab>main, got %q", name)
+	}
+}
+
+// TestConfigTOMLErrors tests the locations given by errors in TOML config files
+func TestConfigTOMLErrors(t *testing.T) {
+	defs := []uargs.ArgDef{
+		{Name: "port", Usage: "Port", Type: uargs.Int},
+		{Name: "host", Usage: "Host", Type: uargs.String},
+	}
+	tests := []struct {
+		content, expected string
+	}{
+		{"port = 80\nhost example.com", "%s:2: host: expected key = value"},
+		{"port = 80\nhost = \"example.com", "%s:2: host: unterminated string"},
+		{"port = eighty", "%s:1: port: invalid unquoted value"},
+		{"port = 80\n\nport = 81", "%s:3: port: duplicate key"},
+		{"host = \"a\" \"b\"", "%s:1: host: unexpected text after value"},
+		{"[remote\nport = 80", "%s:1: expected [table]"},
+		{"port = [[1], [2]]", "%s:1: port: nested arrays and tables are not supported"},
+		{"port = \"\\x\"", `%s:1: port: invalid escape \x`},
+		{"port = 99999999999999999999", `%s:1:1: port: expected int, got "99999999999999999999"`},
+	}
+	for _, test := range tests {
+		path := writeConfig(t, "config.toml", test.content)
+		parser := uargs.NewParser(defs, uargs.WithConfigFile(path))
+		_, err := parser.ParseArgs(nil)
+		expected := strings.ReplaceAll(test.expected, "%s", path)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected an error containing %q, got %v", expected, err)
+		}
+	}
+}
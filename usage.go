@@ -0,0 +1,443 @@
+package uargs
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// AccessibleEnv is the environment variable that switches Usage to the
+// screen-reader-friendly layout when set to a value other than "" or "0"
+const AccessibleEnv = "UARGS_ACCESSIBLE"
+
+// Usage generates a formatted help text showing all defined arguments with their
+// names, short options, and usage descriptions. This is helpful for displaying
+// to users when invalid arguments are provided or when help is requested.
+// Each flag is followed by the type of its values, as in --coords FLOAT FLOAT,
+// and its description is annotated with (required), (default: 8080), and the
+// like.
+// Arguments are listed in the order they were defined, followed by inherited
+// persistent ones, unless WithSortedUsage is set. Arguments with a Group are
+// listed under its heading, after the ungrouped ones.
+//
+// When the parser was created with WithAccessibleUsage, or the UARGS_ACCESSIBLE
+// environment variable is set, the output of AccessibleUsage is returned instead.
+//
+// Example:
+//
+//	if err != nil {
+//		fmt.Println(err)
+//		fmt.Println(parser.Usage())
+//		os.Exit(1)
+//	}
+func (p *Parser) Usage() string {
+	if p == nil {
+		return ""
+	}
+	if p.accessible || accessibleFromEnv() {
+		return p.AccessibleUsage()
+	}
+	// Short names are padded to a common width so that long names line up
+	shortWidth := 0
+	for _, def := range p.defs {
+		if def.Short != "" && !def.EnvOnly {
+			shortWidth = max(shortWidth, displayWidth(def.Short)+3)
+		}
+	}
+	var rows [][4]string // Flags, description, example, and group of each argument
+	flagWidth := 0
+	for _, def := range p.usageDefs() {
+		if def.EnvOnly {
+			continue
+		}
+		short := ""
+		if def.Short != "" {
+			short = "-" + def.Short + ", "
+		}
+		row := [4]string{padRight(short, shortWidth) + "--" + def.Name + " " + placeholder(def), strings.TrimSpace(def.Usage + p.usageNotes(def)), def.Example, def.Group}
+		flagWidth = max(flagWidth, displayWidth(row[0]))
+		rows = append(rows, row)
+	}
+
+	// Columns are aligned by display width so wide and combining characters line up
+	var b strings.Builder
+	b.WriteString(p.usageHeader())
+	if p.command != nil && (p.command.Usage != "" || p.command.DocsURL != "") {
+		b.WriteString("\n" + p.commandUsage() + "\n")
+		// Other sections start with a blank line of their own
+		if slices.ContainsFunc(rows, func(row [4]string) bool { return row[3] == "" }) {
+			b.WriteString("\n")
+		}
+	}
+	for _, group := range p.argGroups() {
+		if group != "" {
+			b.WriteString("\n" + strings.TrimSuffix(group, ":") + ":\n")
+		}
+		for _, row := range rows {
+			if row[3] != group {
+				continue
+			}
+			line := "  " + padRight(row[0], flagWidth) + "  " + row[1]
+			b.WriteString(strings.TrimRight(line, " "))
+			b.WriteString("\n")
+			if row[2] != "" {
+				b.WriteString("  " + strings.Repeat(" ", flagWidth) + "  e.g. " + row[2] + "\n")
+			}
+		}
+	}
+	p.writeSettings(&b)
+	p.writeCommands(&b)
+	p.writeGroups(&b)
+	p.writeEpilogue(&b)
+	return b.String()
+}
+
+// writeSettings lists the EnvOnly arguments, which are set through the
+// environment or config files instead of flags
+func (p *Parser) writeSettings(b *strings.Builder) {
+	var rows [][2]string // Name and description of each setting
+	width := 0
+	for _, def := range p.usageDefs() {
+		if def.EnvOnly {
+			rows = append(rows, [2]string{def.Name, strings.TrimSpace(def.Usage + p.usageNotes(def))})
+			width = max(width, displayWidth(def.Name))
+		}
+	}
+	if len(rows) == 0 {
+		return
+	}
+	b.WriteString("\nSettings (environment or config file only):\n")
+	for _, row := range rows {
+		b.WriteString(strings.TrimRight("  "+padRight(row[0], width)+"  "+row[1], " ") + "\n")
+	}
+}
+
+// AccessibleUsage generates help text suited to screen readers. It avoids column
+// alignment and symbols, describes every argument in its own paragraph, and
+// spells out whether the argument is required and what kind of value it takes.
+//
+// Example output:
+//
+//	Usage:
+//
+//	Option --input, short form -i. Required. Takes a string value. Input file path.
+func (p *Parser) AccessibleUsage() string {
+	if p == nil {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(p.usageHeader())
+	if p.command != nil && p.command.Usage != "" {
+		b.WriteString("\n" + strings.TrimSuffix(p.command.Usage, ".") + ".\n")
+	}
+	if p.command != nil && p.command.DocsURL != "" {
+		b.WriteString("\nSee " + p.command.DocsURL + " for more information.\n")
+	}
+	for _, def := range p.usageDefs() {
+		if def.EnvOnly {
+			b.WriteString("\nSetting " + def.Name + ", set through the environment or a config file only")
+		} else {
+			b.WriteString("\nOption --")
+			b.WriteString(def.Name)
+		}
+		if def.Short != "" {
+			b.WriteString(", short form -")
+			b.WriteString(def.Short)
+		}
+		if def.Required {
+			b.WriteString(". Required")
+		} else {
+			b.WriteString(". Optional")
+		}
+		b.WriteString(". ")
+		b.WriteString(describeValues(def))
+		b.WriteString(".")
+		if env := p.envName(def); env != "" {
+			b.WriteString(" Can also be set with the environment variable " + env + ".")
+		}
+		if def.Default != nil {
+			b.WriteString(fmt.Sprintf(" Defaults to %v.", redactValue(def, def.Default)))
+		}
+		if r := rangeText(def); r != "" {
+			b.WriteString(" Allowed range " + r + ".")
+		}
+		if def.Step > 0 {
+			b.WriteString(" Must be a multiple of " + formatNumber(def.Step) + ".")
+		}
+		if len(def.Choices) > 0 {
+			b.WriteString(" Allowed values: " + strings.Join(def.Choices, ", ") + ".")
+		}
+		if len(def.RequiredIfGiven) > 0 {
+			b.WriteString(" Required when " + p.flagNames(def.RequiredIfGiven) + " is given.")
+		}
+		if def.RequiredIf != "" {
+			b.WriteString(" Required when the condition " + def.RequiredIf + " holds.")
+		}
+		if len(def.ConflictsWith) > 0 {
+			b.WriteString(" Cannot be used together with " + p.flagNames(def.ConflictsWith) + ".")
+		}
+		if def.Group != "" {
+			b.WriteString(" Listed under " + strings.TrimSuffix(def.Group, ":") + ".")
+		}
+		if def.Deprecated != "" {
+			b.WriteString(" Deprecated: " + strings.TrimSuffix(def.Deprecated, ".") + ".")
+		}
+		if def.Usage != "" {
+			b.WriteString(" ")
+			b.WriteString(strings.TrimSuffix(def.Usage, "."))
+			b.WriteString(".")
+		}
+		if def.Example != "" {
+			b.WriteString(" For example: " + def.Example + ".")
+		}
+		if def.DocsURL != "" {
+			b.WriteString(" See " + def.DocsURL + " for more information.")
+		}
+		b.WriteString("\n")
+	}
+	for _, c := range p.visibleCommands() {
+		b.WriteString("\nCommand " + c.command.Name + ".")
+		if c.command.Usage != "" {
+			b.WriteString(" " + strings.TrimSuffix(c.command.Usage, ".") + ".")
+		}
+		if len(c.command.Aliases) > 0 {
+			b.WriteString(" Also available as " + strings.Join(c.command.Aliases, ", ") + ".")
+		}
+		if c.command.Group != "" {
+			b.WriteString(" Listed under " + strings.TrimSuffix(c.command.Group, ":") + ".")
+		}
+		if c.command.Default {
+			b.WriteString(" Runs when no command is given.")
+		}
+		if c.command.Deprecated != "" {
+			b.WriteString(" Deprecated: " + strings.TrimSuffix(c.command.Deprecated, ".") + ".")
+		}
+		b.WriteString("\n")
+	}
+	for _, g := range p.groups {
+		b.WriteString("\nArguments " + p.describeGroup(g) + ".\n")
+	}
+	p.writeEpilogue(&b)
+	return b.String()
+}
+
+// usageHeader returns the first line of usage output, which names the
+// command path for subcommand parsers, as in "Usage: remote add"
+func (p *Parser) usageHeader() string {
+	if path := p.path(); len(path) > 0 {
+		return "Usage: " + strings.Join(path, " ") + "\n"
+	}
+	return "Usage:\n"
+}
+
+// writeCommands appends the subcommands and their descriptions to usage output,
+// in a section per Group. Ungrouped commands come first, and groups follow in
+// the order they first appear.
+func (p *Parser) writeCommands(b *strings.Builder) {
+	commands := p.visibleCommands()
+	if len(commands) == 0 {
+		return
+	}
+	width := 0
+	groups := []string{""}
+	for _, c := range commands {
+		width = max(width, displayWidth(c.command.Name))
+		if !slices.Contains(groups, c.command.Group) {
+			groups = append(groups, c.command.Group)
+		}
+	}
+	for _, group := range groups {
+		var lines []string
+		for _, c := range commands {
+			if c.command.Group != group {
+				continue
+			}
+			line := "  " + padRight(c.command.Name, width) + "  " + c.command.Usage
+			if len(c.command.Aliases) > 0 {
+				line += " (aliases: " + strings.Join(c.command.Aliases, ", ") + ")"
+			}
+			if c.command.Default {
+				line += " (default)"
+			}
+			if c.command.Deprecated != "" {
+				line += " (deprecated: " + c.command.Deprecated + ")"
+			}
+			lines = append(lines, strings.TrimRight(line, " ")+"\n")
+		}
+		if len(lines) == 0 {
+			continue // No ungrouped commands
+		}
+		heading := group
+		if heading == "" {
+			heading = "Commands"
+		}
+		b.WriteString("\n" + strings.TrimSuffix(heading, ":") + ":\n")
+		b.WriteString(strings.Join(lines, ""))
+	}
+}
+
+// writeGroups appends the rules of argument groups to usage output
+func (p *Parser) writeGroups(b *strings.Builder) {
+	if len(p.groups) == 0 {
+		return
+	}
+	b.WriteString("\nConstraints:\n")
+	for _, g := range p.groups {
+		b.WriteString("  " + p.describeGroup(g) + "\n")
+	}
+}
+
+// usageDefs returns the arguments in the order they were defined, or sorted by
+// name with WithSortedUsage
+func (p *Parser) usageDefs() []ArgDef {
+	defs := make([]ArgDef, 0, len(p.order))
+	for _, name := range p.order {
+		defs = append(defs, p.defs[name])
+	}
+	if p.sortUsage {
+		slices.SortFunc(defs, func(a, b ArgDef) int { return strings.Compare(a.Name, b.Name) })
+	}
+	return defs
+}
+
+// argGroups returns "" for ungrouped arguments followed by the Groups of the
+// flag arguments in the order they first appear in the definitions
+func (p *Parser) argGroups() []string {
+	groups := []string{""}
+	for _, name := range p.order {
+		if def := p.defs[name]; !def.EnvOnly && !slices.Contains(groups, def.Group) {
+			groups = append(groups, def.Group)
+		}
+	}
+	return groups
+}
+
+// usageNotes returns the parenthesized annotations appended to an argument's
+// usage text, such as its default value and allowed range
+func (p *Parser) usageNotes(def ArgDef) string {
+	notes := ""
+	if def.Required {
+		notes += " (required)"
+	}
+	if env := p.envName(def); env != "" {
+		notes += " (env: " + env + ")"
+	}
+	if def.Default != nil {
+		notes += fmt.Sprintf(" (default: %v)", redactValue(def, def.Default))
+	}
+	if r := rangeText(def); r != "" {
+		notes += " (range: " + r + ")"
+	}
+	if def.Step > 0 {
+		notes += " (multiple of " + formatNumber(def.Step) + ")"
+	}
+	if len(def.Choices) > 0 {
+		notes += " (one of: " + strings.Join(def.Choices, ", ") + ")"
+	}
+	if def.DocsURL != "" {
+		notes += " (see " + def.DocsURL + ")"
+	}
+	if len(def.RequiredIfGiven) > 0 {
+		notes += " (required with " + p.flagNames(def.RequiredIfGiven) + ")"
+	}
+	if def.RequiredIf != "" {
+		notes += " (required when " + def.RequiredIf + ")"
+	}
+	if len(def.ConflictsWith) > 0 {
+		notes += " (conflicts with " + p.flagNames(def.ConflictsWith) + ")"
+	}
+	if def.Deprecated != "" {
+		notes += " (deprecated: " + def.Deprecated + ")"
+	}
+	return notes
+}
+
+// placeholder returns the values an argument takes as shown after its flag in
+// usage output: its Metavar or type in upper case, once per value, as in
+// "FLOAT FLOAT", or followed by "..." when there is no limit
+func placeholder(def ArgDef) string {
+	if names := strings.Fields(def.Metavar); len(names) > 1 {
+		return strings.Join(names, " ")
+	}
+	name := strings.ToUpper(string(valueType(def)))
+	if def.Metavar != "" {
+		name = strings.TrimSpace(def.Metavar)
+	}
+	n := maxArgs(def)
+	if n < 0 {
+		return strings.Repeat(name+" ", max(def.MinArgs-1, 0)) + name + "..."
+	}
+	return strings.TrimSuffix(strings.Repeat(name+" ", n), " ")
+}
+
+// exampleHint returns the suffix appended to value errors of an argument
+// with an Example, such as ", e.g. --coords 10.5 20.3"
+func exampleHint(def ArgDef) string {
+	if def.Example == "" {
+		return ""
+	}
+	return ", e.g. " + def.Example
+}
+
+// flagName formats an argument the way it is referred to in help, errors, and
+// warnings: "-s, --name", or "--name" when it has no short name
+func flagName(def ArgDef) string {
+	if def.Short == "" {
+		return "--" + def.Name
+	}
+	return "-" + def.Short + ", --" + def.Name
+}
+
+// flagNameOf formats the named argument with flagName
+func (p *Parser) flagNameOf(name string) string {
+	if def, ok := p.defs[name]; ok {
+		return flagName(def)
+	}
+	return "--" + name
+}
+
+// flagNames formats the named arguments with flagName, separated by " | "
+// since the canonical form itself contains a comma
+func (p *Parser) flagNames(names []string) string {
+	formatted := make([]string, len(names))
+	for i, name := range names {
+		formatted[i] = p.flagNameOf(name)
+	}
+	return strings.Join(formatted, " | ")
+}
+
+// describeValues explains in words how many values of which type an argument takes
+func describeValues(def ArgDef) string {
+	lo, hi := def.MinArgs, maxArgs(def)
+	switch {
+	case hi < 0:
+		return fmt.Sprintf("Takes %d or more %s values", lo, valueType(def))
+	case lo == hi && hi > 1:
+		return fmt.Sprintf("Takes exactly %d %s values", hi, valueType(def))
+	case lo > 0 && hi > 1:
+		return fmt.Sprintf("Takes %d to %d %s values", lo, hi, valueType(def))
+	case hi > 1:
+		return fmt.Sprintf("Takes up to %d %s values", hi, valueType(def))
+	}
+	article := "a"
+	if valueType(def) == Int {
+		article = "an"
+	}
+	return fmt.Sprintf("Takes %s %s value", article, valueType(def))
+}
+
+// accessibleFromEnv reports whether the accessible help layout was requested
+// through the environment
+func accessibleFromEnv() bool {
+	v := os.Getenv(AccessibleEnv)
+	return v != "" && v != "0"
+}
+
+// commandUsage returns the Usage of the command of p, followed by its DocsURL
+func (p *Parser) commandUsage() string {
+	if p.command.DocsURL == "" {
+		return p.command.Usage
+	}
+	return strings.TrimSpace(p.command.Usage + " (see " + p.command.DocsURL + ")")
+}
@@ -0,0 +1,283 @@
+package uargs_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestAccessibleUsage tests the screen-reader-friendly help layout
+func TestAccessibleUsage(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "input", Short: "i", Usage: "Input file path", Required: true, Type: uargs.String},
+	}
+
+	parser := uargs.NewParser(args, uargs.WithAccessibleUsage())
+	want := "Usage:\n\nOption --input, short form -i. Required. Takes a string value. Input file path.\n"
+	if got := parser.Usage(); got != want {
+		t.Errorf("Expected accessible usage %q, got %q", want, got)
+	}
+
+	args = []uargs.ArgDef{
+		{Name: "coords", Usage: "Coordinates", NumArgs: 2, Type: uargs.Float},
+	}
+	t.Setenv(uargs.AccessibleEnv, "1")
+	got := uargs.NewParser(args).Usage()
+	if !strings.Contains(got, "Option --coords. Optional. Takes up to 2 float values. Coordinates.") {
+		t.Errorf("Expected environment to select accessible usage, got %q", got)
+	}
+
+	args = []uargs.ArgDef{
+		{Name: "output", Short: "o", Usage: "Output file", Type: uargs.String, RequiredIfGiven: []string{"input", "url"}, ConflictsWith: []string{"quiet"}},
+		{Name: "input", Short: "i", Usage: "Input file", Type: uargs.String},
+		{Name: "url", Usage: "Input URL", Type: uargs.String},
+		{Name: "quiet", Short: "q", Usage: "No output", NumArgs: 0, Type: uargs.String},
+	}
+	got = uargs.NewParser(args).Usage()
+	if !strings.Contains(got, "Required when -i, --input | --url is given. Cannot be used together with -q, --quiet.") {
+		t.Errorf("Expected related flags with their short names, got %q", got)
+	}
+}
+
+// TestFlagFormatting tests that flags are shown as "-s, --name" in help and errors
+func TestFlagFormatting(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "threshold", Short: "th", Usage: "Threshold", Type: uargs.Float},
+		{Name: "count", Short: "c", Usage: "Count", Type: uargs.Int},
+		{Name: "verbose", Usage: "Verbose output", Type: uargs.String},
+	}
+	parser := uargs.NewParser(args)
+
+	usage := parser.Usage()
+	for _, want := range []string{
+		"  -th, --threshold FLOAT  Threshold\n",
+		"  -c,  --count INT        Count\n",
+		"       --verbose STRING   Verbose output\n",
+	} {
+		if !strings.Contains(usage, want) {
+			t.Errorf("Expected usage line %q, got %q", want, usage)
+		}
+	}
+
+	_, err := parser.ParseArgs([]string{"--count", "1", "-c", "2"})
+	if err == nil || err.Error() != "duplicate argument -c, --count" {
+		t.Errorf("Expected duplicate error with canonical flag name, got %v", err)
+	}
+	_, err = parser.ParseArgs([]string{"-th", "x"})
+	if err == nil || err.Error() != "-th, --threshold expects float, got 'x'" {
+		t.Errorf("Expected type error with canonical flag name, got %v", err)
+	}
+}
+
+// TestExample tests per-argument examples in help and value errors
+func TestExample(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "coords", Usage: "Coordinates", NumArgs: 2, MinArgs: 2, Type: uargs.Float, Example: "--coords 10.5 20.3"},
+		{Name: "name", Usage: "Name", Type: uargs.String},
+	}
+	parser := uargs.NewParser(args)
+
+	usage := parser.Usage()
+	if !strings.Contains(usage, "  --coords FLOAT FLOAT  Coordinates\n                        e.g. --coords 10.5 20.3\n") {
+		t.Errorf("Expected example under --coords, got %q", usage)
+	}
+	if !strings.Contains(parser.AccessibleUsage(), "Coordinates. For example: --coords 10.5 20.3.") {
+		t.Errorf("Expected example in accessible usage, got %q", parser.AccessibleUsage())
+	}
+
+	_, err := parser.ParseArgs([]string{"--coords", "10.5", "north"})
+	if err == nil || err.Error() != "--coords expects float, got 'north', e.g. --coords 10.5 20.3" {
+		t.Errorf("Expected type error with example, got %v", err)
+	}
+	_, err = parser.ParseArgs([]string{"--coords", "10.5"})
+	if err == nil || err.Error() != "--coords expects at least 2 values, got 1, e.g. --coords 10.5 20.3" {
+		t.Errorf("Expected arity error with example, got %v", err)
+	}
+}
+
+// TestUsageOrder tests that usage lists arguments in a stable order
+func TestUsageOrder(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "zone", Usage: "Zone", Type: uargs.String},
+		{Name: "address", Usage: "Address", Type: uargs.String, Persistent: true},
+		{Name: "mode", Usage: "Mode", Type: uargs.String},
+	}
+	position := func(usage string, names ...string) []int {
+		var positions []int
+		for _, name := range names {
+			positions = append(positions, strings.Index(usage, "--"+name))
+		}
+		return positions
+	}
+
+	// Test case 1: Arguments keep their definition order, on every call
+	parser := uargs.NewParser(args)
+	serve := parser.AddCommand(uargs.Command{Name: "serve", Usage: "Serve", Args: []uargs.ArgDef{
+		{Name: "workers", Usage: "Workers", Type: uargs.Int},
+		{Name: "bind", Usage: "Bind", Type: uargs.String},
+	}})
+	usage := parser.Usage()
+	if p := position(usage, "zone", "address", "mode"); !(p[0] < p[1] && p[1] < p[2]) {
+		t.Errorf("Expected zone, address, mode in definition order, got:\n%s", usage)
+	}
+	for i := 0; i < 20; i++ {
+		if again := parser.Usage(); again != usage {
+			t.Fatalf("Expected the same usage on every call, got:\n%s\nthen:\n%s", usage, again)
+		}
+	}
+
+	// Test case 2: Inherited persistent arguments follow the command's own
+	usage = serve.Usage()
+	if p := position(usage, "workers", "bind", "address"); !(p[0] < p[1] && p[1] < p[2]) {
+		t.Errorf("Expected workers, bind, address, got:\n%s", usage)
+	}
+
+	// Test case 3: WithSortedUsage lists arguments alphabetically
+	parser = uargs.NewParser(args, uargs.WithSortedUsage())
+	serve = parser.AddCommand(uargs.Command{Name: "serve", Usage: "Serve", Args: []uargs.ArgDef{
+		{Name: "workers", Usage: "Workers", Type: uargs.Int},
+		{Name: "bind", Usage: "Bind", Type: uargs.String},
+	}})
+	usage = parser.Usage()
+	if p := position(usage, "address", "mode", "zone"); !(p[0] < p[1] && p[1] < p[2]) {
+		t.Errorf("Expected address, mode, zone, got:\n%s", usage)
+	}
+	usage = serve.AccessibleUsage()
+	if p := position(usage, "address", "bind", "workers"); !(p[0] < p[1] && p[1] < p[2]) {
+		t.Errorf("Expected address, bind, workers, got:\n%s", usage)
+	}
+}
+
+// TestUsageAnnotations tests the value types and required and default notes
+// shown in usage
+func TestUsageAnnotations(t *testing.T) {
+	parser := uargs.NewParser([]uargs.ArgDef{
+		{Name: "port", Short: "p", Usage: "Port", Type: uargs.Int, Default: 8080},
+		{Name: "user", Usage: "User name", Required: true},
+		{Name: "coords", Usage: "Coordinates", Type: uargs.Float, NumArgs: 2},
+		{Name: "files", Usage: "Input files", Type: uargs.File, Greedy: true},
+		{Name: "tags", Usage: "Tags", MinArgs: 2, MaxArgs: -1},
+	})
+	usage := parser.Usage()
+
+	// Test case 1: Each line shows the value type, repeated by arity, and the notes
+	expected := "Usage:\n" +
+		"  -p, --port INT               Port (default: 8080)\n" +
+		"      --user STRING            User name (required)\n" +
+		"      --coords FLOAT FLOAT     Coordinates\n" +
+		"      --files FILE...          Input files\n" +
+		"      --tags STRING STRING...  Tags\n"
+	if usage != expected {
+		t.Errorf("Expected usage:\n%s\ngot:\n%s", expected, usage)
+	}
+}
+
+// TestMetavar tests naming argument values in usage
+func TestMetavar(t *testing.T) {
+	parser := uargs.NewParser([]uargs.ArgDef{
+		{Name: "input", Usage: "Input", Type: uargs.File, Metavar: "PATH"},
+		{Name: "coords", Usage: "Coordinates", Type: uargs.Float, NumArgs: 2, Metavar: "X Y"},
+		{Name: "ids", Usage: "IDs", Type: uargs.Int, NumArgs: 3, Metavar: "ID"},
+		{Name: "hosts", Usage: "Hosts", Greedy: true, Metavar: "HOST"},
+	})
+
+	// Test case 1: A single name is repeated by arity, several names are shown as given
+	expected := "Usage:\n" +
+		"  --input PATH     Input\n" +
+		"  --coords X Y     Coordinates\n" +
+		"  --ids ID ID ID   IDs\n" +
+		"  --hosts HOST...  Hosts\n"
+	if usage := parser.Usage(); usage != expected {
+		t.Errorf("Expected usage:\n%s\ngot:\n%s", expected, usage)
+	}
+
+	// Test case 2: Several names must match the number of values
+	_, err := uargs.NewParserE([]uargs.ArgDef{{Name: "coords", Usage: "Coordinates", NumArgs: 2, Metavar: "X Y Z"}})
+	if err == nil || err.Error() != `--coords needs a single Metavar or one name per value, got "X Y Z"` {
+		t.Errorf("Expected an error about the Metavar names, got %v", err)
+	}
+}
+
+// TestUsageArgGroups tests listing arguments under Group headings
+func TestUsageArgGroups(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "verbose", Short: "v", Usage: "Verbose output"},
+		{Name: "host", Usage: "Host", Group: "Connection options"},
+		{Name: "format", Usage: "Format", Group: "Output options"},
+		{Name: "port", Short: "p", Usage: "Port", Type: uargs.Int, Group: "Connection options"},
+		{Name: "color", Usage: "Color", Group: "Output options"},
+	}
+
+	// Test case 1: Ungrouped arguments come first, then groups in declared order
+	expected := "Usage:\n" +
+		"  -v, --verbose STRING  Verbose output\n" +
+		"\nConnection options:\n" +
+		"      --host STRING     Host\n" +
+		"  -p, --port INT        Port\n" +
+		"\nOutput options:\n" +
+		"      --format STRING   Format\n" +
+		"      --color STRING    Color\n"
+	if usage := uargs.NewParser(args).Usage(); usage != expected {
+		t.Errorf("Expected usage:\n%s\ngot:\n%s", expected, usage)
+	}
+
+	// Test case 2: Sorting applies within groups and keeps the group order
+	usage := uargs.NewParser(args, uargs.WithSortedUsage()).Usage()
+	if !strings.Contains(usage, "\nOutput options:\n      --color STRING    Color\n      --format STRING   Format\n") || strings.Index(usage, "Connection") > strings.Index(usage, "Output") {
+		t.Errorf("Expected sorted arguments within declared groups, got:\n%s", usage)
+	}
+
+	// Test case 3: Accessible usage names the group
+	if got := uargs.NewParser(args).AccessibleUsage(); !strings.Contains(got, "Option --host. Optional. Takes a string value. Listed under Connection options. Host.") {
+		t.Errorf("Expected the group in accessible usage, got %q", got)
+	}
+}
+
+// TestCommandUsage tests the help text of nested commands
+func TestCommandUsage(t *testing.T) {
+	parser := uargs.NewParser([]uargs.ArgDef{
+		{Name: "verbose", Short: "v", Usage: "Verbosity", Type: uargs.Int, Persistent: true},
+	})
+	remote := parser.AddCommand(uargs.Command{Name: "remote", Usage: "Manage remotes"})
+	add := remote.AddCommand(uargs.Command{Name: "add", Usage: "Add a remote", Args: []uargs.ArgDef{
+		{Name: "url", Usage: "Remote URL", Type: uargs.String, Required: true},
+	}})
+	remote.AddCommand(uargs.Command{Name: "remove", Usage: "Remove a remote"})
+	tidy := remote.AddCommand(uargs.Command{Name: "tidy", Usage: "Tidy remotes"})
+	tidy.AddCommand(uargs.Command{Name: "now", Usage: "Tidy now"})
+
+	// Test case 1: The description, flags, and commands are separated by one blank line
+	tests := []struct {
+		parser   *uargs.Parser
+		expected string
+	}{
+		{remote, "Usage: remote\n" +
+			"\nManage remotes\n" +
+			"\n  -v, --verbose INT  Verbosity\n" +
+			"\nCommands:\n" +
+			"  add     Add a remote\n" +
+			"  remove  Remove a remote\n" +
+			"  tidy    Tidy remotes\n"},
+		{add, "Usage: remote add\n" +
+			"\nAdd a remote\n" +
+			"\n      --url STRING   Remote URL (required)\n" +
+			"  -v, --verbose INT  Verbosity\n"},
+	}
+	for _, test := range tests {
+		if usage := test.parser.Usage(); usage != test.expected {
+			t.Errorf("Expected usage:\n%s\ngot:\n%s", test.expected, usage)
+		}
+	}
+
+	// Test case 2: Without flags, one blank line separates the description from the commands
+	tidyUsage := "Usage: remote tidy\n\nTidy remotes\n\n  -v, --verbose INT  Verbosity\n\nCommands:\n  now  Tidy now\n"
+	noFlags := uargs.NewParser(nil).AddCommand(uargs.Command{Name: "tidy", Usage: "Tidy remotes"})
+	noFlags.AddCommand(uargs.Command{Name: "now", Usage: "Tidy now"})
+	if usage := noFlags.Usage(); usage != "Usage: tidy\n\nTidy remotes\n\nCommands:\n  now  Tidy now\n" {
+		t.Errorf("Expected one blank line before the commands, got %q", usage)
+	}
+	if usage := tidy.Usage(); usage != tidyUsage {
+		t.Errorf("Expected usage %q, got %q", tidyUsage, usage)
+	}
+}
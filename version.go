@@ -0,0 +1,102 @@
+package uargs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"strings"
+)
+
+// VersionName is the name of the flag and command registered by WithVersion
+const VersionName = "version"
+
+// VersionInfo describes the build of a program, as shown by --version
+type VersionInfo struct {
+	// Version is the release, such as "v1.4.2"
+	Version string `json:"version"`
+	// Commit is the revision the program was built from
+	Commit string `json:"commit,omitempty"`
+	// Date is when the program was built or its revision was committed
+	Date string `json:"date,omitempty"`
+}
+
+// BuildVersion returns the version information recorded by the Go toolchain:
+// the module version and the VCS revision and commit time. The revision has a
+// "-dirty" suffix if the working tree had changes. Fields that are not
+// recorded are left empty.
+func BuildVersion() VersionInfo {
+	var info VersionInfo
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	info.Version = build.Main.Version
+	dirty := false
+	for _, s := range build.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			info.Commit = s.Value
+		case "vcs.time":
+			info.Date = s.Value
+		case "vcs.modified":
+			dirty = s.Value == "true"
+		}
+	}
+	if dirty && info.Commit != "" {
+		info.Commit += "-dirty"
+	}
+	return info
+}
+
+// isVersionFlag reports whether the token arg requests the version, as
+// --version or -V. An argument named version or with the short name V takes
+// precedence.
+func (p *Parser) isVersionFlag(arg string) bool {
+	if p.version == nil {
+		return false
+	}
+	if arg == "--"+VersionName {
+		_, defined := p.defs[VersionName]
+		return !defined
+	}
+	if arg == "-V" {
+		_, defined := p.shortToLong["V"]
+		return !defined
+	}
+	return false
+}
+
+// isVersionCommand reports whether the word arg is the version command, which
+// exists for parsers with subcommands unless one of them is called version
+func (p *Parser) isVersionCommand(arg string) bool {
+	return p.version != nil && arg == VersionName && len(p.commands) > 0 && p.findCommand(VersionName) == nil
+}
+
+// writeVersion writes the version to the output set with WithOutput, as JSON
+// if argv holds --json. It returns ErrVersion once the version is written.
+func (p *Parser) writeVersion(argv []string) error {
+	info := *p.version
+	for _, arg := range argv {
+		if arg == "--json" {
+			enc := json.NewEncoder(p.output)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(info); err != nil {
+				return err
+			}
+			return ErrVersion
+		}
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Version: %s\n", info.Version)
+	if info.Commit != "" {
+		fmt.Fprintf(&b, "Commit: %s\n", info.Commit)
+	}
+	if info.Date != "" {
+		fmt.Fprintf(&b, "Built: %s\n", info.Date)
+	}
+	if _, err := io.WriteString(p.output, b.String()); err != nil {
+		return err
+	}
+	return ErrVersion
+}
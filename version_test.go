@@ -0,0 +1,62 @@
+package uargs_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestVersion tests --version, -V, and the version command
+func TestVersion(t *testing.T) {
+	var out strings.Builder
+	info := uargs.VersionInfo{Version: "v1.4.2", Commit: "abc123", Date: "2026-01-02T03:04:05Z"}
+	parser := uargs.NewParser([]uargs.ArgDef{
+		{Name: "port", Usage: "Port", Type: uargs.Int, Required: true},
+	}, uargs.WithVersion(info), uargs.WithOutput(&out))
+	parser.AddCommand(uargs.Command{Name: "serve", Usage: "Serve"})
+
+	// Test case 1: Text output, without checking required arguments
+	for _, argv := range [][]string{{"--version"}, {"-V"}, {"version"}} {
+		out.Reset()
+		if _, err := parser.ParseArgs(argv); !errors.Is(err, uargs.ErrVersion) {
+			t.Errorf("%v: expected ErrVersion, got %v", argv, err)
+		}
+		if want := "Version: v1.4.2\nCommit: abc123\nBuilt: 2026-01-02T03:04:05Z\n"; out.String() != want {
+			t.Errorf("%v: expected %q, got %q", argv, want, out.String())
+		}
+	}
+	if code := parser.ExitCode(uargs.ErrVersion); code != 0 {
+		t.Errorf("Expected exit code 0, got %d", code)
+	}
+
+	// Test case 2: JSON output
+	out.Reset()
+	if _, err := parser.ParseArgs([]string{"version", "--json"}); !errors.Is(err, uargs.ErrVersion) {
+		t.Fatalf("Expected ErrVersion, got %v", err)
+	}
+	var got uargs.VersionInfo
+	if err := json.Unmarshal([]byte(out.String()), &got); err != nil || got != info {
+		t.Errorf("Expected %+v, got %+v (%v)", info, got, err)
+	}
+
+	// Test case 3: Missing fields come from the build information
+	out.Reset()
+	built := uargs.NewParser(nil, uargs.WithVersion(uargs.VersionInfo{}), uargs.WithOutput(&out))
+	if _, err := built.ParseArgs([]string{"--version"}); !errors.Is(err, uargs.ErrVersion) {
+		t.Fatalf("Expected ErrVersion, got %v", err)
+	}
+	if want := "Version: " + uargs.BuildVersion().Version + "\n"; !strings.HasPrefix(out.String(), want) {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+
+	// Test case 4: Defined arguments take precedence
+	own := uargs.NewParser([]uargs.ArgDef{
+		{Name: "verbose", Short: "V", Usage: "Verbose", Type: uargs.Int},
+	}, uargs.WithVersion(info))
+	if _, err := own.ParseArgs([]string{"-V", "2"}); err != nil {
+		t.Errorf("Expected -V to be the defined argument, got %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+package uargs_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestUsageDisplayWidth tests that usage columns align for wide, combining,
+// and bidirectional characters
+func TestUsageDisplayWidth(t *testing.T) {
+	args := []uargs.ArgDef{
+		{Name: "名前", Short: "n", Usage: "Wide name", Type: uargs.String},
+		{Name: "cafe\u0301", Short: "c", Usage: "Combining mark", Type: uargs.String},
+		{Name: "\u200fabcd", Short: "r", Usage: "Bidi mark", Type: uargs.String},
+		{Name: "plain", Short: "p", Usage: "Plain ASCII", Type: uargs.String},
+	}
+
+	lines := strings.Split(strings.TrimSpace(uargs.NewParser(args).Usage()), "\n")[1:]
+	want := map[string]string{
+		"Wide name":      "  -n, --名前 STRING   Wide name",
+		"Combining mark": "  -c, --cafe\u0301 STRING   Combining mark",
+		"Bidi mark":      "  -r, --\u200fabcd STRING   Bidi mark",
+		"Plain ASCII":    "  -p, --plain STRING  Plain ASCII",
+	}
+	for _, line := range lines {
+		for usage, expected := range want {
+			if strings.HasSuffix(line, usage) && line != expected {
+				t.Errorf("Expected line %q, got %q", expected, line)
+			}
+		}
+	}
+}
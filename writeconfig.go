@@ -0,0 +1,239 @@
+package uargs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ConfigFormat is a config file format written by WriteConfig
+type ConfigFormat string
+
+const (
+	// FormatJSON writes an object with nested objects for subcommands
+	FormatJSON ConfigFormat = "json"
+	// FormatTOML writes keys with [tables] for subcommands
+	FormatTOML ConfigFormat = "toml"
+	// FormatYAML writes a mapping with nested mappings for subcommands. uargs
+	// does not read YAML, so this is meant for tools that do.
+	FormatYAML ConfigFormat = "yaml"
+)
+
+// WriteConfig writes the values of the last successful parse to a config file
+// at path, so that "mytool --port 9000 --save-config" can bootstrap a config
+// file. The values of the selected subcommands are nested under their names,
+// as WithConfigFile reads them. With explicitOnly, values taken from Default or
+// DefaultFunc are left out. Secret arguments, the --config argument of
+// WithConfigFlag, and standard input given as "-" are never written. An empty
+// format is chosen from the extension of path (.json, .toml, .yaml, or .yml).
+//
+// Example:
+//
+//	if path, ok := parsed["save-config"].(string); ok {
+//		if err := parser.WriteConfig(path, "", true); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+func (p *Parser) WriteConfig(path string, format ConfigFormat, explicitOnly bool) error {
+	if p == nil {
+		return ErrNilParser
+	}
+	if !p.done {
+		return ErrNotParsed
+	}
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".json":
+			format = FormatJSON
+		case ".toml":
+			format = FormatTOML
+		case ".yaml", ".yml":
+			format = FormatYAML
+		default:
+			return fmt.Errorf("%s: unknown config format, expected .json, .toml, .yaml, or .yml", path)
+		}
+	}
+	values, err := p.result.configValues(explicitOnly)
+	if err != nil {
+		return err
+	}
+	var b bytes.Buffer
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(&b)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(values)
+	case FormatTOML:
+		err = writeTOML(&b, nil, values)
+	case FormatYAML:
+		err = writeYAML(&b, "", values)
+	default:
+		return fmt.Errorf("unsupported config format %q", format)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	return os.WriteFile(path, b.Bytes(), 0o644)
+}
+
+// configValues returns the values of r to write to a config file, with the
+// values of the selected subcommand nested under its name
+func (r *Result) configValues(explicitOnly bool) (map[string]interface{}, error) {
+	p := r.parser
+	values := make(map[string]interface{})
+	for name, v := range r.values {
+		def := p.defs[name]
+		switch {
+		case explicitOnly && r.sources[name].Origin == OriginDefault,
+			def.Secret, p.inheritedFlags[name], p.configFlag && name == ConfigName:
+			continue
+		}
+		switch v.(type) {
+		case string, int, float64, []string, []int, []float64:
+			values[name] = v
+		case io.Reader:
+			// Standard input cannot be saved
+		default:
+			return nil, fmt.Errorf("cannot write the value of %s of type %T", flagName(def), v)
+		}
+	}
+	if cmd := r.Command(); cmd != nil {
+		nested, err := cmd.configValues(explicitOnly)
+		if err != nil {
+			return nil, err
+		}
+		if len(nested) > 0 {
+			values[cmd.Name()] = nested
+		}
+	}
+	return values, nil
+}
+
+// sortedConfigKeys returns the keys of m with values and with nested maps,
+// each sorted
+func sortedConfigKeys(m map[string]interface{}) (keys, nested []string) {
+	for key, v := range m {
+		if _, ok := v.(map[string]interface{}); ok {
+			nested = append(nested, key)
+		} else {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	slices.Sort(nested)
+	return keys, nested
+}
+
+// writeTOML writes the values of m as TOML keys, followed by a table for each
+// nested map
+func writeTOML(w io.Writer, table []string, m map[string]interface{}) error {
+	keys, tables := sortedConfigKeys(m)
+	if len(keys) > 0 && len(table) > 0 {
+		fmt.Fprintf(w, "\n[%s]\n", strings.Join(table, "."))
+	}
+	for _, key := range keys {
+		s, err := configLiteral(m[key])
+		if err != nil {
+			return fmt.Errorf("%s: %v", key, err)
+		}
+		fmt.Fprintf(w, "%s = %s\n", configKeyLiteral(key), s)
+	}
+	for _, key := range tables {
+		if err := writeTOML(w, append(table[:len(table):len(table)], configKeyLiteral(key)), m[key].(map[string]interface{})); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// configKeyLiteral quotes key unless it is a bare key, made of letters, digits,
+// _, and -
+func configKeyLiteral(key string) string {
+	for i := 0; i < len(key); i++ {
+		if !isBareKeyChar(key[i]) {
+			return quoteConfigString(key)
+		}
+	}
+	return key
+}
+
+// writeYAML writes the values of m as a YAML mapping indented by indent, with
+// nested maps last
+func writeYAML(w io.Writer, indent string, m map[string]interface{}) error {
+	keys, nested := sortedConfigKeys(m)
+	for _, key := range keys {
+		s, err := configLiteral(m[key])
+		if err != nil {
+			return fmt.Errorf("%s: %v", key, err)
+		}
+		fmt.Fprintf(w, "%s%s: %s\n", indent, configKeyLiteral(key), s)
+	}
+	for _, key := range nested {
+		fmt.Fprintf(w, "%s%s:\n", indent, configKeyLiteral(key))
+		if err := writeYAML(w, indent+"  ", m[key].(map[string]interface{})); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// configLiteral formats a value as a TOML or YAML literal: a double-quoted
+// string, a number, or a flow list of them
+func configLiteral(v interface{}) (string, error) {
+	var items []string
+	switch v := v.(type) {
+	case string:
+		return quoteConfigString(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case float64:
+		return formatConfigFloat(v)
+	case []string:
+		for _, s := range v {
+			items = append(items, quoteConfigString(s))
+		}
+	case []int:
+		for _, n := range v {
+			items = append(items, strconv.Itoa(n))
+		}
+	case []float64:
+		for _, f := range v {
+			s, err := formatConfigFloat(f)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, s)
+		}
+	}
+	return "[" + strings.Join(items, ", ") + "]", nil
+}
+
+// formatConfigFloat formats f with a decimal point or exponent, as TOML
+// requires for floats
+func formatConfigFloat(f float64) (string, error) {
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if strings.ContainsAny(s, "IN") {
+		return "", fmt.Errorf("cannot write %s", s)
+	}
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return s, nil
+}
+
+// quoteConfigString quotes s as a double-quoted string, with the escapes that
+// JSON, TOML, and YAML have in common
+func quoteConfigString(s string) string {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(b.String(), "\n")
+}
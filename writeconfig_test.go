@@ -0,0 +1,89 @@
+package uargs_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/utsav-56/uargs"
+)
+
+// TestWriteConfig tests writing parsed values to config files and reading them back
+func TestWriteConfig(t *testing.T) {
+	defs := []uargs.ArgDef{
+		{Name: "port", Usage: "Port", Type: uargs.Int, Default: 80},
+		{Name: "ratio", Usage: "Ratio", Type: uargs.Float},
+		{Name: "tags", Usage: "Tags", Type: uargs.String, NumArgs: 2},
+		{Name: "name", Usage: "Name", Type: uargs.String},
+		{Name: "token", Usage: "Token", Type: uargs.String, Secret: true},
+		{Name: "verbose", Usage: "Verbosity", Type: uargs.String, Persistent: true},
+	}
+	newParser := func(opts ...uargs.Option) (*uargs.Parser, *uargs.Parser) {
+		parser := uargs.NewParser(defs, append(opts, uargs.WithSecretAdvice(false), uargs.WithConfigFlag())...)
+		serve := parser.AddCommand(uargs.Command{Name: "serve", Usage: "Serve", Args: []uargs.ArgDef{
+			{Name: "workers", Usage: "Workers", Type: uargs.Int, Default: 1},
+		}})
+		return parser, serve
+	}
+	argv := []string{"--ratio", "2", "--tags", "a", "b \"c\"\n", "--name", "x\u2028y", "--token", "secret", "serve", "--workers", "4", "--verbose", "yes"}
+
+	parser, _ := newParser()
+	if err := parser.WriteConfig(filepath.Join(t.TempDir(), "config.json"), "", false); !errors.Is(err, uargs.ErrNotParsed) {
+		t.Errorf("Expected ErrNotParsed before parsing, got %v", err)
+	}
+	expected, err := parser.ParseArgs(argv)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	delete(expected, "token")
+
+	// Test case 1: JSON and TOML files read back to the same values
+	for _, name := range []string{"config.json", "config.toml"} {
+		path := filepath.Join(t.TempDir(), name)
+		if err := parser.WriteConfig(path, "", false); err != nil {
+			t.Fatalf("Unexpected error writing %s: %v", name, err)
+		}
+		reader, serve := newParser(uargs.WithConfigFile(path))
+		parsed, err := reader.ParseArgs([]string{"serve"})
+		if err != nil {
+			t.Fatalf("Unexpected error reading %s: %v", name, err)
+		}
+		delete(parsed, "config")
+		if !reflect.DeepEqual(parsed, expected) {
+			t.Errorf("%s: Expected %v, got %v", name, expected, parsed)
+		}
+		if workers := serve.Result().Map()["workers"]; workers != 4 {
+			t.Errorf("%s: Expected workers=4, got %v", name, workers)
+		}
+	}
+
+	// Test case 2: YAML output, leaving out defaults
+	path := filepath.Join(t.TempDir(), "config.yml")
+	if err := parser.WriteConfig(path, "", true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	yaml := `name: "x\u2028y"
+ratio: 2.0
+tags: ["a", "b \"c\"\n"]
+verbose: "yes"
+serve:
+  workers: 4
+`
+	if string(data) != yaml {
+		t.Errorf("Expected:\n%s\nGot:\n%s", yaml, data)
+	}
+
+	// Test case 3: Unknown extensions need an explicit format
+	if err := parser.WriteConfig(filepath.Join(t.TempDir(), "config.cfg"), "", false); err == nil {
+		t.Errorf("Expected an error for an unknown extension")
+	}
+	if err := parser.WriteConfig(filepath.Join(t.TempDir(), "config.cfg"), uargs.FormatTOML, false); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}